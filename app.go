@@ -28,15 +28,36 @@ type App struct {
 	mutex       sync.RWMutex
 	trayManager *tray.Manager
 	iconData    []byte
+	trayIcons   tray.IconSet // 标准状态图标套
+	trayIconsCB tray.IconSet // 色盲友好状态图标套
 
 	// 缓存的状态 (托盘和前端随时读取)
 	isConnected      bool
 	currentTemp      types.TemperatureData
 	currentFan       *types.FanData
 	autoControlState bool
+	boostEndsAt      time.Time // 狂暴模式结束时刻，零值表示未开启
+	thermalAlert     bool      // 核心上报的持续热降频告警，断开/重连时清除
 
 	// 自启动管理器，启动时初始化一次
 	autostartManager *autostart.Manager
+
+	// 启动参数指定的目标页面(--view=...)，startup时通知前端跳转
+	pendingView string
+	// 启动参数携带的通知动作(--action=...)，startup连上核心后回传执行
+	pendingAction string
+
+	// 远程核心模式：非空时只拨该TCP端点，跳过本机核心的定位与拉起
+	remoteCoreAddr  string
+	remoteCoreToken string
+
+	// 核心离线期间的本地编辑草稿，核心恢复连接后可一键应用
+	draftMutex  sync.Mutex
+	draftConfig *types.AppConfig
+	draftCurve  []types.FanCurvePoint
+
+	// 前端错误上报的防护状态（限流/去重/截断计数）
+	feGuard frontendLogGuard
 }
 
 // 重新导出类型，供Wails生成TypeScript绑定
@@ -127,10 +148,39 @@ func (a *App) startup(ctx context.Context) {
 	adapter := &trayLoggerAdapter{sugar: guiLogger, installDir: config.GetInstallDir()}
 	a.autostartManager = autostart.NewManager(adapter, config.GetInstallDir())
 
-	// 连接到后台核心服务
-	if err := a.ipcClient.Connect(); err != nil {
-		guiLogger.Errorf("连接核心服务失败: %v", err)
-		runtime.EventsEmit(ctx, "core-service-error", "无法连接到核心服务，请检查服务是否运行")
+	// 远程核心模式：命令行参数优先，其次GUI本地设置。启用后只拨远程TCP端点，
+	// 本桌面仅作为另一台机器上核心的控制面板
+	if a.remoteCoreAddr == "" {
+		if settings := loadRemoteCoreSettings(); settings.Addr != "" {
+			a.remoteCoreAddr = settings.Addr
+			if a.remoteCoreToken == "" {
+				a.remoteCoreToken = settings.Token
+			}
+		}
+	}
+	if a.remoteCoreAddr != "" {
+		guiLogger.Infof("远程核心模式: %s", a.remoteCoreAddr)
+		a.ipcClient.SetEndpoints(ipc.RemoteEndpoints(a.remoteCoreAddr))
+	}
+
+	// 连接到后台核心服务，失败时按搜索路径定位并尝试拉起核心后重试一次。
+	// 远程模式下跳过拉起——远程核心的生命周期由其所在机器负责
+	connectErr := a.ipcClient.Connect()
+	if connectErr != nil && a.remoteCoreAddr == "" {
+		if coreErr := a.ensureCoreServiceRunning(); coreErr != nil {
+			runtime.EventsEmit(ctx, "core-service-missing", coreErr)
+		} else {
+			connectErr = a.ipcClient.Connect()
+		}
+	}
+
+	if connectErr != nil {
+		guiLogger.Errorf("连接核心服务失败: %v", connectErr)
+		errMsg := "无法连接到核心服务，请检查服务是否运行"
+		if a.remoteCoreAddr != "" {
+			errMsg = fmt.Sprintf("无法连接到远程核心 %s，请检查网络与远程服务状态", a.remoteCoreAddr)
+		}
+		runtime.EventsEmit(ctx, "core-service-error", errMsg)
 
 		go func() {
 			defaultCfg := types.GetDefaultConfig(false)
@@ -141,6 +191,8 @@ func (a *App) startup(ctx context.Context) {
 		guiLogger.Info("已成功连接到核心服务 IPC 管道")
 		a.ipcClient.SetEventHandler(a.handleCoreEvent)
 
+		a.handshakeClientMode()
+
 		// 启动时主动拉取一次配置，同步状态
 		cfg := a.GetConfig()
 		status := a.GetDeviceStatus()
@@ -163,6 +215,24 @@ func (a *App) startup(ctx context.Context) {
 	// 启动连接健康检查
 	go a.startConnectionHealthCheck()
 
+	// toast激活拉起新进程时携带的通知动作，连上核心后回传执行
+	if a.pendingAction != "" {
+		action := a.pendingAction
+		go func() {
+			if err := a.TriggerNotificationAction(action); err != nil {
+				guiLogger.Errorf("通知动作 %s 执行失败: %v", action, err)
+			}
+		}()
+	}
+
+	// 核心拉起GUI时可通过 --view= 指定落地页面（如通知动作"点击查看图表"）
+	if a.pendingView != "" {
+		view := a.pendingView
+		go func() {
+			runtime.EventsEmit(ctx, "navigate", view)
+		}()
+	}
+
 	guiLogger.Info("=== BS2PRO GUI 启动完成 ===")
 }
 
@@ -170,6 +240,7 @@ func (a *App) startup(ctx context.Context) {
 func (a *App) InitSystemTray() {
 	trayAdapter := &trayLoggerAdapter{sugar: guiLogger, installDir: config.GetInstallDir()}
 	a.trayManager = tray.NewManager(trayAdapter, a.iconData)
+	a.trayManager.SetIconSets(a.trayIcons, a.trayIconsCB)
 
 	a.trayManager.SetCallbacks(
 		func() {
@@ -188,15 +259,37 @@ func (a *App) InitSystemTray() {
 			// 点击关闭核心：停止核心服务
 			a.StopCoreService()
 		},
-		func() bool {
-			// 切换智能变频
+		func() {
+			// 切换智能变频：异步下发，结果以核心回传的配置事件为准
 			a.mutex.RLock()
 			currentState := a.autoControlState
 			a.mutex.RUnlock()
 
 			newState := !currentState
-			go a.SetAutoControl(newState)
-			return newState
+			go func() {
+				if err := a.SetAutoControl(newState); err != nil {
+					guiLogger.Errorf("托盘切换智能变频失败: %v", err)
+				}
+			}()
+		},
+		func() {
+			// 一键狂暴：异步下发，倒计时以核心回传的max-boost-changed事件为准。
+			// 狂暴进行中再次点击视为提前结束。
+			a.mutex.RLock()
+			boosting := !a.boostEndsAt.IsZero() && time.Now().Before(a.boostEndsAt)
+			a.mutex.RUnlock()
+
+			go func() {
+				var err error
+				if boosting {
+					err = a.CancelMaxBoost()
+				} else {
+					err = a.MaxBoost(0)
+				}
+				if err != nil {
+					guiLogger.Errorf("托盘切换狂暴模式失败: %v", err)
+				}
+			}()
 		},
 		func() tray.Status {
 			// 为托盘提供状态
@@ -206,12 +299,20 @@ func (a *App) InitSystemTray() {
 			if a.currentFan != nil {
 				rpm = uint16(a.currentFan.CurrentRPM)
 			}
+			boostRemaining := 0
+			if !a.boostEndsAt.IsZero() {
+				if r := int(time.Until(a.boostEndsAt) / time.Second); r > 0 {
+					boostRemaining = r
+				}
+			}
 			return tray.Status{
 				Connected:        a.isConnected,
 				CPUTemp:          a.currentTemp.CPUTemp,
 				GPUTemp:          a.currentTemp.GPUTemp,
 				CurrentRPM:       rpm,
 				AutoControlState: a.autoControlState,
+				BoostRemaining:   boostRemaining,
+				Alert:            a.thermalAlert,
 			}
 		},
 	)
@@ -258,12 +359,14 @@ func (a *App) handleCoreEvent(event ipc.Event) {
 		json.Unmarshal(event.Data, &deviceInfo)
 		a.mutex.Lock()
 		a.isConnected = true
+		a.thermalAlert = false
 		a.mutex.Unlock()
 		runtime.EventsEmit(a.ctx, "device-connected", deviceInfo)
 
 	case ipc.EventDeviceDisconnected:
 		a.mutex.Lock()
 		a.isConnected = false
+		a.thermalAlert = false
 		a.mutex.Unlock()
 		runtime.EventsEmit(a.ctx, "device-disconnected", nil)
 
@@ -287,6 +390,10 @@ func (a *App) handleCoreEvent(event ipc.Event) {
 			a.autoControlState = cfg.AutoControl
 			a.mutex.Unlock()
 
+			if a.trayManager != nil {
+				a.trayManager.SetIconTheme(cfg.TrayIconTheme)
+			}
+
 			if a.ctx != nil {
 				// 发送恢复信号给前端
 				runtime.EventsEmit(a.ctx, "core-service-connected", nil)
@@ -296,6 +403,11 @@ func (a *App) handleCoreEvent(event ipc.Event) {
 				if a.isConnected {
 					runtime.EventsEmit(a.ctx, "device-connected", status["currentData"])
 				}
+
+				// 存在离线编辑草稿时提示前端，由用户决定应用或丢弃
+				if a.HasPendingChanges() {
+					runtime.EventsEmit(a.ctx, "pending-changes-available", nil)
+				}
 			}
 		}()
 
@@ -318,11 +430,87 @@ func (a *App) handleCoreEvent(event ipc.Event) {
 			a.mutex.Lock()
 			a.autoControlState = cfg.AutoControl
 			a.mutex.Unlock()
+			if a.trayManager != nil {
+				a.trayManager.SetIconTheme(cfg.TrayIconTheme)
+			}
 			runtime.EventsEmit(a.ctx, "config-update", cfg)
 		}
 
+	case ipc.EventThrottlingDetected:
+		// 记录告警状态供托盘切换告警图标，并转发给前端提示
+		var detail map[string]any
+		json.Unmarshal(event.Data, &detail)
+		a.mutex.Lock()
+		a.thermalAlert = true
+		a.mutex.Unlock()
+		runtime.EventsEmit(a.ctx, "throttling-detected", detail)
+
+	case ipc.EventNotification:
+		// 核心下发的系统通知（已经过专注助手过滤），转发给前端弹toast
+		var notification map[string]any
+		if err := json.Unmarshal(event.Data, &notification); err == nil {
+			runtime.EventsEmit(a.ctx, "notification", notification)
+		}
+
+	case ipc.EventMaxBoostChanged:
+		var status ipc.MaxBoostStatus
+		if err := json.Unmarshal(event.Data, &status); err == nil {
+			a.mutex.Lock()
+			if status.Active {
+				a.boostEndsAt = time.Unix(status.EndsAtUnix, 0)
+			} else {
+				a.boostEndsAt = time.Time{}
+			}
+			a.mutex.Unlock()
+			runtime.EventsEmit(a.ctx, "max-boost-changed", status)
+		}
+
+	case ipc.EventControlStateChanged:
+		var info ipc.ControlStateInfo
+		if err := json.Unmarshal(event.Data, &info); err == nil {
+			runtime.EventsEmit(a.ctx, "control-state-changed", info)
+		}
+
+	case ipc.EventCurveLivePoint:
+		var point ipc.CurveLivePoint
+		if err := json.Unmarshal(event.Data, &point); err == nil {
+			runtime.EventsEmit(a.ctx, "curve-live-point", point)
+		}
+
+	case ipc.EventThermalProfileProgress:
+		var progress ipc.ThermalProfileProgress
+		if err := json.Unmarshal(event.Data, &progress); err == nil {
+			runtime.EventsEmit(a.ctx, "thermal-profile-progress", progress)
+		}
+
+	case ipc.EventThermalProfileDone:
+		var result ipc.ThermalProfileResult
+		if err := json.Unmarshal(event.Data, &result); err == nil {
+			runtime.EventsEmit(a.ctx, "thermal-profile-done", result)
+		}
+
+	case ipc.EventCurveABTestProgress:
+		var progress ipc.CurveABTestProgress
+		if err := json.Unmarshal(event.Data, &progress); err == nil {
+			runtime.EventsEmit(a.ctx, "curve-ab-test-progress", progress)
+		}
+
+	case ipc.EventOperationProgress:
+		var progress ipc.OperationProgress
+		if err := json.Unmarshal(event.Data, &progress); err == nil {
+			runtime.EventsEmit(a.ctx, "operation-progress", progress)
+		}
+
+	case ipc.EventCurveABTestDone:
+		var result ipc.CurveABTestResult
+		if err := json.Unmarshal(event.Data, &result); err == nil {
+			runtime.EventsEmit(a.ctx, "curve-ab-test-done", result)
+		}
+
 	case "show-window":
-		a.ShowWindow()
+		var payload map[string]string
+		json.Unmarshal(event.Data, &payload)
+		a.NavigateTo(payload["view"])
 	}
 }
 
@@ -368,12 +556,15 @@ func (a *App) GetConfig() AppConfig {
 func (a *App) UpdateConfig(cfg AppConfig) error {
 	resp, err := a.sendRequest(ipc.ReqUpdateConfig, cfg)
 	if err != nil {
+		// 核心不可达时保留为本地草稿，避免用户编辑丢失
+		a.saveDraftConfig(cfg)
 		return err
 	}
 	if resp == nil || !resp.Success {
 		if resp != nil {
 			return fmt.Errorf("%s", resp.Error)
 		}
+		a.saveDraftConfig(cfg)
 		return fmt.Errorf("服务响应为空")
 	}
 	return nil
@@ -382,17 +573,125 @@ func (a *App) UpdateConfig(cfg AppConfig) error {
 func (a *App) SetFanCurve(curve []FanCurvePoint) error {
 	resp, err := a.sendRequest(ipc.ReqSetFanCurve, curve)
 	if err != nil {
+		// 核心不可达时保留为本地草稿，避免用户编辑丢失
+		a.saveDraftCurve(curve)
 		return err
 	}
 	if resp == nil || !resp.Success {
 		if resp != nil {
 			return fmt.Errorf("%s", resp.Error)
 		}
+		a.saveDraftCurve(curve)
 		return fmt.Errorf("服务响应为空")
 	}
 	return nil
 }
 
+// ExportCurveFile 把当前生效曲线导出为.bs2curve分享文件内容（前端负责保存对话框）
+func (a *App) ExportCurveFile(name, author, description string) (string, error) {
+	resp, err := a.sendRequest(ipc.ReqExportCurveFile, ipc.ExportCurveFileParams{
+		Name:        name,
+		Author:      author,
+		Description: description,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return "", fmt.Errorf("%s", resp.Error)
+		}
+		return "", fmt.Errorf("服务响应为空")
+	}
+	var content string
+	if err := json.Unmarshal(resp.Data, &content); err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+// ImportCurveFile 解析并校验.bs2curve分享文件，返回曲线点与元数据供前端预览，
+// 用户确认后再经SetFanCurve应用
+func (a *App) ImportCurveFile(content string) (map[string]any, error) {
+	resp, err := a.sendRequest(ipc.ReqImportCurveFile, ipc.ImportCurveFileParams{Content: content})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return nil, fmt.Errorf("%s", resp.Error)
+		}
+		return nil, fmt.Errorf("服务响应为空")
+	}
+	var result map[string]any
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// saveDraftConfig 暂存核心离线期间的配置编辑
+func (a *App) saveDraftConfig(cfg AppConfig) {
+	a.draftMutex.Lock()
+	defer a.draftMutex.Unlock()
+	cfgCopy := cfg
+	a.draftConfig = &cfgCopy
+	guiLogger.Info("核心服务不可达，配置修改已暂存为本地草稿")
+}
+
+// saveDraftCurve 暂存核心离线期间的风扇曲线编辑
+func (a *App) saveDraftCurve(curve []FanCurvePoint) {
+	a.draftMutex.Lock()
+	defer a.draftMutex.Unlock()
+	a.draftCurve = append([]FanCurvePoint(nil), curve...)
+	guiLogger.Info("核心服务不可达，风扇曲线修改已暂存为本地草稿")
+}
+
+// HasPendingChanges 是否存在待应用的离线编辑草稿
+func (a *App) HasPendingChanges() bool {
+	a.draftMutex.Lock()
+	defer a.draftMutex.Unlock()
+	return a.draftConfig != nil || a.draftCurve != nil
+}
+
+// ApplyPendingChanges 将离线编辑草稿重放到核心（先配置后曲线），
+// 应用成功的部分即时清除，失败的部分保留等待下次重试
+func (a *App) ApplyPendingChanges() error {
+	a.draftMutex.Lock()
+	cfg := a.draftConfig
+	curve := a.draftCurve
+	a.draftMutex.Unlock()
+
+	if cfg != nil {
+		if err := a.UpdateConfig(*cfg); err != nil {
+			return fmt.Errorf("应用暂存配置失败: %v", err)
+		}
+		a.draftMutex.Lock()
+		a.draftConfig = nil
+		a.draftMutex.Unlock()
+	}
+
+	if curve != nil {
+		if err := a.SetFanCurve(curve); err != nil {
+			return fmt.Errorf("应用暂存曲线失败: %v", err)
+		}
+		a.draftMutex.Lock()
+		a.draftCurve = nil
+		a.draftMutex.Unlock()
+	}
+
+	guiLogger.Info("离线编辑草稿已全部应用")
+	return nil
+}
+
+// DiscardPendingChanges 丢弃离线编辑草稿
+func (a *App) DiscardPendingChanges() {
+	a.draftMutex.Lock()
+	defer a.draftMutex.Unlock()
+	a.draftConfig = nil
+	a.draftCurve = nil
+}
+
 func (a *App) GetFanCurve() []FanCurvePoint {
 	resp, err := a.sendRequest(ipc.ReqGetFanCurve, nil)
 	if err != nil || resp == nil || !resp.Success {
@@ -451,6 +750,110 @@ func (a *App) SetCustomSpeed(enabled bool, rpm int) error {
 	return nil
 }
 
+// MaxBoost 开启一键狂暴，durationSec<=0时使用配置的默认时长
+func (a *App) MaxBoost(durationSec int) error {
+	return a.sendMaxBoost(ipc.MaxBoostParams{Enabled: true, DurationSec: durationSec})
+}
+
+// CancelMaxBoost 提前结束狂暴模式
+func (a *App) CancelMaxBoost() error {
+	return a.sendMaxBoost(ipc.MaxBoostParams{Enabled: false})
+}
+
+func (a *App) sendMaxBoost(params ipc.MaxBoostParams) error {
+	resp, err := a.sendRequest(ipc.ReqMaxBoost, params)
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		return fmt.Errorf("服务响应为空")
+	}
+	return nil
+}
+
+// StartThermalProfile 启动曲线测绘向导，进度和结果通过
+// thermal-profile-progress / thermal-profile-done 事件回传前端
+func (a *App) StartThermalProfile(settleSec, sampleSec int) error {
+	resp, err := a.sendRequest(ipc.ReqStartThermalProfile, ipc.StartThermalProfileParams{
+		SettleSec: settleSec,
+		SampleSec: sampleSec,
+	})
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		return fmt.Errorf("服务响应为空")
+	}
+	return nil
+}
+
+// CancelThermalProfile 取消正在进行的曲线测绘
+func (a *App) CancelThermalProfile() error {
+	resp, err := a.sendRequest(ipc.ReqCancelThermalProfile, nil)
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		return fmt.Errorf("服务响应为空")
+	}
+	return nil
+}
+
+// StartCurveABTest 启动两条曲线的A/B交替对比测试，进度和结论通过
+// curve-ab-test-progress / curve-ab-test-done 事件回传前端
+func (a *App) StartCurveABTest(params ipc.StartCurveABTestParams) error {
+	resp, err := a.sendRequest(ipc.ReqStartCurveABTest, params)
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		return fmt.Errorf("服务响应为空")
+	}
+	return nil
+}
+
+// CancelCurveABTest 取消正在进行的曲线A/B测试
+func (a *App) CancelCurveABTest() error {
+	resp, err := a.sendRequest(ipc.ReqCancelCurveABTest, nil)
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		return fmt.Errorf("服务响应为空")
+	}
+	return nil
+}
+
+// TriggerNotificationAction 把通知toast上被点击的动作按钮回传核心执行
+func (a *App) TriggerNotificationAction(actionID string) error {
+	resp, err := a.sendRequest(ipc.ReqNotificationAction, ipc.NotificationActionParams{ActionID: actionID})
+	if err != nil {
+		return err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		return fmt.Errorf("服务响应为空")
+	}
+	return nil
+}
+
 func (a *App) SetGearLight(enabled bool) bool {
 	resp, err := a.sendRequest(ipc.ReqSetGearLight, ipc.SetBoolParams{Enabled: enabled})
 	if err != nil || resp == nil {
@@ -546,12 +949,26 @@ func (a *App) IsAutoStartLaunch() bool {
 	return autostart.DetectAutoStartLaunch(os.Args)
 }
 
+// GetElevationStatus 查询GUI进程的UAC提权详情。前端在涉及管理员权限的
+// 设置项上据此给出正确引导（可一键提权还是需要换管理员账号）
+func (a *App) GetElevationStatus() autostart.ElevationStatus {
+	return autostart.GetElevationStatus()
+}
+
 func (a *App) ShowWindow() {
 	if a.ctx != nil {
 		runtime.WindowShow(a.ctx)
 	}
 }
 
+// NavigateTo 显示窗口并让前端跳转到指定页面，view为空时仅显示窗口
+func (a *App) NavigateTo(view string) {
+	a.ShowWindow()
+	if view != "" && a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "navigate", view)
+	}
+}
+
 func (a *App) HideWindow() {
 	if a.ctx != nil {
 		runtime.WindowHide(a.ctx)
@@ -664,9 +1081,61 @@ func (a *App) GetDebugInfo() map[string]any {
 	}
 	var info map[string]any
 	json.Unmarshal(resp.Data, &info)
+	if info != nil {
+		info["frontendLogStats"] = a.frontendLogStats()
+	}
 	return info
 }
 
+// ListCrashReports 获取核心的崩溃报告索引（新到旧，调试页展示用）
+func (a *App) ListCrashReports() []ipc.CrashReportInfo {
+	resp, err := a.sendRequest(ipc.ReqListCrashReports, nil)
+	if err != nil || resp == nil || !resp.Success {
+		return nil
+	}
+	var reports []ipc.CrashReportInfo
+	json.Unmarshal(resp.Data, &reports)
+	return reports
+}
+
+// CreateSupportBundle 让核心打包支持包，返回zip文件路径供前端提示用户
+func (a *App) CreateSupportBundle() (string, error) {
+	resp, err := a.sendRequest(ipc.ReqCreateSupportBundle, nil)
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || !resp.Success {
+		if resp != nil {
+			return "", fmt.Errorf("%s", resp.Error)
+		}
+		return "", fmt.Errorf("服务响应为空")
+	}
+	var result struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return "", err
+	}
+	return result.Path, nil
+}
+
+// GetHealth 获取核心各子系统的健康报告（状态栏指示灯用）
+func (a *App) GetHealth() map[string]any {
+	resp, err := a.sendRequest(ipc.ReqGetHealth, nil)
+	if err != nil || resp == nil || !resp.Success {
+		errMsg := "请求失败"
+		if err != nil {
+			errMsg = err.Error()
+		} else if resp != nil && resp.Error != "" {
+			errMsg = resp.Error
+		}
+		return map[string]any{"status": "down", "error": errMsg}
+	}
+	var report map[string]any
+	json.Unmarshal(resp.Data, &report)
+	return report
+}
+
 func (a *App) SetDebugMode(enabled bool) error {
 	resp, err := a.sendRequest(ipc.ReqSetDebugMode, ipc.SetBoolParams{Enabled: enabled})
 	if err != nil {
@@ -681,19 +1150,26 @@ func (a *App) SetDebugMode(enabled bool) error {
 	return nil
 }
 
-// LogFrontendError 接收前端上报的JS错误，写入gui日志文件
-func (a *App) LogFrontendError(level, source, message, stack string) {
-	if guiLogger == nil {
+// handshakeClientMode 握手声明GUI客户端身份（远程模式下附带访问令牌），
+// 并取回核心会话ID写入本端日志，排查问题时据此把gui日志与core日志对齐到同一次核心启动。
+// 每次建立新连接后都须调用，远程连接在握手通过前无法执行业务请求。
+func (a *App) handshakeClientMode() {
+	resp, err := a.ipcClient.SendRequest(ipc.ReqSetClientMode, ipc.SetClientModeParams{
+		Mode:       ipc.ClientModeFull,
+		ClientType: ipc.ClientTypeGUI,
+		Token:      a.remoteCoreToken,
+	})
+	if err != nil || !resp.Success {
+		if resp != nil && !resp.Success {
+			guiLogger.Errorf("客户端握手被拒绝: %s", resp.Error)
+		} else {
+			guiLogger.Errorf("客户端握手失败: %v", err)
+		}
 		return
 	}
-	entry := fmt.Sprintf("[frontend][%s] %s\n  stack: %s", source, message, stack)
-	switch level {
-	case "warn":
-		guiLogger.Warn(entry)
-	case "crash", "error":
-		guiLogger.Error(entry)
-	default:
-		guiLogger.Info(entry)
+	var result ipc.SetClientModeResult
+	if json.Unmarshal(resp.Data, &result) == nil && result.SessionID != "" {
+		guiLogger.Infof("核心会话ID: %s", result.SessionID)
 	}
 }
 
@@ -711,6 +1187,8 @@ func (a *App) startConnectionHealthCheck() {
 
 			if err := a.ipcClient.Connect(); err == nil {
 				guiLogger.Info("Watchdog: 核心服务重连成功！")
+				// 重连是新的TCP/管道连接，须重新握手（远程模式下否则一直处于未鉴权状态）
+				a.handshakeClientMode()
 				currentInterval = baseInterval // 重连成功，重置为基础心跳频率
 			} else {
 				// 连接失败，推送UI状态
@@ -758,6 +1236,8 @@ func (a *App) CheckConnectionStatus() map[string]any {
 			status["error"] = resp.Error
 		}
 	}
+	status["transport"] = a.ipcClient.ActiveTransport()
+	status["remoteCore"] = a.remoteCoreAddr
 
 	return status
 }
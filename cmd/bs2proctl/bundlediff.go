@@ -0,0 +1,254 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// 支持包对比：离线读取两份支持包zip，汇报两次导出之间的
+// 版本、配置、健康状态和崩溃报告差异。排查"上周还好好的"一类问题时，
+// 用户只要各留一份支持包，就能直接定位中间改了什么。
+// 对比完全在本地完成，不连接核心服务。
+
+// bundleSnapshot 从支持包zip解出的可对比内容
+type bundleSnapshot struct {
+	path     string
+	manifest map[string]any
+	config   map[string]any
+	health   ipc.HealthReport
+	crashes  []ipc.CrashReportInfo
+}
+
+// runBundleDiff 对比两份支持包并打印报告。
+// 返回diff风格退出码：0无差异，1有差异，2读取失败。
+func runBundleDiff(oldPath, newPath string) int {
+	oldSnap, err := readBundle(oldPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取旧支持包失败: %v\n", err)
+		return 2
+	}
+	newSnap, err := readBundle(newPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取新支持包失败: %v\n", err)
+		return 2
+	}
+
+	if bundleCreated(oldSnap) > bundleCreated(newSnap) {
+		fmt.Fprintln(os.Stderr, "提示: 第一个包比第二个包新，报告按参数顺序以第一个为基准")
+	}
+
+	changed := false
+	fmt.Printf("对比: %s -> %s\n", oldSnap.path, newSnap.path)
+	fmt.Printf("导出时间: %s -> %s\n",
+		bundleCreatedString(oldSnap), bundleCreatedString(newSnap))
+
+	if ov, nv := oldSnap.manifest["version"], newSnap.manifest["version"]; ov != nv {
+		fmt.Printf("\n版本变化: %v -> %v\n", ov, nv)
+		changed = true
+	}
+
+	if diffConfig(oldSnap.config, newSnap.config) {
+		changed = true
+	}
+	if diffHealth(oldSnap.health, newSnap.health) {
+		changed = true
+	}
+	if diffCrashes(oldSnap.crashes, newSnap.crashes) {
+		changed = true
+	}
+
+	if !changed {
+		fmt.Println("\n无差异")
+		return 0
+	}
+	return 1
+}
+
+// readBundle 解出支持包中的可对比条目。崩溃索引等可选条目缺失时
+// 按空值处理，保证也能对比旧版本核心导出的包。
+func readBundle(path string) (*bundleSnapshot, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	snap := &bundleSnapshot{path: path}
+	if err := readBundleJSON(&zr.Reader, "manifest.json", &snap.manifest); err != nil {
+		return nil, fmt.Errorf("缺少清单（不是支持包?）: %v", err)
+	}
+	if err := readBundleJSON(&zr.Reader, "config.json", &snap.config); err != nil {
+		return nil, fmt.Errorf("缺少配置快照: %v", err)
+	}
+	_ = readBundleJSON(&zr.Reader, "health.json", &snap.health)
+	_ = readBundleJSON(&zr.Reader, "crash_reports.json", &snap.crashes)
+	return snap, nil
+}
+
+// readBundleJSON 解析zip中指定条目的JSON内容
+func readBundleJSON(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
+
+func bundleCreated(snap *bundleSnapshot) float64 {
+	created, _ := snap.manifest["createdUnix"].(float64)
+	return created
+}
+
+func bundleCreatedString(snap *bundleSnapshot) string {
+	if s, ok := snap.manifest["created"].(string); ok && s != "" {
+		return s
+	}
+	if unix := bundleCreated(snap); unix > 0 {
+		return time.Unix(int64(unix), 0).Format(time.RFC3339)
+	}
+	return "未知"
+}
+
+// diffConfig 打印配置差异（新增/删除/变更的叶子键），返回是否有差异
+func diffConfig(oldCfg, newCfg map[string]any) bool {
+	oldFlat := map[string]string{}
+	newFlat := map[string]string{}
+	flattenJSON("", oldCfg, oldFlat)
+	flattenJSON("", newCfg, newFlat)
+
+	keys := map[string]bool{}
+	for k := range oldFlat {
+		keys[k] = true
+	}
+	for k := range newFlat {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, k := range sorted {
+		oldVal, inOld := oldFlat[k]
+		newVal, inNew := newFlat[k]
+		switch {
+		case !inOld:
+			lines = append(lines, fmt.Sprintf("  + %s = %s", k, newVal))
+		case !inNew:
+			lines = append(lines, fmt.Sprintf("  - %s (原值 %s)", k, oldVal))
+		case oldVal != newVal:
+			lines = append(lines, fmt.Sprintf("  ~ %s: %s -> %s", k, oldVal, newVal))
+		}
+	}
+	if len(lines) == 0 {
+		return false
+	}
+	fmt.Printf("\n配置变更 (%d 项):\n", len(lines))
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+	return true
+}
+
+// flattenJSON 把解码后的JSON值摊平成 点路径->紧凑值 的叶子映射，
+// 数组按下标展开，整体消失/新增的子树会表现为一组同前缀的叶子差异
+func flattenJSON(prefix string, v any, out map[string]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenJSON(key, child, out)
+		}
+	case []any:
+		for i, child := range val {
+			flattenJSON(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			out[prefix] = fmt.Sprintf("%v", v)
+			return
+		}
+		out[prefix] = string(data)
+	}
+}
+
+// diffHealth 打印整体与各组件健康状态变化，返回是否有差异
+func diffHealth(oldRep, newRep ipc.HealthReport) bool {
+	if oldRep.Status == "" && newRep.Status == "" {
+		return false
+	}
+	var lines []string
+	if oldRep.Status != newRep.Status {
+		lines = append(lines, fmt.Sprintf("  整体: %s -> %s", oldRep.Status, newRep.Status))
+	}
+	oldComp := map[string]ipc.ComponentHealth{}
+	for _, c := range oldRep.Components {
+		oldComp[c.Name] = c
+	}
+	for _, c := range newRep.Components {
+		prev, ok := oldComp[c.Name]
+		if !ok {
+			lines = append(lines, fmt.Sprintf("  + %s: %s", c.Name, c.Status))
+			continue
+		}
+		if prev.Status != c.Status {
+			detail := ""
+			if c.Detail != "" {
+				detail = "（" + c.Detail + "）"
+			}
+			lines = append(lines, fmt.Sprintf("  ~ %s: %s -> %s%s", c.Name, prev.Status, c.Status, detail))
+		}
+	}
+	if len(lines) == 0 {
+		return false
+	}
+	fmt.Println("\n健康状态变化:")
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+	return true
+}
+
+// diffCrashes 列出新包中新增的崩溃报告，返回是否有新增
+func diffCrashes(oldList, newList []ipc.CrashReportInfo) bool {
+	seen := map[string]bool{}
+	for _, r := range oldList {
+		// .gz压缩不改变报告身份，去掉后缀对齐两份索引
+		seen[trimGz(r.File)] = true
+	}
+	var added []ipc.CrashReportInfo
+	for _, r := range newList {
+		if !seen[trimGz(r.File)] {
+			added = append(added, r)
+		}
+	}
+	if len(added) == 0 {
+		return false
+	}
+	fmt.Printf("\n新增崩溃报告 (%d 份):\n", len(added))
+	for _, r := range added {
+		fmt.Printf("  %s (%s)\n", r.File, time.Unix(r.TimeUnix, 0).Format("2006-01-02 15:04:05"))
+	}
+	return true
+}
+
+func trimGz(name string) string {
+	const suffix = ".gz"
+	if len(name) > len(suffix) && name[len(name)-len(suffix):] == suffix {
+		return name[:len(name)-len(suffix)]
+	}
+	return name
+}
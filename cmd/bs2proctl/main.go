@@ -0,0 +1,176 @@
+// bs2proctl 核心服务的命令行调试客户端。与GUI走同一套IPC协议，
+// 面向开发与脚本化排障：一次性发送请求（send）、导出协议规范（schema）、
+// 以及交互式协议浏览器（repl）。本地连接默认admin层级，
+// 可用 -tier 自降权限来验证鉴权行为。
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/version"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `用法: bs2proctl [选项] <子命令>
+
+子命令:
+  repl                交互式协议浏览器（开发调试新IPC请求的首选入口）
+  send <类型> [JSON]  发送单个请求并打印响应
+  schema              导出核心当前的机器可读协议规范
+  diff <旧包> <新包>  离线对比两份支持包（配置/版本/健康/崩溃差异）
+  version             打印版本号
+
+选项:
+`)
+	flag.PrintDefaults()
+}
+
+func main() {
+	remote := flag.String("remote", "", "远程核心地址 host:port，默认连接本机管道/TCP")
+	token := flag.String("token", "", "远程访问令牌，连接远程核心时必需")
+	tier := flag.String("tier", ipc.TierAdmin, "声明的权限层级 read/control/admin")
+	observer := flag.Bool("observer", false, "以observer只读模式握手（修改类请求会被拒绝）")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "version":
+		fmt.Println(version.Get())
+	case "schema":
+		client := mustDial(*remote, *token, *tier, *observer)
+		defer client.Close()
+		spec := mustFetchSpec(client)
+		printJSONValue(spec)
+	case "send":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "用法: bs2proctl send <请求类型> [参数JSON]")
+			os.Exit(2)
+		}
+		client := mustDial(*remote, *token, *tier, *observer)
+		defer client.Close()
+		var data json.RawMessage
+		if len(args) >= 3 {
+			if !json.Valid([]byte(args[2])) {
+				fmt.Fprintln(os.Stderr, "参数不是合法JSON")
+				os.Exit(2)
+			}
+			data = json.RawMessage(args[2])
+		}
+		resp, err := client.SendRequest(ipc.RequestType(args[1]), data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "请求失败: %v\n", err)
+			os.Exit(1)
+		}
+		printResponse(resp)
+		if !resp.Success {
+			os.Exit(1)
+		}
+	case "diff":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "用法: bs2proctl diff <旧支持包.zip> <新支持包.zip>")
+			os.Exit(2)
+		}
+		os.Exit(runBundleDiff(args[1], args[2]))
+	case "repl":
+		client := mustDial(*remote, *token, *tier, *observer)
+		defer client.Close()
+		runREPL(client, mustFetchSpec(client))
+	default:
+		fmt.Fprintf(os.Stderr, "未知子命令: %s\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+}
+
+// mustDial 建立连接并完成客户端模式握手，失败直接退出进程
+func mustDial(remote, token, tier string, observer bool) *ipc.Client {
+	client := ipc.NewClient(nil)
+	if remote != "" {
+		client.SetEndpoints(ipc.RemoteEndpoints(remote))
+	}
+	if err := client.Connect(); err != nil {
+		fmt.Fprintf(os.Stderr, "连接核心服务失败: %v\n", err)
+		os.Exit(1)
+	}
+	mode := ipc.ClientModeFull
+	if observer {
+		mode = ipc.ClientModeObserver
+	}
+	resp, err := client.SendRequest(ipc.ReqSetClientMode, ipc.SetClientModeParams{
+		Mode:       mode,
+		ClientType: ipc.ClientTypeCLI,
+		Tier:       tier,
+		Token:      token,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "握手失败: %v\n", err)
+		os.Exit(1)
+	}
+	if !resp.Success {
+		fmt.Fprintf(os.Stderr, "握手被拒绝: %s\n", resp.Error)
+		os.Exit(1)
+	}
+	return client
+}
+
+// mustFetchSpec 从核心拉取协议规范，失败直接退出进程。
+// 规范由核心运行时推导，列出的请求/权限永远与核心实际执行的一致。
+func mustFetchSpec(client *ipc.Client) *ipc.ProtocolSpec {
+	resp, err := client.SendRequest(ipc.ReqGetSchema, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "获取协议规范失败: %v\n", err)
+		os.Exit(1)
+	}
+	if !resp.Success {
+		fmt.Fprintf(os.Stderr, "获取协议规范被拒绝: %s\n", resp.Error)
+		os.Exit(1)
+	}
+	var spec ipc.ProtocolSpec
+	if err := json.Unmarshal(resp.Data, &spec); err != nil {
+		fmt.Fprintf(os.Stderr, "解析协议规范失败: %v\n", err)
+		os.Exit(1)
+	}
+	return &spec
+}
+
+// printResponse 打印响应：成功标志、错误信息和缩进后的data
+func printResponse(resp *ipc.Response) {
+	if resp.Success {
+		fmt.Println("成功")
+	} else {
+		fmt.Printf("失败: %s\n", resp.Error)
+	}
+	if len(resp.Data) > 0 {
+		fmt.Println(indentJSON(resp.Data))
+	}
+}
+
+// indentJSON 缩进原始JSON，失败时原样返回
+func indentJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// printJSONValue 缩进打印任意可序列化值
+func printJSONValue(v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "序列化失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
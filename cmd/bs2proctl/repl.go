@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// REPL 交互式协议浏览器。请求列表、参数结构和权限层级全部来自
+// 核心返回的协议规范，不在本地硬编码：新增IPC请求后重启核心，
+// repl里立即可见并可试发，无需改动本工具。
+//
+// 事件默认不打印（风扇/温度事件每秒都有，会刷掉提示符），
+// 用 watch on [关键字] 按需开启。
+
+// replPrompt REPL提示符，事件打印后重绘
+const replPrompt = "bs2proctl> "
+
+type repl struct {
+	client *ipc.Client
+	spec   *ipc.ProtocolSpec
+
+	// printMu 串行化响应输出与异步事件输出，避免交错
+	printMu     sync.Mutex
+	watchOn     bool
+	watchFilter string // 事件类型子串过滤，空表示全部
+}
+
+func runREPL(client *ipc.Client, spec *ipc.ProtocolSpec) {
+	r := &repl{client: client, spec: spec}
+	client.SetEventHandler(r.onEvent)
+
+	fmt.Printf("已连接核心 (传输: %s)，协议规范版本 %d：%d 个请求，%d 个事件\n",
+		client.ActiveTransport(), spec.SpecVersion, len(spec.Requests), len(spec.Events))
+	fmt.Println("输入 help 查看命令，list 查看请求类型")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Print(replPrompt)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !r.dispatch(line) {
+			return
+		}
+		fmt.Print(replPrompt)
+	}
+}
+
+// dispatch 执行一条REPL命令，返回false表示退出
+func (r *repl) dispatch(line string) bool {
+	fields := strings.Fields(line)
+	cmd := fields[0]
+	args := fields[1:]
+
+	switch cmd {
+	case "help", "?":
+		r.printHelp()
+	case "list", "ls":
+		r.listRequests(strings.Join(args, " "))
+	case "events":
+		r.listEvents()
+	case "desc":
+		if len(args) != 1 {
+			fmt.Println("用法: desc <请求类型>")
+			break
+		}
+		r.describe(args[0])
+	case "send":
+		if len(args) == 0 {
+			fmt.Println("用法: send <请求类型> [参数JSON]")
+			break
+		}
+		// 参数JSON可能含空格，按原始行切出第三段
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "send"))
+		parts := strings.SplitN(rest, " ", 2)
+		inline := ""
+		if len(parts) == 2 {
+			inline = strings.TrimSpace(parts[1])
+		}
+		r.send(parts[0], inline)
+	case "watch":
+		r.setWatch(args)
+	case "schema":
+		printJSONValue(r.spec)
+	case "exit", "quit", "q":
+		return false
+	default:
+		fmt.Printf("未知命令: %s（help查看命令列表）\n", cmd)
+	}
+	return true
+}
+
+func (r *repl) printHelp() {
+	fmt.Print(`命令:
+  list [关键字]        列出请求类型（类型/层级/是否修改/说明），关键字过滤
+  desc <请求类型>      查看单个请求的参数与响应结构
+  send <类型> [JSON]   发送请求；声明了参数但未内联时会提示输入
+  events               列出广播事件类型
+  watch on [关键字]    打印实时事件，关键字按事件类型子串过滤
+  watch off            停止打印事件
+  schema               打印完整协议规范JSON
+  exit                 退出
+`)
+}
+
+func (r *repl) listRequests(keyword string) {
+	keyword = strings.ToLower(keyword)
+	for _, req := range r.spec.Requests {
+		if keyword != "" &&
+			!strings.Contains(strings.ToLower(string(req.Type)), keyword) &&
+			!strings.Contains(strings.ToLower(req.Summary), keyword) {
+			continue
+		}
+		mut := "  "
+		if req.Mutating {
+			mut = "改"
+		}
+		fmt.Printf("  %-28s %-7s %s  %s\n", req.Type, req.Tier, mut, req.Summary)
+	}
+}
+
+func (r *repl) listEvents() {
+	for _, ev := range r.spec.Events {
+		payload := ev.Payload
+		if payload == "" {
+			payload = "-"
+		}
+		fmt.Printf("  %-28s %-24s %s\n", ev.Type, payload, ev.Summary)
+	}
+}
+
+func (r *repl) findRequest(reqType string) *ipc.RequestSpec {
+	for i := range r.spec.Requests {
+		if string(r.spec.Requests[i].Type) == reqType {
+			return &r.spec.Requests[i]
+		}
+	}
+	return nil
+}
+
+func (r *repl) describe(reqType string) {
+	req := r.findRequest(reqType)
+	if req == nil {
+		fmt.Printf("未知请求类型: %s（list查看全部）\n", reqType)
+		return
+	}
+	fmt.Printf("类型:   %s\n说明:   %s\n层级:   %s\n", req.Type, req.Summary, req.Tier)
+	fmt.Printf("修改类: %v\n", req.Mutating)
+	if req.Params != "" {
+		fmt.Printf("参数:   %s\n", req.Params)
+	} else {
+		fmt.Println("参数:   无")
+	}
+	if req.Result != "" {
+		fmt.Printf("响应:   %s\n", req.Result)
+	}
+}
+
+// send 发送请求。规范声明了参数结构而命令行未内联JSON时，
+// 提示一行JSON输入；直接回车表示不带参数发送。
+func (r *repl) send(reqType, inline string) {
+	spec := r.findRequest(reqType)
+	if spec == nil {
+		fmt.Printf("未知请求类型: %s（list查看全部）\n", reqType)
+		return
+	}
+	if inline == "" && spec.Params != "" {
+		fmt.Printf("参数JSON (结构 %s，回车跳过)> ", spec.Params)
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Printf("读取参数失败: %v\n", err)
+			return
+		}
+		inline = strings.TrimSpace(line)
+	}
+
+	var data json.RawMessage
+	if inline != "" {
+		if !json.Valid([]byte(inline)) {
+			fmt.Println("参数不是合法JSON，已取消发送")
+			return
+		}
+		data = json.RawMessage(inline)
+	}
+
+	resp, err := r.client.SendRequest(ipc.RequestType(reqType), data)
+	r.printMu.Lock()
+	defer r.printMu.Unlock()
+	if err != nil {
+		fmt.Printf("请求失败: %v\n", err)
+		return
+	}
+	printResponse(resp)
+}
+
+func (r *repl) setWatch(args []string) {
+	if len(args) == 0 {
+		fmt.Println("用法: watch on [关键字] | watch off")
+		return
+	}
+	r.printMu.Lock()
+	defer r.printMu.Unlock()
+	switch args[0] {
+	case "on":
+		r.watchOn = true
+		r.watchFilter = ""
+		if len(args) > 1 {
+			r.watchFilter = strings.ToLower(args[1])
+		}
+		if r.watchFilter != "" {
+			fmt.Printf("已开启事件打印（过滤: %s）\n", r.watchFilter)
+		} else {
+			fmt.Println("已开启事件打印")
+		}
+	case "off":
+		r.watchOn = false
+		fmt.Println("已停止事件打印")
+	default:
+		fmt.Println("用法: watch on [关键字] | watch off")
+	}
+}
+
+// onEvent 事件回调（Client在独立goroutine调用），打印后重绘提示符
+func (r *repl) onEvent(ev ipc.Event) {
+	r.printMu.Lock()
+	defer r.printMu.Unlock()
+	if !r.watchOn {
+		return
+	}
+	if r.watchFilter != "" && !strings.Contains(strings.ToLower(ev.Type), r.watchFilter) {
+		return
+	}
+	fmt.Printf("\n[事件] %s\n", ev.Type)
+	if len(ev.Data) > 0 {
+		fmt.Println(indentJSON(ev.Data))
+	}
+	fmt.Print(replPrompt)
+}
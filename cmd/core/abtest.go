@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/temperature"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// 曲线A/B测试参数限制。窗口太短时样本全是换曲线后的过渡状态，
+// 比不出差异；上限防止一次测试占用设备数小时
+const (
+	curveABDefaultWindowSec = 120
+	curveABMinWindowSec     = 30
+	curveABMaxWindowSec     = 900
+	curveABDefaultRounds    = 3
+	curveABMinRounds        = 1
+	curveABMaxRounds        = 10
+	curveABDefaultThreshold = 85
+	curveABMinThreshold     = 60
+	curveABMaxThreshold     = 100
+	// curveABSettleSec 每个窗口开头不计入统计的稳定期，
+	// 刚换曲线时的温度还带着上一条曲线的惯性
+	curveABSettleSec = 15
+	// curveABSampleIntervalSec 窗口内的采样间隔
+	curveABSampleIntervalSec = 2
+)
+
+// curveABTest 曲线A/B测试运行状态。测试通过交替发布两条曲线快照进行，
+// 调速仍由现有控制回路按快照执行，测的就是真实控制路径
+type curveABTest struct {
+	mutex  sync.Mutex
+	active bool
+	cancel chan struct{}
+}
+
+// abAccum 单条曲线的采样累计值，结束时折算为 ipc.CurveABStats
+type abAccum struct {
+	tempSum  int
+	rpmSum   int
+	samples  int
+	aboveSec int
+}
+
+// StartCurveABTest 启动两条曲线的A/B交替对比：用户把典型负载跑起来后，
+// 两条曲线按窗口轮流生效（ABAB…交替摊平负载漂移），
+// 统计各自的平均温度/平均转速/高温时长，结束后广播结论。
+// 属于实验性控制功能，由 experimental-control 功能开关把门。
+func (a *CoreApp) StartCurveABTest(params ipc.StartCurveABTestParams) error {
+	if !a.configManager.FeatureEnabled(config.FlagExperimentalControl) {
+		return fmt.Errorf("曲线A/B测试是实验性功能，请先开启 %s 功能开关", config.FlagExperimentalControl)
+	}
+
+	a.mutex.RLock()
+	connected := a.isConnected
+	a.mutex.RUnlock()
+	if !connected {
+		return fmt.Errorf("设备未连接，无法启动A/B测试")
+	}
+	if a.maxBoostStatus().Active {
+		return fmt.Errorf("狂暴模式进行中，请先结束再启动A/B测试")
+	}
+	a.profile.mutex.Lock()
+	profiling := a.profile.active
+	a.profile.mutex.Unlock()
+	if profiling {
+		return fmt.Errorf("曲线测绘进行中，请先取消再启动A/B测试")
+	}
+	if temp := a.tempReader.Read(); !temp.BridgeOk {
+		return fmt.Errorf("温度读取不可用(%s)，无法统计表现", temp.BridgeMsg)
+	}
+
+	cfg := a.configManager.Get()
+	if !cfg.AutoControl {
+		return fmt.Errorf("A/B测试依赖智能变频执行曲线，请先开启智能变频")
+	}
+
+	curveA := temperature.NormalizeCurve(params.CurveA, cfg.CurveMinTempGap, cfg.CurveMinRPMDelta)
+	curveB := temperature.NormalizeCurve(params.CurveB, cfg.CurveMinTempGap, cfg.CurveMinRPMDelta)
+	if len(curveA) < 2 || len(curveB) < 2 {
+		return fmt.Errorf("两条曲线各需至少2个有效点")
+	}
+
+	windowSec := params.DurationEachSec
+	if windowSec <= 0 {
+		windowSec = curveABDefaultWindowSec
+	}
+	windowSec = clampInt(windowSec, curveABMinWindowSec, curveABMaxWindowSec)
+	rounds := params.Rounds
+	if rounds <= 0 {
+		rounds = curveABDefaultRounds
+	}
+	rounds = clampInt(rounds, curveABMinRounds, curveABMaxRounds)
+	threshold := params.ThresholdTemp
+	if threshold <= 0 {
+		threshold = curveABDefaultThreshold
+	}
+	threshold = clampInt(threshold, curveABMinThreshold, curveABMaxThreshold)
+
+	a.abtest.mutex.Lock()
+	if a.abtest.active {
+		a.abtest.mutex.Unlock()
+		return fmt.Errorf("已有A/B测试在进行中")
+	}
+	a.abtest.active = true
+	a.abtest.cancel = make(chan struct{})
+	cancel := a.abtest.cancel
+	a.abtest.mutex.Unlock()
+
+	a.logInfo("曲线A/B测试开始: %d轮 × 每窗口%d秒，高温阈值%d°C", rounds, windowSec, threshold)
+	a.safeGo("curveABTestRun", func() {
+		a.runCurveABTest(curveA, curveB, windowSec, rounds, threshold, cancel)
+	})
+	return nil
+}
+
+// CancelCurveABTest 取消正在进行的A/B测试，返回是否确有测试被取消。
+// 恢复原曲线和广播done事件由测试goroutine完成
+func (a *CoreApp) CancelCurveABTest(reason string) bool {
+	a.abtest.mutex.Lock()
+	defer a.abtest.mutex.Unlock()
+	if !a.abtest.active || a.abtest.cancel == nil {
+		return false
+	}
+	select {
+	case <-a.abtest.cancel:
+	default:
+		close(a.abtest.cancel)
+		a.logInfo("曲线A/B测试取消请求(%s)", reason)
+	}
+	return true
+}
+
+// runCurveABTest 测试主循环：每轮依次让A、B各生效一个窗口并采样
+func (a *CoreApp) runCurveABTest(curveA, curveB []types.FanCurvePoint, windowSec, rounds, threshold int, cancel chan struct{}) {
+	var accA, accB abAccum
+	completed := true
+	reason := "完成"
+
+	windows := []struct {
+		label string
+		curve []types.FanCurvePoint
+		acc   *abAccum
+	}{
+		{"A", curveA, &accA},
+		{"B", curveB, &accB},
+	}
+
+loop:
+	for round := 1; round <= rounds; round++ {
+		for _, w := range windows {
+			a.mutex.RLock()
+			connected := a.isConnected
+			a.mutex.RUnlock()
+			if !connected {
+				completed, reason = false, "设备断开"
+				break loop
+			}
+
+			a.publishCurve(w.curve)
+			a.logInfo("曲线A/B测试: 第%d/%d轮，曲线%s生效%d秒", round, rounds, w.label, windowSec)
+			if !a.abWindow(w.label, round, rounds, windowSec, threshold, w.acc, cancel) {
+				completed, reason = false, "已取消"
+				break loop
+			}
+		}
+	}
+
+	a.finishCurveABTest(accA, accB, threshold, completed, reason)
+}
+
+// abWindow 执行一个采样窗口：前curveABSettleSec秒只等待不统计，
+// 之后按固定间隔从监控循环的快照取温度和转速。返回false表示测试被取消
+func (a *CoreApp) abWindow(label string, round, rounds, windowSec, threshold int, acc *abAccum, cancel chan struct{}) bool {
+	for remaining := windowSec; remaining > 0; remaining -= curveABSampleIntervalSec {
+		a.broadcastABProgress(ipc.CurveABTestProgress{
+			Curve: label, Round: round, TotalRounds: rounds, RemainingSec: remaining,
+		})
+
+		if windowSec-remaining >= curveABSettleSec {
+			temp := a.snapshotTemperature()
+			if temp.BridgeOk && temp.MaxTemp > 0 {
+				acc.tempSum += temp.MaxTemp
+				acc.samples++
+				if temp.MaxTemp >= threshold {
+					acc.aboveSec += curveABSampleIntervalSec
+				}
+				if fanData := a.snapshotFanData(); fanData != nil {
+					acc.rpmSum += int(fanData.CurrentRPM)
+				}
+			}
+		}
+
+		select {
+		case <-cancel:
+			return false
+		case <-time.After(curveABSampleIntervalSec * time.Second):
+		}
+	}
+	return true
+}
+
+// finishCurveABTest 收尾：恢复配置中的曲线、计算结论并广播done事件
+func (a *CoreApp) finishCurveABTest(accA, accB abAccum, threshold int, completed bool, reason string) {
+	a.abtest.mutex.Lock()
+	a.abtest.active = false
+	a.abtest.cancel = nil
+	a.abtest.mutex.Unlock()
+
+	// 重新发布配置里的曲线：测试期间若用户改过配置，以最新配置为准
+	a.publishCurve(a.configManager.Get().FanCurve)
+
+	result := ipc.CurveABTestResult{
+		Completed:     completed,
+		Reason:        reason,
+		ThresholdTemp: threshold,
+		CurveA:        accA.stats(),
+		CurveB:        accB.stats(),
+	}
+	result.Winner, result.Verdict = abVerdict(result.CurveA, result.CurveB)
+	a.logInfo("曲线A/B测试结束(%s): 胜者%s，%s", reason, result.Winner, result.Verdict)
+
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventCurveABTestDone, result)
+	}
+}
+
+// stats 把采样累计值折算为对外的统计结构
+func (acc abAccum) stats() ipc.CurveABStats {
+	s := ipc.CurveABStats{Samples: acc.samples, AboveThresholdSec: acc.aboveSec}
+	if acc.samples > 0 {
+		s.AvgTemp = float64(acc.tempSum) / float64(acc.samples)
+		s.AvgRPM = float64(acc.rpmSum) / float64(acc.samples)
+	}
+	return s
+}
+
+// abVerdict 比较两条曲线的表现并给出结论。判定顺序：
+// 高温时长（差5秒以上才算显著）> 平均温度（差1°C以上）> 平均转速
+// （差50 RPM以上，温度打平时更安静者胜），全部打平判平局。
+func abVerdict(statsA, statsB ipc.CurveABStats) (winner, verdict string) {
+	if statsA.Samples == 0 || statsB.Samples == 0 {
+		return "tie", "有效采样不足，无法判定（确认测试期间负载在持续运行）"
+	}
+
+	switch {
+	case abs(statsA.AboveThresholdSec-statsB.AboveThresholdSec) > 5:
+		if statsA.AboveThresholdSec < statsB.AboveThresholdSec {
+			return "A", fmt.Sprintf("曲线A的高温时长少%d秒", statsB.AboveThresholdSec-statsA.AboveThresholdSec)
+		}
+		return "B", fmt.Sprintf("曲线B的高温时长少%d秒", statsA.AboveThresholdSec-statsB.AboveThresholdSec)
+	case math.Abs(statsA.AvgTemp-statsB.AvgTemp) > 1.0:
+		if statsA.AvgTemp < statsB.AvgTemp {
+			return "A", fmt.Sprintf("曲线A平均温度低%.1f°C", statsB.AvgTemp-statsA.AvgTemp)
+		}
+		return "B", fmt.Sprintf("曲线B平均温度低%.1f°C", statsA.AvgTemp-statsB.AvgTemp)
+	case math.Abs(statsA.AvgRPM-statsB.AvgRPM) > 50:
+		if statsA.AvgRPM < statsB.AvgRPM {
+			return "A", fmt.Sprintf("温度相当，曲线A平均转速低%.0f RPM更安静", statsB.AvgRPM-statsA.AvgRPM)
+		}
+		return "B", fmt.Sprintf("温度相当，曲线B平均转速低%.0f RPM更安静", statsA.AvgRPM-statsB.AvgRPM)
+	}
+	return "tie", "两条曲线表现相当"
+}
+
+// abs 整数绝对值
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// broadcastABProgress 向所有客户端推送A/B测试窗口进度
+func (a *CoreApp) broadcastABProgress(progress ipc.CurveABTestProgress) {
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventCurveABTestProgress, progress)
+	}
+}
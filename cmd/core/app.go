@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -14,10 +17,17 @@ import (
 
 	"github.com/TIANLI0/BS2PRO-Controller/internal/asus"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/curvefile"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/device"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/lifecycle"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/logger"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/notify"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/obs"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/overlay"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/rgb"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/shm"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/statushttp"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/temperature"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/version"
@@ -31,6 +41,7 @@ type CoreApp struct {
 	tempReader    *temperature.Reader
 	configManager *config.Manager
 	logger        *logger.CustomLogger
+	clock         types.Clock
 	ipcServer     *ipc.Server
 
 	isConnected        bool
@@ -44,12 +55,94 @@ type CoreApp struct {
 	guiLastResponse   int64
 	guiMonitorEnabled bool
 	cleanupChan       chan bool
+	clockWatchStop    chan struct{}
+
+	// safeBoot 检测到崩溃循环后以最小安全配置启动
+	safeBoot bool
+	// guiHangCount GUI 假死累计检测次数（可靠性统计）
+	guiHangCount int64
+	// effectiveTempRate 自适应采样当前生效的采样间隔(秒)
+	effectiveTempRate int64
 
 	mutex          sync.RWMutex
 	stopMonitoring chan bool
 
+	// 设备会话结构化记录（连接→命令→断开）
+	sessions *sessionRecorder
+
+	// 设备写入意图日志（崩溃后据此判断硬件状态是否可能不一致）
+	intents *intentJournal
+
+	// 端到端控制延迟统计（温度采样→设备写入完成）
+	latency latencyTracker
+
+	// 持续热降频检测（风扇满速仍降频时提醒用户）
+	throttleDetector temperature.ThrottleDetector
+
+	// 调速决策日志（有界环形缓冲，只在目标转速变化时追加）
+	decisionMutex   sync.Mutex
+	decisions       []types.ControlDecision
+	lastDecisionRPM int
+
+	// 遥测共享内存写入端（高频只读数据走共享内存，管道保留给控制）
+	telemetry *shm.Writer
+
+	// RTSS游戏内OSD发布端
+	overlay *overlay.Publisher
+
+	// 本地HTTP状态端点（OBS浏览器源等直播叠加层消费）
+	statusServer *statushttp.Server
+
+	// OBS场景联动客户端及进入映射场景前的状态快照
+	obsClient    *obs.Client
+	obsPrevMutex sync.Mutex
+	obsPrev      *obsPrevState
+
 	// 记录当前已经下发的 RGB 智能温度档位
 	lastSmartModeLevel byte
+
+	// 高频风扇数据诊断捕获
+	capture fanCapture
+
+	// IPC流量诊断录制（脱敏JSONL，供--replay离线回放）
+	recorder ipcRecorder
+
+	// 一键狂暴状态（限时最高挡位，到期自动恢复）
+	boost maxBoost
+
+	// 曲线测绘向导状态（逐挡扫描测稳态温度，生成推荐曲线）
+	profile thermalProfile
+
+	// 曲线A/B测试状态（两条曲线交替生效统计表现）
+	abtest curveABTest
+
+	// 系统通知管理器（专注助手开启时延后非关键通知）
+	notifier *notify.Manager
+
+	// 读密集请求的无锁快照（atomic.Value发布最近一次的数据），
+	// GUI图表高频轮询从这里读，不与控制回路争抢大锁
+	tempSnapshot atomic.Value // types.TemperatureData
+	fanSnapshot  atomic.Value // *types.FanData
+
+	// 控制回路使用的不可变曲线快照（双缓冲：写入方整体替换指针），
+	// 避免回路在插值途中读到被换掉一半的切片
+	curveSnap atomic.Value // *curveSnapshot
+
+	// 实时工作点事件的上次推送时刻（仅温度监控goroutine访问）
+	lastLivePointAt time.Time
+
+	// 控制回路运行状态（区分配置开关与实际行为），变化时广播事件
+	ctrlStateMutex sync.Mutex
+	controlState   string
+	controlReason  string
+	controlSince   int64
+
+	// sessionID 本次核心启动的会话ID，握手时下发给GUI/CLI，
+	// 双端日志据此对齐到同一条时间线
+	sessionID string
+
+	// shutdown 子系统关闭顺序管理器，依赖关系见 registerShutdownPlan
+	shutdown *lifecycle.Manager
 }
 
 func NewCoreApp(debugMode bool) *CoreApp {
@@ -91,6 +184,7 @@ func NewCoreApp(debugMode bool) *CoreApp {
 		currentTemp:        types.TemperatureData{BridgeOk: true},
 		configManager:      configMgr,
 		logger:             customLogger,
+		clock:              types.SystemClock{},
 		isConnected:        false,
 		monitoringTemp:     false,
 		stopMonitoring:     make(chan bool, 1),
@@ -99,37 +193,248 @@ func NewCoreApp(debugMode bool) *CoreApp {
 		debugMode:          debugMode,
 		guiLastResponse:    time.Now().Unix(),
 		cleanupChan:        make(chan bool, 1),
+		clockWatchStop:     make(chan struct{}),
 		guiMonitorEnabled:  true,
 		lastSmartModeLevel: 0,
 	}
+
+	sessionDir := customLogger.GetLogDir()
+	if sessionDir == "" {
+		sessionDir = config.GetLogDir()
+	}
+	app.sessions = newSessionRecorder(sessionDir, customLogger)
+	app.intents = newIntentJournal(configMgr.GetDefaultConfigDir(), customLogger)
+	app.telemetry = shm.NewWriter(customLogger)
+	app.overlay = overlay.NewPublisher(customLogger)
+	app.statusServer = statushttp.NewServer(app.statusSnapshot, customLogger)
+	app.statusServer.SetHealth(func() (any, bool) {
+		report := app.healthReport()
+		return report, report.Status != ipc.HealthDown
+	})
+	app.obsClient = obs.NewClient(app.onOBSApplyProfile, app.onOBSRestore, customLogger)
+	// 通知统一经管理器分级下发：实际展示走IPC广播，由GUI弹toast
+	app.notifier = notify.NewManager(customLogger, func(n notify.Notification) {
+		if app.ipcServer != nil {
+			app.ipcServer.BroadcastEvent(ipc.EventNotification, n)
+		}
+	})
+	app.registerNotificationActions()
+	app.shutdown = lifecycle.NewManager(customLogger)
+	app.registerShutdownPlan()
+
 	return app
 }
 
+// registerShutdownPlan 声明各子系统的关闭顺序依赖。
+// 依赖方先停、被依赖方后停：温控回路与数据捕获先于设备句柄释放
+// （否则监控goroutine可能在断开后继续向设备写转速），
+// 设备断开的广播要求IPC服务器仍然在线，配置落盘放在最后。
+func (a *CoreApp) registerShutdownPlan() {
+	a.shutdown.Register("fan-capture", func() error {
+		a.StopFanDataCapture("服务停止")
+		return nil
+	}, "device", "ipc")
+	a.shutdown.Register("thermal-profile", func() error {
+		a.CancelThermalProfile("服务停止")
+		return nil
+	}, "device", "ipc")
+	a.shutdown.Register("curve-abtest", func() error {
+		a.CancelCurveABTest("服务停止")
+		return nil
+	}, "device", "ipc")
+	a.shutdown.Register("ipc-recorder", func() error {
+		a.StopIPCRecording("服务停止")
+		return nil
+	}, "ipc")
+	a.shutdown.Register("temp-monitor", a.stopTemperatureMonitoringAndWait, "device", "config", "asus")
+	a.shutdown.Register("obs", func() error {
+		if a.obsClient != nil {
+			a.obsClient.Stop()
+		}
+		return nil
+	}, "device", "config")
+	a.shutdown.Register("device", func() error {
+		a.DisconnectDevice()
+		return nil
+	}, "ipc")
+	a.shutdown.Register("telemetry", func() error {
+		if a.telemetry != nil {
+			a.telemetry.Close()
+		}
+		return nil
+	})
+	a.shutdown.Register("overlay", func() error {
+		if a.overlay != nil {
+			a.overlay.Close()
+		}
+		return nil
+	})
+	a.shutdown.Register("status-http", func() error {
+		if a.statusServer != nil {
+			a.statusServer.Stop()
+		}
+		return nil
+	})
+	a.shutdown.Register("notify", func() error {
+		if a.notifier != nil {
+			a.notifier.Stop()
+		}
+		return nil
+	}, "ipc")
+	a.shutdown.Register("asus", func() error {
+		if a.asusClient != nil {
+			a.asusClient.Close()
+		}
+		return nil
+	})
+	a.shutdown.Register("ipc", func() error {
+		if a.ipcServer != nil {
+			a.ipcServer.Stop()
+		}
+		return nil
+	})
+	a.shutdown.Register("config", func() error {
+		if a.configManager != nil {
+			a.configManager.Flush()
+		}
+		return nil
+	})
+}
+
+// registerNotificationActions 注册通知动作按钮的处理函数。
+// GUI展示toast后把用户点击经 NotificationAction 请求回传到这里执行。
+func (a *CoreApp) registerNotificationActions() {
+	a.notifier.RegisterAction(notifyActionMaxBoost, func() error {
+		_, err := a.StartMaxBoost(0)
+		return err
+	})
+	a.notifier.RegisterAction(notifyActionAutoControl, func() error {
+		return a.SetAutoControl(true)
+	})
+	// 忽略：仅关闭toast，无核心侧副作用
+	a.notifier.RegisterAction(notifyActionDismiss, func() error { return nil })
+}
+
+// 通知动作ID，与GUI toast按钮一一对应
+const (
+	notifyActionMaxBoost    = "max-boost"
+	notifyActionAutoControl = "auto-control"
+	notifyActionDismiss     = "dismiss"
+)
+
+// newCoreSessionID 生成核心会话ID（时间戳+随机后缀，便于肉眼按启动时间排序）
+func newCoreSessionID() string {
+	suffix := make([]byte, 3)
+	_, _ = cryptorand.Read(suffix)
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), hex.EncodeToString(suffix))
+}
+
 func (a *CoreApp) Start() error {
+	a.sessionID = newCoreSessionID()
 	a.logInfo("=== BS2PRO 核心服务(Windows Service) 启动 ===")
-	a.logInfo("版本: %s", version.Get())
+	a.logInfo("版本: %s, 会话ID: %s", version.Get(), a.sessionID)
 
 	cfg := a.configManager.Load(false)
+	a.configManager.EnsureInstallationID()
 	if cfg.DebugMode {
 		a.debugMode = true
 		if a.logger != nil {
 			a.logger.SetDebugMode(true)
 		}
 	}
+	if a.logger != nil {
+		a.logger.SetLanguage(cfg.LogLanguage)
+	}
+
+	// 崩溃循环检测：短时间内多次崩溃则本次以最小安全配置启动（仅内存生效，不覆盖磁盘配置），
+	// 让用户有机会进入 GUI 修正配置而不是反复重装。
+	const crashLoopThreshold = 3
+	const crashLoopWindow = 10 * time.Minute
+	if n := countRecentCrashReports(resolveCrashLogDir(a), crashLoopWindow); n >= crashLoopThreshold {
+		a.logError("检测到崩溃循环（%v 内 %d 次崩溃），以安全配置启动", crashLoopWindow, n)
+		a.safeBoot = true
+		cfg.AutoControl = false
+		cfg.CustomSpeedEnabled = false
+		cfg.FanCurve = types.GetDefaultFanCurve()
+		if cfg.RGBConfig != nil {
+			cfg.RGBConfig.Mode = "off"
+		}
+		a.configManager.Set(cfg)
+	}
+
+	// 崩溃报告保留策略放后台执行（压缩旧报告可能涉及磁盘IO），不阻塞启动
+	a.safeGo("cleanupCrashReports", func() {
+		if compressed, removed := cleanupCrashReports(resolveCrashLogDir(a)); compressed > 0 || removed > 0 {
+			a.logInfo("崩溃报告保留策略: 压缩 %d 份，删除 %d 份", compressed, removed)
+		}
+	})
+
+	// 发布初始曲线快照（崩溃循环安全启动时为默认曲线）
+	a.publishCurve(cfg.FanCurve)
+	// 记录初始控制回路状态（此时尚无客户端，广播自然无接收方）
+	a.refreshControlState()
+
+	// 崩溃一致性检查：存在遗留意图说明上次进程在设备写入中途退出，
+	// 硬件状态可能与配置不一致，连接后 applyConfigOnConnect 会按配置对账
+	if pending := a.intents.Pending(); pending != nil {
+		a.logError("检测到未确认的设备写入意图 [%s %s @ %s]，连接设备后将按配置重新对账",
+			pending.Action, pending.Detail, pending.Time)
+		a.intents.Clear()
+	}
 
 	if err := a.deviceManager.Init(); err != nil {
 		a.logError("初始化HID库失败: %v", err)
 		return err
 	}
+	if cfg.DeviceInterfacePath != "" {
+		a.deviceManager.SetPreferredPath(cfg.DeviceInterfacePath)
+	}
 	a.deviceManager.SetCallbacks(a.onFanDataUpdate, a.onDeviceDisconnect)
 
 	a.logInfo("启动 IPC 服务器 (Named Pipe)")
 	a.ipcServer = ipc.NewServer(a.handleIPCRequest, a.logger)
+	a.ipcServer.SetSessionID(a.sessionID)
+	if cfg.RemoteAccessAddr != "" {
+		// 远程控制面板场景：另一台机器上的GUI经TCP+令牌连入本核心
+		a.ipcServer.EnableRemoteAccess(cfg.RemoteAccessAddr, cfg.RemoteAccessToken)
+	}
 	if err := a.ipcServer.Start(); err != nil {
 		a.logError("启动 IPC 服务器失败: %v", err)
 		return err
 	}
 
+	if a.safeBoot {
+		a.ipcServer.BroadcastEvent(ipc.EventCrashLoopSafeBoot, map[string]any{
+			"message": "检测到核心服务崩溃循环，已使用安全配置启动（RGB关闭、自定义转速关闭、默认曲线）",
+		})
+	}
+
+	if cfg.SharedMemoryTelemetry {
+		if err := a.telemetry.Open(); err != nil {
+			a.logError("开启遥测共享内存失败: %v", err)
+		}
+	}
+	if cfg.OverlayOSD {
+		if err := a.overlay.Open(); err != nil {
+			a.logError("连接RTSS OSD失败: %v", err)
+		}
+	}
+	if cfg.StatusServerEnabled {
+		if err := a.statusServer.Start(cfg.StatusServerPort, cfg.StatusServerRateSec); err != nil {
+			a.logError("启动本地状态端点失败: %v", err)
+		}
+	}
+	if cfg.OBSConfig != nil && cfg.OBSConfig.Enabled {
+		a.obsClient.Start(cfg.OBSConfig.Host, cfg.OBSConfig.Password, cfg.OBSConfig.SceneProfile)
+	}
+
+	a.notifier.SetCriticalBreakthrough(cfg.ThermalAlertBreakthrough)
+	a.notifier.Start()
+
+	a.safeGo("watchClockChanges", func() {
+		a.watchClockChanges()
+	})
+
 	if cfg.GuiMonitoring {
 		a.logInfo("启动健康监控")
 		a.safeGo("startHealthMonitoring", func() {
@@ -138,7 +443,7 @@ func (a *CoreApp) Start() error {
 	}
 
 	a.safeGo("delayedConnectDevice", func() {
-		time.Sleep(1 * time.Second)
+		a.clock.Sleep(1 * time.Second)
 		a.ConnectDevice()
 	})
 
@@ -147,23 +452,59 @@ func (a *CoreApp) Start() error {
 
 func (a *CoreApp) Stop() {
 	a.logInfo("核心服务正在停止...")
+	a.shutdown.StopAll(lifecycle.DefaultStopTimeout)
+	a.logInfo("核心服务已停止")
+	// cleanup 收尾关闭日志，放在所有组件之后，保证逐步关停日志完整落盘
 	a.cleanup()
-	a.DisconnectDevice()
-	if a.asusClient != nil {
-		a.asusClient.Close()
+}
+
+// stopTemperatureMonitoringAndWait 通知温控goroutine退出并等待其确认。
+// 仅发信号不等待的话，设备断开后监控循环可能还会完成最后一次转速写入。
+func (a *CoreApp) stopTemperatureMonitoringAndWait() error {
+	a.mutex.Lock()
+	running := a.monitoringTemp
+	a.mutex.Unlock()
+	if !running {
+		return nil
 	}
-	if a.ipcServer != nil {
-		a.ipcServer.Stop()
+
+	select {
+	case a.stopMonitoring <- true:
+	default:
 	}
-	a.logInfo("核心服务已停止")
+
+	const waitLimit = 3 * time.Second
+	deadline := a.clock.Now().Add(waitLimit)
+	for a.clock.Now().Before(deadline) {
+		a.mutex.Lock()
+		running = a.monitoringTemp
+		a.mutex.Unlock()
+		if !running {
+			return nil
+		}
+		a.clock.Sleep(20 * time.Millisecond)
+	}
+	return fmt.Errorf("温控循环未在 %v 内退出", waitLimit)
 }
 
-func (a *CoreApp) onShowWindowRequest() {
-	a.logInfo("收到显示窗口请求")
-	if a.ipcServer != nil && a.ipcServer.HasClients() {
-		a.ipcServer.BroadcastEvent("show-window", nil)
+// onShowWindowRequest 唤起GUI窗口。view非空时要求GUI打开指定页面
+// （如通知动作"点击查看图表"），已有GUI连接走广播，
+// 否则拉起GUI进程并通过命令行参数传递目标页面。
+func (a *CoreApp) onShowWindowRequest(view string) {
+	a.logInfo("收到显示窗口请求 (view=%q)", view)
+	if a.ipcServer != nil && a.ipcServer.HasGUIClients() {
+		a.ipcServer.BroadcastEvent("show-window", map[string]string{"view": view})
+		return
+	}
+
+	args := []string{}
+	if view != "" {
+		args = append(args, "--view="+view)
+	}
+	if err := a.launchGui(args...); err != nil {
+		a.logError("没有 GUI 连接，拉起 GUI 进程失败: %v", err)
 	} else {
-		a.logInfo("没有 GUI 连接，服务模式下无法主动唤起窗口。")
+		a.logInfo("没有 GUI 连接，已拉起 GUI 进程")
 	}
 }
 
@@ -175,7 +516,7 @@ func (a *CoreApp) onQuitRequest() {
 
 	go func() {
 		defer func() { recover() }()
-		time.Sleep(1 * time.Second)
+		a.clock.Sleep(1 * time.Second)
 		a.Stop() // 释放硬件句柄
 		a.logInfo("核心服务进程自我终止")
 		os.Exit(0) // 正常退出
@@ -201,8 +542,15 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		status := a.GetDeviceStatus()
 		return a.dataResponse(status)
 	case ipc.ReqGetCurrentFanData:
-		data := a.deviceManager.GetCurrentFanData()
-		return a.dataResponse(data)
+		return a.dataResponse(a.snapshotFanData())
+	case ipc.ReqGetDeviceSessions:
+		return a.dataResponse(a.sessions.Sessions())
+	case ipc.ReqEnumerateDevices:
+		devices, err := a.deviceManager.EnumerateDevices()
+		if err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.dataResponse(devices)
 	case ipc.ReqGetConfig:
 		cfg := a.configManager.Get()
 		return a.dataResponse(cfg)
@@ -220,13 +568,48 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		if err := json.Unmarshal(req.Data, &curve); err != nil {
 			return a.errorResponse("解析风扇曲线失败: " + err.Error())
 		}
-		if err := a.SetFanCurve(curve); err != nil {
+		normalized, err := a.SetFanCurve(curve)
+		if err != nil {
 			return a.errorResponse(err.Error())
 		}
-		return a.successResponse(true)
+		return a.dataResponse(normalized)
 	case ipc.ReqGetFanCurve:
 		curve := a.configManager.Get().FanCurve
 		return a.dataResponse(curve)
+	case ipc.ReqImportCurve:
+		var params ipc.ImportCurveParams
+		if err := json.Unmarshal(req.Data, &params); err != nil {
+			return a.errorResponse("解析参数失败: " + err.Error())
+		}
+		curve, err := temperature.ImportCurve(params.Format, params.Content)
+		if err != nil {
+			return a.errorResponse("导入曲线失败: " + err.Error())
+		}
+		a.logInfo("已导入%s格式曲线，共%d个点", params.Format, len(curve))
+		return a.dataResponse(curve)
+	case ipc.ReqExportCurveFile:
+		var params ipc.ExportCurveFileParams
+		if len(req.Data) > 0 {
+			if err := json.Unmarshal(req.Data, &params); err != nil {
+				return a.errorResponse("解析导出参数失败: " + err.Error())
+			}
+		}
+		content, err := a.exportCurveFile(params)
+		if err != nil {
+			return a.errorResponse("导出曲线文件失败: " + err.Error())
+		}
+		return a.dataResponse(content)
+	case ipc.ReqImportCurveFile:
+		var params ipc.ImportCurveFileParams
+		if err := json.Unmarshal(req.Data, &params); err != nil {
+			return a.errorResponse("解析导入参数失败: " + err.Error())
+		}
+		file, err := curvefile.Parse([]byte(params.Content))
+		if err != nil {
+			return a.errorResponse("导入曲线文件失败: " + err.Error())
+		}
+		a.logInfo("已导入曲线文件 %q（作者: %s，共%d个点）", file.Metadata.Name, file.Metadata.Author, len(file.Points))
+		return a.dataResponse(map[string]any{"curve": file.Points, "metadata": file.Metadata})
 	case ipc.ReqSetAutoControl:
 		var params ipc.SetAutoControlParams
 		if err := json.Unmarshal(req.Data, &params); err != nil {
@@ -241,6 +624,9 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		if err := json.Unmarshal(req.Data, &params); err != nil {
 			return a.errorResponse("解析参数失败: " + err.Error())
 		}
+		if err := a.validateGearSupported(params.Gear); err != nil {
+			return a.errorResponse(err.Error())
+		}
 		success := a.SetManualGear(params.Gear, params.Level)
 		return a.successResponse(success)
 	case ipc.ReqGetAvailableGears:
@@ -251,6 +637,11 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		if err := json.Unmarshal(req.Data, &params); err != nil {
 			return a.errorResponse("解析参数失败: " + err.Error())
 		}
+		if params.Enabled {
+			if err := a.validateCustomSpeedRPM(params.RPM, params.Override); err != nil {
+				return a.errorResponse(err.Error())
+			}
+		}
 		if err := a.SetCustomSpeed(params.Enabled, params.RPM); err != nil {
 			return a.errorResponse(err.Error())
 		}
@@ -284,10 +675,7 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		success := a.SetBrightness(params.Value)
 		return a.successResponse(success)
 	case ipc.ReqGetTemperature:
-		a.mutex.RLock()
-		temp := a.currentTemp
-		a.mutex.RUnlock()
-		return a.dataResponse(temp)
+		return a.dataResponse(a.snapshotTemperature())
 	case ipc.ReqTestTemperatureReading:
 		temp := a.tempReader.Read()
 		return a.dataResponse(temp)
@@ -300,7 +688,7 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 					CpuTemp:    cpuTemp,
 					GpuTemp:    0,
 					MaxTemp:    cpuTemp,
-					UpdateTime: time.Now().Unix(),
+					UpdateTime: a.clock.Now().Unix(),
 					Success:    true,
 					Error:      "",
 				}
@@ -320,7 +708,13 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		}
 		return a.dataResponse(status)
 	case ipc.ReqShowWindow:
-		a.onShowWindowRequest()
+		var params ipc.ShowWindowParams
+		if len(req.Data) > 0 {
+			if err := json.Unmarshal(req.Data, &params); err != nil {
+				return a.errorResponse("解析参数失败: " + err.Error())
+			}
+		}
+		a.onShowWindowRequest(params.View)
 		return a.successResponse(true)
 	case ipc.ReqHideWindow:
 		return a.successResponse(true)
@@ -330,6 +724,96 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 	case ipc.ReqGetDebugInfo:
 		info := a.GetDebugInfo()
 		return a.dataResponse(info)
+	case ipc.ReqGetControlDecisions:
+		return a.dataResponse(a.GetControlDecisions())
+	case ipc.ReqNotificationAction:
+		var params ipc.NotificationActionParams
+		if err := json.Unmarshal(req.Data, &params); err != nil {
+			return a.errorResponse("解析参数失败: " + err.Error())
+		}
+		if err := a.notifier.HandleAction(params.ActionID); err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.successResponse(true)
+	case ipc.ReqGetConnectedClients:
+		return a.dataResponse(a.ipcServer.ConnectedClients())
+	case ipc.ReqStartFanDataCapture:
+		var params ipc.StartFanCaptureParams
+		if len(req.Data) > 0 {
+			if err := json.Unmarshal(req.Data, &params); err != nil {
+				return a.errorResponse("解析参数失败: " + err.Error())
+			}
+		}
+		path, err := a.StartFanDataCapture(params.DurationSec)
+		if err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.dataResponse(map[string]any{"path": path})
+	case ipc.ReqStopFanDataCapture:
+		return a.successResponse(a.StopFanDataCapture("手动停止"))
+	case ipc.ReqStartIPCRecording:
+		var params ipc.StartIPCRecordingParams
+		if len(req.Data) > 0 {
+			if err := json.Unmarshal(req.Data, &params); err != nil {
+				return a.errorResponse("解析参数失败: " + err.Error())
+			}
+		}
+		path, err := a.StartIPCRecording(params.DurationSec)
+		if err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.dataResponse(map[string]any{"path": path})
+	case ipc.ReqStopIPCRecording:
+		return a.successResponse(a.StopIPCRecording("手动停止"))
+	case ipc.ReqListCrashReports:
+		return a.dataResponse(listCrashReports(resolveCrashLogDir(a)))
+	case ipc.ReqCreateSupportBundle:
+		path, err := a.CreateSupportBundle()
+		if err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.dataResponse(map[string]any{"path": path})
+	case ipc.ReqMaxBoost:
+		var params ipc.MaxBoostParams
+		if len(req.Data) > 0 {
+			if err := json.Unmarshal(req.Data, &params); err != nil {
+				return a.errorResponse("解析参数失败: " + err.Error())
+			}
+		} else {
+			params.Enabled = true
+		}
+		if !params.Enabled {
+			return a.dataResponse(a.StopMaxBoost())
+		}
+		status, err := a.StartMaxBoost(params.DurationSec)
+		if err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.dataResponse(status)
+	case ipc.ReqStartThermalProfile:
+		var params ipc.StartThermalProfileParams
+		if len(req.Data) > 0 {
+			if err := json.Unmarshal(req.Data, &params); err != nil {
+				return a.errorResponse("解析参数失败: " + err.Error())
+			}
+		}
+		if err := a.StartThermalProfile(params.SettleSec, params.SampleSec); err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.successResponse(true)
+	case ipc.ReqCancelThermalProfile:
+		return a.successResponse(a.CancelThermalProfile("手动取消"))
+	case ipc.ReqStartCurveABTest:
+		var params ipc.StartCurveABTestParams
+		if err := json.Unmarshal(req.Data, &params); err != nil {
+			return a.errorResponse("解析参数失败: " + err.Error())
+		}
+		if err := a.StartCurveABTest(params); err != nil {
+			return a.errorResponse(err.Error())
+		}
+		return a.successResponse(true)
+	case ipc.ReqCancelCurveABTest:
+		return a.successResponse(a.CancelCurveABTest("手动取消"))
 	case ipc.ReqSetDebugMode:
 		var params ipc.SetBoolParams
 		if err := json.Unmarshal(req.Data, &params); err != nil {
@@ -340,10 +824,14 @@ func (a *CoreApp) handleIPCRequest(req ipc.Request) (res ipc.Response) {
 		}
 		return a.successResponse(true)
 	case ipc.ReqUpdateGuiResponseTime:
-		atomic.StoreInt64(&a.guiLastResponse, time.Now().Unix())
+		atomic.StoreInt64(&a.guiLastResponse, a.clock.Now().Unix())
 		return a.successResponse(true)
 	case ipc.ReqPing:
 		return a.dataResponse("pong")
+	case ipc.ReqGetSchema:
+		return a.dataResponse(ipc.Spec())
+	case ipc.ReqGetHealth:
+		return a.dataResponse(a.healthReport())
 	case ipc.ReqSetRGBMode:
 		var params ipc.SetRGBModeParams
 		if err := json.Unmarshal(req.Data, &params); err != nil {
@@ -381,7 +869,26 @@ func (a *CoreApp) dataResponse(data any) ipc.Response {
 	return ipc.Response{Success: true, Data: dataBytes}
 }
 
+// snapshotTemperature 返回最近发布的温度快照，监控未运行过时回退到受锁的当前值
+func (a *CoreApp) snapshotTemperature() types.TemperatureData {
+	if v := a.tempSnapshot.Load(); v != nil {
+		return v.(types.TemperatureData)
+	}
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.currentTemp
+}
+
+// snapshotFanData 返回最近发布的风扇数据快照，未发布过时回退到设备管理器
+func (a *CoreApp) snapshotFanData() *types.FanData {
+	if v := a.fanSnapshot.Load(); v != nil {
+		return v.(*types.FanData)
+	}
+	return a.deviceManager.GetCurrentFanData()
+}
+
 func (a *CoreApp) onFanDataUpdate(fanData *types.FanData) {
+	a.fanSnapshot.Store(fanData)
 	a.mutex.Lock()
 	cfg := a.configManager.Get()
 	var shouldBroadcastConfig bool
@@ -389,12 +896,6 @@ func (a *CoreApp) onFanDataUpdate(fanData *types.FanData) {
 	if fanData.WorkMode == "挡位工作模式" && cfg.AutoControl && a.lastDeviceMode == "自动模式(实时转速)" && !a.userSetAutoControl && !cfg.IgnoreDeviceOnReconnect {
 		a.logInfo("检测到设备从自动模式切换到挡位工作模式，自动关闭智能变频")
 		cfg.AutoControl = false
-		if a.monitoringTemp {
-			select {
-			case a.stopMonitoring <- true:
-			default:
-			}
-		}
 		a.configManager.Set(cfg)
 		a.configManager.Save()
 		shouldBroadcastConfig = true
@@ -409,6 +910,20 @@ func (a *CoreApp) onFanDataUpdate(fanData *types.FanData) {
 	}
 	a.mutex.Unlock()
 
+	// 收到有效的0x5AA5报告说明当前接口探测正确，持久化为首选接口
+	if path := a.deviceManager.ConnectedPath(); path != "" && cfg.DeviceInterfacePath != path {
+		cfg = a.configManager.Get()
+		cfg.DeviceInterfacePath = path
+		a.configManager.Update(cfg)
+		a.deviceManager.SetPreferredPath(path)
+		a.logInfo("已将当前HID接口持久化为首选路径: %s", path)
+	}
+
+	// 设备模式回退关闭了智能变频，重新收敛监控循环状态
+	if shouldBroadcastConfig {
+		a.reconcileMonitoring()
+	}
+
 	// 在锁外进行广播，避免持锁期间阻塞
 	if shouldBroadcastConfig && a.ipcServer != nil {
 		a.ipcServer.BroadcastEvent(ipc.EventConfigUpdate, broadcastCfg)
@@ -416,6 +931,141 @@ func (a *CoreApp) onFanDataUpdate(fanData *types.FanData) {
 	if a.ipcServer != nil {
 		a.ipcServer.BroadcastEvent(ipc.EventFanDataUpdate, fanData)
 	}
+
+	a.publishTelemetry(fanData)
+}
+
+// publishTelemetry 将最新快照写入遥测共享内存和游戏内OSD，未启用时为空操作。
+// fanData为nil时取设备管理器缓存的最新值。
+func (a *CoreApp) publishTelemetry(fanData *types.FanData) {
+	shmOpened := a.telemetry != nil && a.telemetry.Opened()
+	osdOpened := a.overlay != nil && a.overlay.Opened()
+	if !shmOpened && !osdOpened {
+		return
+	}
+	if fanData == nil {
+		fanData = a.deviceManager.GetCurrentFanData()
+	}
+	a.mutex.RLock()
+	temp := a.currentTemp
+	a.mutex.RUnlock()
+
+	if shmOpened {
+		a.telemetry.Publish(shm.Snapshot{
+			UpdateTime:  a.clock.Now().UnixMilli(),
+			Fan:         fanData,
+			Temperature: &temp,
+		})
+	}
+	if osdOpened {
+		a.overlay.Publish(a.overlayText(fanData, temp))
+	}
+}
+
+// activeProfileName 当前生效的模式/挡位名称（OSD与状态端点共用）
+func activeProfileName(cfg types.AppConfig) string {
+	switch {
+	case cfg.CustomSpeedEnabled:
+		return "Custom"
+	case cfg.AutoControl:
+		return "Auto"
+	}
+	return cfg.ManualGear
+}
+
+// overlayText 组装游戏内OSD展示的单行状态文本
+func (a *CoreApp) overlayText(fanData *types.FanData, temp types.TemperatureData) string {
+	rpm := 0
+	if fanData != nil {
+		rpm = int(fanData.CurrentRPM)
+	}
+	return fmt.Sprintf("BS2PRO: CPU %dC GPU %dC | %d RPM | %s",
+		temp.CPUTemp, temp.GPUTemp, rpm, activeProfileName(a.configManager.Get()))
+}
+
+// obsPrevState 进入OBS映射场景前的控制状态快照
+type obsPrevState struct {
+	autoControl bool
+	customSpeed bool
+	gear        string
+	level       string
+}
+
+// onOBSApplyProfile OBS切换到映射场景：记录当前状态并应用场景挡位
+func (a *CoreApp) onOBSApplyProfile(scene, profile string) {
+	if err := a.validateGearSupported(profile); err != nil {
+		a.logError("OBS场景 [%s] 映射的挡位无效: %v", scene, err)
+		return
+	}
+
+	cfg := a.configManager.Get()
+	a.obsPrevMutex.Lock()
+	if a.obsPrev == nil {
+		a.obsPrev = &obsPrevState{
+			autoControl: cfg.AutoControl,
+			customSpeed: cfg.CustomSpeedEnabled,
+			gear:        cfg.ManualGear,
+			level:       cfg.ManualLevel,
+		}
+	}
+	a.obsPrevMutex.Unlock()
+
+	if cfg.AutoControl {
+		if err := a.SetAutoControl(false); err != nil {
+			a.logError("OBS联动关闭智能变频失败: %v", err)
+		}
+	}
+	if cfg.CustomSpeedEnabled {
+		if err := a.SetCustomSpeed(false, cfg.CustomSpeedRPM); err != nil {
+			a.logError("OBS联动关闭自定义转速失败: %v", err)
+		}
+	}
+	a.SetManualGear(profile, cfg.ManualLevel)
+}
+
+// onOBSRestore OBS离开映射场景：恢复进入前的控制状态
+func (a *CoreApp) onOBSRestore() {
+	a.obsPrevMutex.Lock()
+	prev := a.obsPrev
+	a.obsPrev = nil
+	a.obsPrevMutex.Unlock()
+	if prev == nil {
+		return
+	}
+
+	switch {
+	case prev.autoControl:
+		if err := a.SetAutoControl(true); err != nil {
+			a.logError("OBS联动恢复智能变频失败: %v", err)
+		}
+	case prev.customSpeed:
+		cfg := a.configManager.Get()
+		if err := a.SetCustomSpeed(true, cfg.CustomSpeedRPM); err != nil {
+			a.logError("OBS联动恢复自定义转速失败: %v", err)
+		}
+	default:
+		a.SetManualGear(prev.gear, prev.level)
+	}
+}
+
+// statusSnapshot 为本地HTTP状态端点提供当前状态
+func (a *CoreApp) statusSnapshot() statushttp.Status {
+	fanData := a.deviceManager.GetCurrentFanData()
+	a.mutex.RLock()
+	temp := a.currentTemp
+	a.mutex.RUnlock()
+
+	rpm := 0
+	if fanData != nil {
+		rpm = int(fanData.CurrentRPM)
+	}
+	return statushttp.Status{
+		Text:       a.overlayText(fanData, temp),
+		CPUTemp:    temp.CPUTemp,
+		GPUTemp:    temp.GPUTemp,
+		CurrentRPM: rpm,
+		Profile:    activeProfileName(a.configManager.Get()),
+	}
 }
 
 func (a *CoreApp) onDeviceDisconnect() {
@@ -424,18 +1074,23 @@ func (a *CoreApp) onDeviceDisconnect() {
 	a.isConnected = false
 	userDid := a.userDisconnected
 	a.mutex.Unlock()
+	// 快照置空指针，与deviceManager断开后的读取结果保持一致
+	a.fanSnapshot.Store((*types.FanData)(nil))
 
 	if wasConnected {
 		if userDid {
 			a.logInfo("设备连接已主动断开")
+			a.sessions.End("用户主动断开")
 		} else {
 			a.logInfo("设备连接意外断开，将尝试自动重连")
+			a.sessions.End("意外断开")
 		}
 	}
 
 	if a.ipcServer != nil {
 		a.ipcServer.BroadcastEvent(ipc.EventDeviceDisconnected, nil)
 	}
+	a.reconcileMonitoring()
 
 	if !userDid {
 		go a.scheduleReconnect()
@@ -449,8 +1104,13 @@ func (a *CoreApp) scheduleReconnect() {
 		}
 	}()
 
-	retryDelays := []time.Duration{2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second}
-	for i, delay := range retryDelays {
+	policy := a.configManager.Get().ReconnectPolicyOrDefault()
+	for attempt := 1; ; attempt++ {
+		delay, ok := policy.NextDelay(attempt)
+		if !ok {
+			return
+		}
+
 		a.mutex.RLock()
 		connected := a.isConnected
 		a.mutex.RUnlock()
@@ -458,8 +1118,8 @@ func (a *CoreApp) scheduleReconnect() {
 			return
 		}
 
-		a.logInfo("等待 %v 后尝试第 %d 次重连...", delay, i+1)
-		time.Sleep(delay)
+		a.logInfo("等待 %v 后尝试第 %d 次重连...", delay, attempt)
+		a.clock.Sleep(delay)
 
 		a.mutex.RLock()
 		connected = a.isConnected
@@ -477,7 +1137,7 @@ func (a *CoreApp) scheduleReconnect() {
 			}
 			return
 		}
-		a.logError("第 %d 次重连失败", i+1)
+		a.logError("第 %d 次重连失败", attempt)
 	}
 }
 
@@ -491,7 +1151,7 @@ func (a *CoreApp) applyConfigOnConnect() {
 	cfg := a.configManager.Get()
 	a.logInfo("开始应用配置到设备")
 
-	time.Sleep(200 * time.Millisecond)
+	a.clock.Sleep(200 * time.Millisecond)
 
 	if !cfg.AutoControl {
 		if cfg.ManualGear != "" && cfg.ManualLevel != "" {
@@ -500,7 +1160,7 @@ func (a *CoreApp) applyConfigOnConnect() {
 					break
 				}
 				if i < 2 {
-					time.Sleep(100 * time.Millisecond)
+					a.clock.Sleep(100 * time.Millisecond)
 				}
 			}
 		}
@@ -522,16 +1182,21 @@ func (a *CoreApp) applyConfigOnConnect() {
 		a.deviceManager.SetSmartStartStop(cfg.SmartStartStop)
 	}
 
+	if cfg.FirmwareWatchdog {
+		a.deviceManager.SetHostWatchdog(true)
+	}
+
 	if cfg.Brightness > 0 {
 		a.deviceManager.SetBrightness(cfg.Brightness)
 	}
 
 	if cfg.RGBConfig != nil {
 		params := ipc.SetRGBModeParams{
-			Mode:       cfg.RGBConfig.Mode,
-			Colors:     make([]ipc.RGBColorParam, len(cfg.RGBConfig.Colors)),
-			Speed:      cfg.RGBConfig.Speed,
-			Brightness: cfg.RGBConfig.Brightness,
+			Mode:            cfg.RGBConfig.Mode,
+			Colors:          make([]ipc.RGBColorParam, len(cfg.RGBConfig.Colors)),
+			Speed:           cfg.RGBConfig.Speed,
+			Brightness:      cfg.RGBConfig.Brightness,
+			SmartThresholds: cfg.RGBConfig.SmartThresholds,
 		}
 		for i, color := range cfg.RGBConfig.Colors {
 			params.Colors[i] = ipc.RGBColorParam{R: color.R, G: color.G, B: color.B}
@@ -553,11 +1218,16 @@ func (a *CoreApp) ConnectDevice() bool {
 		a.isConnected = true
 		a.mutex.Unlock()
 
+		a.sessions.Begin(deviceInfo)
+		if deviceInfo != nil {
+			a.configManager.RecordDevicePairing(deviceInfo["serial"], deviceInfo["model"])
+		}
+
 		if deviceInfo != nil && a.ipcServer != nil {
 			a.ipcServer.BroadcastEvent(ipc.EventDeviceConnected, deviceInfo)
 		}
 
-		go a.startTemperatureMonitoring()
+		a.reconcileMonitoring()
 		a.applyConfigOnConnect()
 	} else if a.ipcServer != nil {
 		a.ipcServer.BroadcastEvent(ipc.EventDeviceError, "连接失败")
@@ -568,16 +1238,11 @@ func (a *CoreApp) ConnectDevice() bool {
 func (a *CoreApp) DisconnectDevice() {
 	a.mutex.Lock()
 	a.userDisconnected = true
-	if a.monitoringTemp {
-		select {
-		case a.stopMonitoring <- true:
-		default:
-		}
-		a.monitoringTemp = false
-	}
 	a.isConnected = false
 	a.mutex.Unlock()
+	a.reconcileMonitoring()
 
+	a.sessions.End("用户主动断开")
 	a.deviceManager.Disconnect()
 	if a.ipcServer != nil {
 		a.ipcServer.BroadcastEvent(ipc.EventDeviceDisconnected, nil)
@@ -588,32 +1253,281 @@ func (a *CoreApp) GetDeviceStatus() map[string]any {
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
 	return map[string]any{
-		"connected":   a.isConnected,
-		"monitoring":  a.monitoringTemp,
-		"currentData": a.deviceManager.GetCurrentFanData(),
-		"temperature": a.currentTemp,
+		"connected":    a.isConnected,
+		"monitoring":   a.monitoringTemp,
+		"currentData":  a.deviceManager.GetCurrentFanData(),
+		"temperature":  a.currentTemp,
+		"controlState": a.controlStateInfo(),
 	}
 }
 
+// deriveControlState 从当前运行时状态推导控制回路状态及原因
+func (a *CoreApp) deriveControlState() (state, reason string) {
+	if a.safeBoot {
+		return ipc.ControlStateFailsafe, "崩溃循环安全启动，自动调速已停用"
+	}
+
+	a.mutex.RLock()
+	monitoring, connected := a.monitoringTemp, a.isConnected
+	a.mutex.RUnlock()
+	cfg := a.configManager.Get()
+
+	switch {
+	case !cfg.AutoControl:
+		return ipc.ControlStateInactive, "智能变频未开启"
+	case !connected:
+		return ipc.ControlStateInactive, "设备未连接"
+	case !monitoring:
+		return ipc.ControlStateInactive, "温度监控未运行"
+	}
+
+	if temp, ok := a.tempSnapshot.Load().(types.TemperatureData); ok && !temp.BridgeOk {
+		return ipc.ControlStateDegraded, "温度读取不可用，调速暂停生效"
+	}
+	return ipc.ControlStateRunning, ""
+}
+
+// refreshControlState 重新推导控制回路状态，发生变化时记日志并广播事件。
+// 在监控启停、设备连接/断开、配置开关变化及采样循环中调用，幂等且开销很小。
+func (a *CoreApp) refreshControlState() {
+	state, reason := a.deriveControlState()
+
+	a.ctrlStateMutex.Lock()
+	if state == a.controlState && reason == a.controlReason {
+		a.ctrlStateMutex.Unlock()
+		return
+	}
+	a.controlState = state
+	a.controlReason = reason
+	a.controlSince = a.clock.Now().Unix()
+	info := ipc.ControlStateInfo{State: state, Reason: reason, SinceUnix: a.controlSince}
+	a.ctrlStateMutex.Unlock()
+
+	a.logInfo("控制回路状态: %s (%s)", state, reason)
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventControlStateChanged, info)
+	}
+}
+
+// controlStateInfo 当前控制回路状态快照（设备状态/调试信息用）
+func (a *CoreApp) controlStateInfo() ipc.ControlStateInfo {
+	a.ctrlStateMutex.Lock()
+	defer a.ctrlStateMutex.Unlock()
+	state := a.controlState
+	if state == "" {
+		state = ipc.ControlStateInactive
+	}
+	return ipc.ControlStateInfo{State: state, Reason: a.controlReason, SinceUnix: a.controlSince}
+}
+
+// healthReport 汇总各子系统的健康状态。GUI状态栏、看门狗与
+// 状态端点的 /healthz 共用这份数据，整体状态取各组件中最差者（unknown不参与）。
+func (a *CoreApp) healthReport() ipc.HealthReport {
+	now := a.clock.Now().Unix()
+	var components []ipc.ComponentHealth
+
+	// 设备连接
+	a.mutex.RLock()
+	connected := a.isConnected
+	userDisconnected := a.userDisconnected
+	a.mutex.RUnlock()
+	deviceHealth := ipc.ComponentHealth{Name: "device", Status: ipc.HealthOK}
+	if !connected {
+		deviceHealth.Status = ipc.HealthDown
+		deviceHealth.Detail = "设备未连接"
+		if userDisconnected {
+			deviceHealth.Status = ipc.HealthUnknown
+			deviceHealth.Detail = "用户主动断开连接"
+		}
+	}
+	components = append(components, deviceHealth)
+
+	// 温度桥接
+	temp := a.snapshotTemperature()
+	bridgeHealth := ipc.ComponentHealth{Name: "bridge", Status: ipc.HealthOK, LastSuccessUnix: temp.UpdateTime}
+	if !temp.BridgeOk {
+		bridgeHealth.Status = ipc.HealthDegraded
+		bridgeHealth.Detail = temp.BridgeMsg
+	}
+	components = append(components, bridgeHealth)
+
+	// IPC服务器
+	ipcHealth := ipc.ComponentHealth{Name: "ipc", Status: ipc.HealthDown, Detail: "IPC服务器未启动"}
+	if a.ipcServer != nil {
+		ipcHealth.Status = ipc.HealthOK
+		ipcHealth.Detail = fmt.Sprintf("%d 个客户端连接", len(a.ipcServer.ConnectedClients()))
+		ipcHealth.LastSuccessUnix = now
+	}
+	components = append(components, ipcHealth)
+
+	// 控制回路
+	ctrlInfo := a.controlStateInfo()
+	ctrlHealth := ipc.ComponentHealth{Name: "control-loop", Detail: ctrlInfo.Reason, LastSuccessUnix: ctrlInfo.SinceUnix}
+	switch ctrlInfo.State {
+	case ipc.ControlStateRunning, ipc.ControlStateInactive:
+		ctrlHealth.Status = ipc.HealthOK
+	case ipc.ControlStateDegraded:
+		ctrlHealth.Status = ipc.HealthDegraded
+	case ipc.ControlStateFailsafe:
+		ctrlHealth.Status = ipc.HealthDown
+	default:
+		ctrlHealth.Status = ipc.HealthUnknown
+	}
+	components = append(components, ctrlHealth)
+
+	// 配置存储
+	cfgHealth := ipc.ComponentHealth{Name: "config", Status: ipc.HealthOK}
+	if a.configManager != nil {
+		lastErr, lastSaveUnix := a.configManager.SaveHealth()
+		cfgHealth.LastSuccessUnix = lastSaveUnix
+		if lastErr != "" {
+			cfgHealth.Status = ipc.HealthDegraded
+			cfgHealth.Detail = lastErr
+		}
+	}
+	components = append(components, cfgHealth)
+
+	// GUI/托盘：无连接是常态（GUI按需启动），不计入整体状态
+	guiHealth := ipc.ComponentHealth{Name: "gui", Status: ipc.HealthUnknown, Detail: "无GUI连接"}
+	if a.ipcServer != nil && a.ipcServer.HasGUIClients() {
+		guiHealth.Status = ipc.HealthOK
+		guiHealth.Detail = ""
+		guiHealth.LastSuccessUnix = atomic.LoadInt64(&a.guiLastResponse)
+	}
+	components = append(components, guiHealth)
+
+	// 整体状态取最差组件
+	rank := map[string]int{ipc.HealthOK: 0, ipc.HealthUnknown: 0, ipc.HealthDegraded: 1, ipc.HealthDown: 2}
+	overall := ipc.HealthOK
+	for _, c := range components {
+		if c.Status != ipc.HealthUnknown && rank[c.Status] > rank[overall] {
+			overall = c.Status
+		}
+	}
+
+	return ipc.HealthReport{Status: overall, Components: components, TimeUnix: now}
+}
+
 func (a *CoreApp) UpdateConfig(cfg types.AppConfig) error {
 	a.mutex.Lock()
 	oldCfg := a.configManager.Get()
-	shouldStartMonitor := !a.monitoringTemp && a.isConnected && cfg.AutoControl
 	cfg.ConfigPath = oldCfg.ConfigPath
 	err := a.configManager.Update(cfg)
 	a.mutex.Unlock()
-	if shouldStartMonitor {
-		go a.startTemperatureMonitoring()
+	if cfg.DeviceInterfacePath != oldCfg.DeviceInterfacePath {
+		a.deviceManager.SetPreferredPath(cfg.DeviceInterfacePath)
 	}
+	if cfg.LogLanguage != oldCfg.LogLanguage && a.logger != nil {
+		a.logger.SetLanguage(cfg.LogLanguage)
+	}
+	if cfg.FirmwareWatchdog != oldCfg.FirmwareWatchdog {
+		a.deviceManager.SetHostWatchdog(cfg.FirmwareWatchdog)
+	}
+	if cfg.SharedMemoryTelemetry != oldCfg.SharedMemoryTelemetry {
+		if cfg.SharedMemoryTelemetry {
+			if openErr := a.telemetry.Open(); openErr != nil {
+				a.logError("开启遥测共享内存失败: %v", openErr)
+			}
+		} else {
+			a.telemetry.Close()
+		}
+	}
+	if cfg.OverlayOSD != oldCfg.OverlayOSD {
+		if cfg.OverlayOSD {
+			if openErr := a.overlay.Open(); openErr != nil {
+				a.logError("连接RTSS OSD失败: %v", openErr)
+			}
+		} else {
+			a.overlay.Close()
+		}
+	}
+	if cfg.StatusServerEnabled != oldCfg.StatusServerEnabled ||
+		cfg.StatusServerPort != oldCfg.StatusServerPort ||
+		cfg.StatusServerRateSec != oldCfg.StatusServerRateSec {
+		a.statusServer.Stop()
+		if cfg.StatusServerEnabled {
+			if startErr := a.statusServer.Start(cfg.StatusServerPort, cfg.StatusServerRateSec); startErr != nil {
+				a.logError("启动本地状态端点失败: %v", startErr)
+			}
+		}
+	}
+	if cfg.ThermalAlertBreakthrough != oldCfg.ThermalAlertBreakthrough {
+		a.notifier.SetCriticalBreakthrough(cfg.ThermalAlertBreakthrough)
+	}
+	if !reflect.DeepEqual(cfg.FanCurve, oldCfg.FanCurve) {
+		a.publishCurve(cfg.FanCurve)
+	}
+	if !reflect.DeepEqual(cfg.OBSConfig, oldCfg.OBSConfig) {
+		a.obsClient.Stop()
+		if cfg.OBSConfig != nil && cfg.OBSConfig.Enabled {
+			a.obsClient.Start(cfg.OBSConfig.Host, cfg.OBSConfig.Password, cfg.OBSConfig.SceneProfile)
+		}
+	}
+	a.reconcileMonitoring()
 	return err
 }
 
-func (a *CoreApp) SetFanCurve(curve []types.FanCurvePoint) error {
+// curveSnapshot 控制回路读取的不可变曲线快照。点切片发布后不再修改，
+// gen 随每次替换递增，调速决策记录它以便把决策对应到具体一版曲线。
+type curveSnapshot struct {
+	points []types.FanCurvePoint
+	gen    int64
+}
+
+// publishCurve 发布新的曲线快照：深拷贝点列表，代数+1
+func (a *CoreApp) publishCurve(points []types.FanCurvePoint) {
+	var gen int64 = 1
+	if prev, ok := a.curveSnap.Load().(*curveSnapshot); ok && prev != nil {
+		gen = prev.gen + 1
+	}
+	copied := make([]types.FanCurvePoint, len(points))
+	copy(copied, points)
+	a.curveSnap.Store(&curveSnapshot{points: copied, gen: gen})
+}
+
+// currentCurve 获取当前曲线快照，尚未发布时回退到配置中的曲线（代数0）
+func (a *CoreApp) currentCurve() *curveSnapshot {
+	if snap, ok := a.curveSnap.Load().(*curveSnapshot); ok && snap != nil {
+		return snap
+	}
+	return &curveSnapshot{points: a.configManager.Get().FanCurve}
+}
+
+// exportCurveFile 把当前生效曲线打包为.bs2curve分享文件内容
+func (a *CoreApp) exportCurveFile(params ipc.ExportCurveFileParams) (string, error) {
+	meta := curvefile.Metadata{
+		Name:        params.Name,
+		Author:      params.Author,
+		Description: params.Description,
+		DeviceModel: "BS2PRO",
+		AppVersion:  version.Get(),
+	}
+	if meta.Name == "" {
+		meta.Name = "未命名曲线"
+	}
+	if fanData := a.snapshotFanData(); fanData != nil && fanData.MaxGear != "" {
+		meta.DeviceModel = "BS2PRO (" + fanData.MaxGear + ")"
+	}
+
+	content, err := curvefile.Export(a.currentCurve().points, curvefile.InterpolationLinear, meta)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// SetFanCurve 规整并保存温控曲线，返回实际生效的曲线供客户端回显
+func (a *CoreApp) SetFanCurve(curve []types.FanCurvePoint) ([]types.FanCurvePoint, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 	cfg := a.configManager.Get()
-	cfg.FanCurve = curve
-	return a.configManager.Update(cfg)
+	normalized := temperature.NormalizeCurve(curve, cfg.CurveMinTempGap, cfg.CurveMinRPMDelta)
+	if len(normalized) != len(curve) {
+		a.logInfo("曲线规整: %d个点合并为%d个", len(curve), len(normalized))
+	}
+	cfg.FanCurve = normalized
+	a.publishCurve(normalized)
+	return normalized, a.configManager.Update(cfg)
 }
 
 func (a *CoreApp) SetAutoControl(enabled bool) error {
@@ -627,30 +1541,26 @@ func (a *CoreApp) SetAutoControl(enabled bool) error {
 	if enabled {
 		a.userSetAutoControl = true
 	}
-	shouldStartMonitor := enabled && !a.monitoringTemp && a.isConnected
 	a.configManager.Set(cfg)
 	err := a.configManager.Save()
 	isConnected := a.isConnected
 	a.mutex.Unlock()
+	a.reconcileMonitoring()
 
-	// 修复: 在锁外启动 goroutine，避免 startTemperatureMonitoring 锁竞态
-	if shouldStartMonitor {
-		go a.startTemperatureMonitoring()
-	}
 	if !enabled && isConnected {
 		a.safeGo("applyCurrentGearSetting", func() {
-			time.Sleep(200 * time.Millisecond)
+			a.clock.Sleep(200 * time.Millisecond)
 			a.applyCurrentGearSetting()
 		})
 	} else if enabled && isConnected {
 		// 当开启智能变频时（从手动模式切换过来），需要恢复RGB状态
 		a.safeGo("restoreCurrentRGB-autoControl", func() {
-			time.Sleep(300 * time.Millisecond) // 给硬件更多时间切换状态
+			a.clock.Sleep(300 * time.Millisecond) // 给硬件更多时间切换状态
 			a.restoreCurrentRGB()
 		})
 		// 确保进入自动模式，即使温度监控已经在运行
 		a.safeGo("enterAutoMode", func() {
-			time.Sleep(100 * time.Millisecond) // 等待一下再进入自动模式
+			a.clock.Sleep(100 * time.Millisecond) // 等待一下再进入自动模式
 			if err := a.deviceManager.EnterAutoMode(); err != nil {
 				a.logError("进入自动模式失败: %v", err)
 			}
@@ -673,24 +1583,72 @@ func (a *CoreApp) applyCurrentGearSetting() {
 
 	if success && a.isConnected {
 		a.safeGo("restoreCurrentRGB-applyGear", func() {
-			time.Sleep(200 * time.Millisecond)
+			a.clock.Sleep(200 * time.Millisecond)
 			a.restoreCurrentRGB()
 		})
 	}
 }
 
+// validateGearSupported 校验请求的挡位未超过当前设备上报的最高挡位。
+// BS2等低配硬件最高只到强劲，下发超频命令会被硬件静默忽略，
+// 这里提前拦截并给出明确错误，而不是假装成功。
+func (a *CoreApp) validateGearSupported(gear string) error {
+	rank, ok := types.GearRank[gear]
+	if !ok {
+		return fmt.Errorf("未知挡位: %s", gear)
+	}
+
+	fanData := a.deviceManager.GetCurrentFanData()
+	if fanData == nil || fanData.MaxGear == "" {
+		// 设备能力未知时不拦截，保持原有行为
+		return nil
+	}
+	maxRank, ok := types.GearRank[fanData.MaxGear]
+	if !ok {
+		return nil
+	}
+	if rank > maxRank {
+		return fmt.Errorf("挡位 %s 超过设备支持的最高挡位 %s，硬件会忽略该命令", gear, fanData.MaxGear)
+	}
+	return nil
+}
+
+// validateCustomSpeedRPM 校验自定义转速未超过设备能力上限。
+// 标准版(最高挡位较低)的电机控制器收到超限RPM会进入错误状态而不是静默忽略，
+// 默认拒绝并给出明确错误；专家可通过override强行下发，使用记入会话审计日志。
+func (a *CoreApp) validateCustomSpeedRPM(rpm int, override bool) error {
+	fanData := a.deviceManager.GetCurrentFanData()
+	if fanData == nil || fanData.MaxGear == "" {
+		// 设备能力未知时不拦截，保持原有行为
+		return nil
+	}
+	maxRPM := temperature.GearMaxRPM(fanData.MaxGear)
+	if maxRPM <= 0 || rpm <= maxRPM {
+		return nil
+	}
+	if override {
+		a.logInfo("自定义转速 %d RPM 超过设备上限 %d RPM（最高挡位 %s），按专家覆盖放行", rpm, maxRPM, fanData.MaxGear)
+		a.sessions.RecordCommand("SetCustomSpeedOverride", fmt.Sprintf("%d RPM (设备上限 %d)", rpm, maxRPM), true)
+		return nil
+	}
+	return fmt.Errorf("自定义转速 %d RPM 超过设备(最高挡位 %s)可接受的上限 %d RPM，确认硬件可承受可用override强行下发", rpm, fanData.MaxGear, maxRPM)
+}
+
 func (a *CoreApp) SetManualGear(gear, level string) bool {
 	cfg := a.configManager.Get()
 	cfg.ManualGear = gear
 	cfg.ManualLevel = level
 	a.configManager.Update(cfg)
 
+	a.intents.Begin("SetManualGear", gear+"/"+level)
 	success := a.deviceManager.SetManualGear(gear, level)
+	a.intents.Clear()
+	a.sessions.RecordCommand("SetManualGear", gear+"/"+level, success)
 
 	// 当用户主动点击按钮切换到 手动低/中/高时，硬件必定会重置状态
 	if success && a.isConnected {
 		a.safeGo("restoreCurrentRGB-manualGear", func() {
-			time.Sleep(200 * time.Millisecond)
+			a.clock.Sleep(200 * time.Millisecond)
 			a.restoreCurrentRGB()
 		})
 	}
@@ -701,15 +1659,7 @@ func (a *CoreApp) SetCustomSpeed(enabled bool, rpm int) error {
 	a.mutex.Lock()
 	cfg := a.configManager.Get()
 	if enabled {
-		if cfg.AutoControl {
-			cfg.AutoControl = false
-			if a.monitoringTemp {
-				select {
-				case a.stopMonitoring <- true:
-				default:
-				}
-			}
-		}
+		cfg.AutoControl = false
 		cfg.CustomSpeedEnabled = true
 		cfg.CustomSpeedRPM = rpm
 	} else {
@@ -719,10 +1669,14 @@ func (a *CoreApp) SetCustomSpeed(enabled bool, rpm int) error {
 	err := a.configManager.Save()
 	isConnected := a.isConnected
 	a.mutex.Unlock()
+	a.reconcileMonitoring()
 
 	if enabled && isConnected {
 		a.safeGo("setCustomFanSpeed", func() {
-			a.deviceManager.SetCustomFanSpeed(rpm)
+			a.intents.Begin("SetCustomSpeed", fmt.Sprintf("%d RPM", rpm))
+			ok := a.deviceManager.SetCustomFanSpeed(rpm)
+			a.intents.Clear()
+			a.sessions.RecordCommand("SetCustomSpeed", fmt.Sprintf("%d RPM", rpm), ok)
 		})
 	}
 
@@ -732,7 +1686,7 @@ func (a *CoreApp) SetCustomSpeed(enabled bool, rpm int) error {
 
 	if isConnected {
 		a.safeGo("restoreCurrentRGB-customSpeed", func() {
-			time.Sleep(200 * time.Millisecond)
+			a.clock.Sleep(200 * time.Millisecond)
 			a.restoreCurrentRGB()
 		})
 	}
@@ -741,7 +1695,11 @@ func (a *CoreApp) SetCustomSpeed(enabled bool, rpm int) error {
 }
 
 func (a *CoreApp) SetGearLight(enabled bool) bool {
-	if !a.deviceManager.SetGearLight(enabled) {
+	a.intents.Begin("SetGearLight", fmt.Sprintf("%v", enabled))
+	ok := a.deviceManager.SetGearLight(enabled)
+	a.intents.Clear()
+	a.sessions.RecordCommand("SetGearLight", fmt.Sprintf("%v", enabled), ok)
+	if !ok {
 		return false
 	}
 	cfg := a.configManager.Get()
@@ -754,7 +1712,11 @@ func (a *CoreApp) SetGearLight(enabled bool) bool {
 }
 
 func (a *CoreApp) SetPowerOnStart(enabled bool) bool {
-	if !a.deviceManager.SetPowerOnStart(enabled) {
+	a.intents.Begin("SetPowerOnStart", fmt.Sprintf("%v", enabled))
+	ok := a.deviceManager.SetPowerOnStart(enabled)
+	a.intents.Clear()
+	a.sessions.RecordCommand("SetPowerOnStart", fmt.Sprintf("%v", enabled), ok)
+	if !ok {
 		return false
 	}
 	cfg := a.configManager.Get()
@@ -767,7 +1729,11 @@ func (a *CoreApp) SetPowerOnStart(enabled bool) bool {
 }
 
 func (a *CoreApp) SetSmartStartStop(mode string) bool {
-	if !a.deviceManager.SetSmartStartStop(mode) {
+	a.intents.Begin("SetSmartStartStop", mode)
+	ok := a.deviceManager.SetSmartStartStop(mode)
+	a.intents.Clear()
+	a.sessions.RecordCommand("SetSmartStartStop", mode, ok)
+	if !ok {
 		return false
 	}
 	cfg := a.configManager.Get()
@@ -780,7 +1746,11 @@ func (a *CoreApp) SetSmartStartStop(mode string) bool {
 }
 
 func (a *CoreApp) SetBrightness(percentage int) bool {
-	if !a.deviceManager.SetBrightness(percentage) {
+	a.intents.Begin("SetBrightness", fmt.Sprintf("%d", percentage))
+	ok := a.deviceManager.SetBrightness(percentage)
+	a.intents.Clear()
+	a.sessions.RecordCommand("SetBrightness", fmt.Sprintf("%d", percentage), ok)
+	if !ok {
 		return false
 	}
 	cfg := a.configManager.Get()
@@ -815,6 +1785,12 @@ func (a *CoreApp) SetRGBMode(params ipc.SetRGBModeParams) bool {
 
 	// 从deviceManager获取独立的rgbController进行操作
 	rgbCtrl := a.deviceManager.RGB()
+	if rc := a.configManager.Get().RGBConfig; rc != nil {
+		rgbCtrl.SetCrossfadeMs(rc.CrossfadeMs)
+	}
+
+	a.intents.Begin("SetRGBMode", params.Mode)
+	defer a.intents.Clear()
 
 	switch params.Mode {
 	case "smart":
@@ -825,15 +1801,7 @@ func (a *CoreApp) SetRGBMode(params ipc.SetRGBModeParams) bool {
 
 		var level byte = 1
 		if curTemp > 0 {
-			if curTemp < 60 {
-				level = 1
-			} else if curTemp < 85 {
-				level = 2
-			} else if curTemp < 90 {
-				level = 3
-			} else {
-				level = 4
-			}
+			level = a.configManager.Get().RGBConfig.SmartLevelForTemp(curTemp)
 		}
 
 		success = rgbCtrl.SetSmartTempLevel(level)
@@ -877,17 +1845,25 @@ func (a *CoreApp) SetRGBMode(params ipc.SetRGBModeParams) bool {
 		return false
 	}
 
+	a.sessions.RecordCommand("SetRGBMode", params.Mode, success)
+
 	if success {
 		cfg := a.configManager.Get()
 		rgbColors := make([]types.RGBColorConfig, len(params.Colors))
 		for i, c := range params.Colors {
 			rgbColors[i] = types.RGBColorConfig{R: c.R, G: c.G, B: c.B}
 		}
+		// 未携带阈值时沿用现有配置，避免每次切换灯效都把用户自定义阈值冲掉
+		thresholds := params.SmartThresholds
+		if len(thresholds) != 3 && cfg.RGBConfig != nil {
+			thresholds = cfg.RGBConfig.SmartThresholds
+		}
 		cfg.RGBConfig = &types.RGBConfig{
-			Mode:       params.Mode,
-			Colors:     rgbColors,
-			Speed:      params.Speed,
-			Brightness: params.Brightness,
+			Mode:            params.Mode,
+			Colors:          rgbColors,
+			Speed:           params.Speed,
+			Brightness:      params.Brightness,
+			SmartThresholds: thresholds,
 		}
 		a.configManager.Update(cfg)
 		_ = a.configManager.Save()
@@ -906,11 +1882,42 @@ func (a *CoreApp) GetDebugInfo() map[string]any {
 	a.mutex.RUnlock()
 
 	return map[string]any{
-		"debugMode":       debugMode,
-		"isConnected":     isConnected,
-		"guiLastResponse": time.Unix(atomic.LoadInt64(&a.guiLastResponse), 0).Format("2006-01-02 15:04:05"),
-		"monitoringTemp":  monitoringTemp,
-		"hasGUIClients":   a.ipcServer != nil && a.ipcServer.HasClients(),
+		"sessionId":         a.sessionID,
+		"headlessBuild":     headlessBuild,
+		"debugMode":         debugMode,
+		"isConnected":       isConnected,
+		"guiLastResponse":   time.Unix(atomic.LoadInt64(&a.guiLastResponse), 0).Format("2006-01-02 15:04:05"),
+		"monitoringTemp":    monitoringTemp,
+		"hasGUIClients":     a.ipcServer != nil && a.ipcServer.HasGUIClients(),
+		"safeBoot":          a.safeBoot,
+		"guiHangCount":      atomic.LoadInt64(&a.guiHangCount),
+		"effectiveTempRate": atomic.LoadInt64(&a.effectiveTempRate),
+		"configWrites":      a.configManager.WriteCount(),
+		"ipcBroadcast": func() map[string]any {
+			if a.ipcServer == nil {
+				return nil
+			}
+			return a.ipcServer.BroadcastStats()
+		}(),
+		"ipcClients": func() []ipc.ClientInfo {
+			if a.ipcServer == nil {
+				return nil
+			}
+			return a.ipcServer.ConnectedClients()
+		}(),
+		"installationId":  a.configManager.Get().InstallationID,
+		"pairedDevices":   a.configManager.Get().PairedDevices,
+		"reconnectPolicy": a.configManager.Get().ReconnectPolicyOrDefault(),
+		"featureChannel":  a.configManager.FeatureChannel(),
+		"featureFlags":    a.configManager.EffectiveFeatureFlags(),
+		"controlLatency": func() map[string]any {
+			p50, p95, count := a.latency.Percentiles()
+			return map[string]any{
+				"p50Ms":   p50.Milliseconds(),
+				"p95Ms":   p95.Milliseconds(),
+				"samples": count,
+			}
+		}(),
 	}
 }
 
@@ -934,6 +1941,34 @@ func (a *CoreApp) SetDebugMode(enabled bool) error {
 	return nil
 }
 
+// reconcileMonitoring 按期望状态收敛温度监控循环的启停。
+// 所有可能改变期望的路径（连接/断开/配置更新/自定义转速/设备模式回退）
+// 都调用这里而不是各自操作运行标志和停止信号，启停决策只存在这一处；
+// 期望规则本身在 temperature.MonitorDesired 中，可独立测试。
+func (a *CoreApp) reconcileMonitoring() {
+	cfg := a.configManager.Get()
+	a.mutex.Lock()
+	desired := temperature.MonitorDesired(temperature.MonitorState{
+		Connected:        a.isConnected,
+		AutoControl:      cfg.AutoControl,
+		UserDisconnected: a.userDisconnected,
+	})
+	running := a.monitoringTemp
+	a.mutex.Unlock()
+
+	switch {
+	case desired && !running:
+		// 锁外启动goroutine，startTemperatureMonitoring自带重复启动保护
+		go a.startTemperatureMonitoring()
+	case !desired && running:
+		select {
+		case a.stopMonitoring <- true:
+		default:
+		}
+	}
+	a.refreshControlState()
+}
+
 func (a *CoreApp) startTemperatureMonitoring() {
 	a.mutex.Lock()
 	if a.monitoringTemp {
@@ -944,6 +1979,7 @@ func (a *CoreApp) startTemperatureMonitoring() {
 	a.monitoringTemp = true
 	isConnected := a.isConnected
 	a.mutex.Unlock()
+	a.refreshControlState()
 
 	// 清空 stopMonitoring 中可能残留的信号，
 	// 否则新启动的监控goroutine会在第一个select就读到旧信号立即退出
@@ -955,7 +1991,7 @@ func (a *CoreApp) startTemperatureMonitoring() {
 		if err := a.deviceManager.EnterAutoMode(); err != nil {
 			a.logError("进入自动模式失败: %v", err)
 		}
-		time.Sleep(100 * time.Millisecond)
+		a.clock.Sleep(100 * time.Millisecond)
 	}
 
 	cfg := a.configManager.Get()
@@ -966,6 +2002,7 @@ func (a *CoreApp) startTemperatureMonitoring() {
 	}
 	updateInterval := time.Duration(intervalSec) * time.Second
 	ticker := time.NewTicker(updateInterval)
+	atomic.StoreInt64(&a.effectiveTempRate, int64(intervalSec))
 
 	go func() {
 		defer func() {
@@ -976,23 +2013,31 @@ func (a *CoreApp) startTemperatureMonitoring() {
 			a.mutex.Lock()
 			a.monitoringTemp = false
 			a.mutex.Unlock()
+			a.refreshControlState()
 		}()
 
 		sampleCount := max(cfg.TempSampleCount, 1)
 		tempSamples := make([]int, 0, sampleCount)
 		currentIntervalSec := intervalSec
 
+		// 自适应采样的上一次观测值，用于估算升温速率(dT/dt)
+		lastMaxTemp := 0
+		lastSampleTime := time.Time{}
+
 		for {
 			select {
 			case <-a.stopMonitoring:
 				return
 
 			case <-ticker.C:
+				sampleStart := a.clock.Now()
 				temp := a.tempReader.Read()
 
 				a.mutex.Lock()
 				a.currentTemp = temp
 				a.mutex.Unlock()
+				a.tempSnapshot.Store(temp)
+				a.refreshControlState()
 
 				if a.ipcServer != nil {
 					go func(t types.TemperatureData) {
@@ -1000,21 +2045,55 @@ func (a *CoreApp) startTemperatureMonitoring() {
 						a.ipcServer.BroadcastEvent(ipc.EventTemperatureUpdate, t)
 					}(temp)
 				}
+				a.publishTelemetry(nil)
 
 				cfg := a.configManager.Get()
 
-				// 分离式 RGB 智能温控判定
-				if cfg.RGBConfig != nil && cfg.RGBConfig.Mode == "smart" && temp.MaxTemp > 0 {
-					var level byte = 1
-					if temp.MaxTemp < 60 {
-						level = 1
-					} else if temp.MaxTemp < 85 {
-						level = 2
-					} else if temp.MaxTemp < 90 {
-						level = 3
-					} else {
-						level = 4
+				// 持续热降频检测：风扇已满速仍降频说明散热垫已尽力，提示用户检查本体散热
+				fanRPM, maxRPM := 0, 0
+				if fanData := a.deviceManager.GetCurrentFanData(); fanData != nil {
+					fanRPM = int(fanData.CurrentRPM)
+					maxRPM = temperature.GearMaxRPM(fanData.MaxGear)
+				}
+				if a.throttleDetector.Observe(temp.GPUThrottle, temp.MaxTemp, fanRPM, maxRPM) {
+					a.logError("检测到持续热降频: 最高温度 %d°C, 风扇 %d RPM 已接近上限 %d RPM", temp.MaxTemp, fanRPM, maxRPM)
+					if a.ipcServer != nil {
+						a.ipcServer.BroadcastEvent(ipc.EventThrottlingDetected, map[string]any{
+							"maxTemp":     temp.MaxTemp,
+							"gpuThrottle": temp.GPUThrottle,
+							"currentRpm":  fanRPM,
+							"maxRpm":      maxRPM,
+							"message":     "风扇已满速但仍持续热降频，建议检查设备本体散热（清灰/更换硅脂/降低负载）",
+						})
 					}
+					a.notifier.NotifyWithActions(notify.LevelCritical, "持续热降频",
+						fmt.Sprintf("最高温度 %d°C，风扇 %d RPM 已接近上限，建议检查设备本体散热", temp.MaxTemp, fanRPM),
+						[]notify.Action{
+							{ID: notifyActionMaxBoost, Label: "一键狂暴"},
+							{ID: notifyActionDismiss, Label: "忽略"},
+						})
+				}
+
+				// 统一计算采样间隔：响应配置变更，开启自适应采样时按升温速率动态调整
+				desiredSec := cfg.TempUpdateRate
+				if desiredSec < 1 {
+					desiredSec = 1
+				}
+				if cfg.AdaptiveSampling && temp.MaxTemp > 0 {
+					desiredSec = a.adaptiveIntervalSec(cfg, currentIntervalSec,
+						lastMaxTemp, lastSampleTime, temp.MaxTemp)
+					lastMaxTemp = temp.MaxTemp
+					lastSampleTime = a.clock.Now()
+				}
+				if desiredSec != currentIntervalSec {
+					currentIntervalSec = desiredSec
+					ticker.Reset(time.Duration(currentIntervalSec) * time.Second)
+					atomic.StoreInt64(&a.effectiveTempRate, int64(currentIntervalSec))
+				}
+
+				// 分离式 RGB 智能温控判定（阈值可在配置中自定义）
+				if cfg.RGBConfig != nil && cfg.RGBConfig.Mode == "smart" && temp.MaxTemp > 0 {
+					level := cfg.RGBConfig.SmartLevelForTemp(temp.MaxTemp)
 
 					a.mutex.Lock()
 					changed := a.lastSmartModeLevel != level
@@ -1035,15 +2114,6 @@ func (a *CoreApp) startTemperatureMonitoring() {
 						sampleCount = newSampleCount
 						tempSamples = make([]int, 0, sampleCount)
 					}
-					// 动态响应采样间隔配置变更
-					newIntervalSec := cfg.TempUpdateRate
-					if newIntervalSec < 1 {
-						newIntervalSec = 1
-					}
-					if newIntervalSec != currentIntervalSec {
-						currentIntervalSec = newIntervalSec
-						ticker.Reset(time.Duration(currentIntervalSec) * time.Second)
-					}
 					tempSamples = append(tempSamples, temp.MaxTemp)
 					if len(tempSamples) > sampleCount {
 						tempSamples = tempSamples[len(tempSamples)-sampleCount:]
@@ -1054,9 +2124,20 @@ func (a *CoreApp) startTemperatureMonitoring() {
 					}
 					avgTemp = avgTemp / len(tempSamples)
 
-					targetRPM := temperature.CalculateTargetRPM(avgTemp, cfg.FanCurve)
+					// 百分比曲线点按当前设备的最高挡位换算为绝对转速
+					maxGear := ""
+					if fanData := a.deviceManager.GetCurrentFanData(); fanData != nil {
+						maxGear = fanData.MaxGear
+					}
+					snap := a.currentCurve()
+					curve := temperature.ResolveCurve(snap.points, temperature.GearMaxRPM(maxGear))
+
+					targetRPM := temperature.CalculateTargetRPM(avgTemp, curve)
 					if targetRPM > 0 {
+						a.recordControlDecision(temp, avgTemp, curve, snap.gen, targetRPM)
 						a.deviceManager.SetFanSpeed(targetRPM)
+						a.latency.Record(time.Since(sampleStart))
+						a.broadcastCurveLivePoint(avgTemp, targetRPM, snap.gen)
 					}
 				}
 			}
@@ -1064,20 +2145,155 @@ func (a *CoreApp) startTemperatureMonitoring() {
 	}()
 }
 
+// curveLivePointMinInterval 实时工作点事件的最小推送间隔。
+// 控制回路本身至少1秒一拍，这里再设下限防止自适应采样提速后刷爆前端图表
+const curveLivePointMinInterval = 500 * time.Millisecond
+
+// broadcastCurveLivePoint 向GUI推送曲线上的实时工作点（节流后的专用事件，
+// 前端据此在曲线图上画移动圆点，不必从原始风扇数据流自行推算插值）。
+// 仅在温度监控goroutine内调用，lastLivePointAt无需加锁。
+func (a *CoreApp) broadcastCurveLivePoint(avgTemp, targetRPM int, curveGen int64) {
+	if a.ipcServer == nil {
+		return
+	}
+	now := a.clock.Now()
+	if now.Sub(a.lastLivePointAt) < curveLivePointMinInterval {
+		return
+	}
+	a.lastLivePointAt = now
+	a.ipcServer.BroadcastEvent(ipc.EventCurveLivePoint, ipc.CurveLivePoint{
+		AvgTemp:    avgTemp,
+		TargetRPM:  targetRPM,
+		CurveGen:   curveGen,
+		TimeUnixMs: now.UnixMilli(),
+	})
+}
+
+// maxControlDecisions 调速决策日志的最大条数
+const maxControlDecisions = 128
+
+// recordControlDecision 在目标转速发生变化时记录一条调速决策。
+// 目标与上次相同（迟滞保持）时不追加，保证日志只回答"风扇为什么变速"。
+func (a *CoreApp) recordControlDecision(temp types.TemperatureData, avgTemp int, curve []types.FanCurvePoint, curveGen int64, targetRPM int) {
+	a.decisionMutex.Lock()
+	defer a.decisionMutex.Unlock()
+
+	if targetRPM == a.lastDecisionRPM {
+		return
+	}
+
+	// 定位参与插值的曲线段
+	segLow, segHigh := 0, 0
+	for i := 0; i < len(curve)-1; i++ {
+		if avgTemp >= curve[i].Temperature && avgTemp <= curve[i+1].Temperature {
+			segLow, segHigh = curve[i].Temperature, curve[i+1].Temperature
+			break
+		}
+	}
+
+	var reason string
+	switch {
+	case a.lastDecisionRPM == 0:
+		reason = fmt.Sprintf("智能变频首次设定目标转速 %d RPM", targetRPM)
+	case targetRPM > a.lastDecisionRPM:
+		reason = fmt.Sprintf("温度上升，目标转速 %d -> %d RPM", a.lastDecisionRPM, targetRPM)
+	default:
+		reason = fmt.Sprintf("温度下降，目标转速 %d -> %d RPM", a.lastDecisionRPM, targetRPM)
+	}
+
+	a.decisions = append(a.decisions, types.ControlDecision{
+		Time:        a.clock.Now().Unix(),
+		CPUTemp:     temp.CPUTemp,
+		GPUTemp:     temp.GPUTemp,
+		AvgTemp:     avgTemp,
+		SegmentLow:  segLow,
+		SegmentHigh: segHigh,
+		PrevRPM:     a.lastDecisionRPM,
+		TargetRPM:   targetRPM,
+		CurveGen:    curveGen,
+		Reason:      reason,
+	})
+	if len(a.decisions) > maxControlDecisions {
+		a.decisions = a.decisions[len(a.decisions)-maxControlDecisions:]
+	}
+	a.lastDecisionRPM = targetRPM
+}
+
+// GetControlDecisions 获取调速决策日志副本（时间升序）
+func (a *CoreApp) GetControlDecisions() []types.ControlDecision {
+	a.decisionMutex.Lock()
+	defer a.decisionMutex.Unlock()
+
+	out := make([]types.ControlDecision, len(a.decisions))
+	copy(out, a.decisions)
+	return out
+}
+
+// 自适应采样的升温速率阈值(°C/秒)
+const (
+	adaptiveRiseRate   = 0.5 // 超过此速率视为快速升温，收紧到最短间隔
+	adaptiveStableRate = 0.1 // 低于此速率视为温度平稳，逐步放宽间隔
+)
+
+// adaptiveIntervalSec 根据升温速率计算下一个采样间隔(秒)。
+// 快速升温时立即收紧到最短间隔，温度平稳时每周期放宽1秒，介于两者之间保持不变。
+func (a *CoreApp) adaptiveIntervalSec(cfg types.AppConfig, currentSec, lastTemp int, lastTime time.Time, curTemp int) int {
+	minSec := cfg.TempUpdateRateMin
+	if minSec < 1 {
+		minSec = 1
+	}
+	maxSec := cfg.TempUpdateRateMax
+	if maxSec < minSec {
+		maxSec = minSec
+	}
+
+	// 首次采样没有参照，从配置的常规频率开始
+	if lastTime.IsZero() || lastTemp <= 0 {
+		return clampIntervalSec(currentSec, minSec, maxSec)
+	}
+
+	elapsed := time.Since(lastTime).Seconds()
+	if elapsed <= 0 {
+		return clampIntervalSec(currentSec, minSec, maxSec)
+	}
+
+	rate := float64(curTemp-lastTemp) / elapsed
+	switch {
+	case rate >= adaptiveRiseRate:
+		return minSec
+	case rate <= adaptiveStableRate && rate >= -adaptiveStableRate:
+		return clampIntervalSec(currentSec+1, minSec, maxSec)
+	default:
+		return clampIntervalSec(currentSec, minSec, maxSec)
+	}
+}
+
+func clampIntervalSec(sec, minSec, maxSec int) int {
+	if sec < minSec {
+		return minSec
+	}
+	if sec > maxSec {
+		return maxSec
+	}
+	return sec
+}
+
 func (a *CoreApp) startHealthMonitoring() {
 	if a.logger != nil {
 		go a.logger.CleanOldLogs()
 	}
 
-	// 设备健康检查使用指数退避策略
-	baseInterval := 5 * time.Second // 基础探测频率：5秒
-	maxInterval := 30 * time.Second // 最大探测频率：30秒
+	// 设备健康检查使用指数退避策略，间隔由重连策略配置
+	policy := a.configManager.Get().ReconnectPolicyOrDefault()
+	baseInterval := policy.HealthInterval()
+	maxInterval := policy.HealthMaxInterval()
 	currentInterval := baseInterval
 
 	for {
 		select {
-		case <-time.After(currentInterval):
+		case <-a.clock.After(currentInterval):
 			a.checkDeviceHealth(&currentInterval, baseInterval, maxInterval)
+			a.checkGuiHealth()
 		case <-a.cleanupChan:
 			return
 		}
@@ -1129,6 +2345,11 @@ func (a *CoreApp) cleanup() {
 	case a.cleanupChan <- true:
 	default:
 	}
+	select {
+	case <-a.clockWatchStop:
+	default:
+		close(a.clockWatchStop)
+	}
 	if a.logger != nil {
 		a.logger.Close()
 	}
@@ -1160,10 +2381,11 @@ func (a *CoreApp) restoreCurrentRGB() {
 	cfg := a.configManager.Get()
 	if cfg.RGBConfig != nil {
 		params := ipc.SetRGBModeParams{
-			Mode:       cfg.RGBConfig.Mode,
-			Colors:     make([]ipc.RGBColorParam, len(cfg.RGBConfig.Colors)),
-			Speed:      cfg.RGBConfig.Speed,
-			Brightness: cfg.RGBConfig.Brightness,
+			Mode:            cfg.RGBConfig.Mode,
+			Colors:          make([]ipc.RGBColorParam, len(cfg.RGBConfig.Colors)),
+			Speed:           cfg.RGBConfig.Speed,
+			Brightness:      cfg.RGBConfig.Brightness,
+			SmartThresholds: cfg.RGBConfig.SmartThresholds,
 		}
 		for i, color := range cfg.RGBConfig.Colors {
 			params.Colors[i] = ipc.RGBColorParam{R: color.R, G: color.G, B: color.B}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// 高频风扇数据捕获参数
+const (
+	// fanCaptureDefaultSec 未指定时长时的默认捕获时长
+	fanCaptureDefaultSec = 10
+	// fanCaptureMaxSec 捕获时长上限，防止忘记关闭后无限写盘
+	fanCaptureMaxSec = 300
+	// fanCaptureMaxReports 单次捕获的报文数上限（第二道有界保护）
+	fanCaptureMaxReports = 100000
+)
+
+// fanCapture 诊断捕获状态：设备读循环收到的每个原始报文
+// 带时间戳逐行写入JSONL文件，不经过解析路径，无丢包地记录固件行为
+type fanCapture struct {
+	mutex     sync.Mutex
+	active    bool
+	path      string
+	file      *os.File
+	writer    *bufio.Writer
+	count     int
+	stopTimer *time.Timer
+}
+
+// StartFanDataCapture 开启有界时长的高频风扇数据捕获，返回捕获文件路径。
+// 同一时间只允许一次捕获，到达时长或报文数上限后自动停止并广播完成事件。
+func (a *CoreApp) StartFanDataCapture(durationSec int) (string, error) {
+	if durationSec <= 0 {
+		durationSec = fanCaptureDefaultSec
+	}
+	if durationSec > fanCaptureMaxSec {
+		durationSec = fanCaptureMaxSec
+	}
+
+	a.capture.mutex.Lock()
+	defer a.capture.mutex.Unlock()
+
+	if a.capture.active {
+		return "", fmt.Errorf("已有捕获在进行中: %s", a.capture.path)
+	}
+
+	logDir := config.GetLogDir()
+	if a.logger != nil && a.logger.GetLogDir() != "" {
+		logDir = a.logger.GetLogDir()
+	}
+	path := filepath.Join(logDir, fmt.Sprintf("fan_capture_%s.jsonl",
+		a.clock.Now().Format("2006-01-02_15-04-05")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建捕获文件失败: %v", err)
+	}
+
+	a.capture.active = true
+	a.capture.path = path
+	a.capture.file = file
+	a.capture.writer = bufio.NewWriter(file)
+	a.capture.count = 0
+
+	a.deviceManager.SetRawReportTap(a.onRawReportCaptured)
+	a.capture.stopTimer = time.AfterFunc(time.Duration(durationSec)*time.Second, func() {
+		a.StopFanDataCapture("到达捕获时长")
+	})
+
+	a.logInfo("高频风扇数据捕获开始: %s (时长 %d 秒)", path, durationSec)
+	return path, nil
+}
+
+// onRawReportCaptured 设备读循环的旁路回调，逐行记录时间戳与原始报文
+func (a *CoreApp) onRawReportCaptured(report []byte) {
+	a.capture.mutex.Lock()
+	defer a.capture.mutex.Unlock()
+
+	if !a.capture.active {
+		return
+	}
+
+	line := fmt.Sprintf("{\"t\":%q,\"len\":%d,\"data\":%q}\n",
+		a.clock.Now().Format(time.RFC3339Nano), len(report), hex.EncodeToString(report))
+	if _, err := a.capture.writer.WriteString(line); err != nil {
+		a.logError("写入捕获文件失败: %v", err)
+		a.stopFanCaptureLocked("写入失败")
+		return
+	}
+	a.capture.count++
+	if a.capture.count >= fanCaptureMaxReports {
+		a.stopFanCaptureLocked("达到报文数上限")
+	}
+}
+
+// StopFanDataCapture 停止当前捕获，返回是否确有捕获被停止
+func (a *CoreApp) StopFanDataCapture(reason string) bool {
+	a.capture.mutex.Lock()
+	defer a.capture.mutex.Unlock()
+	return a.stopFanCaptureLocked(reason)
+}
+
+// stopFanCaptureLocked 收尾捕获（调用方须持有capture.mutex）：
+// 关闭旁路、落盘、广播完成事件
+func (a *CoreApp) stopFanCaptureLocked(reason string) bool {
+	if !a.capture.active {
+		return false
+	}
+	a.capture.active = false
+
+	if a.capture.stopTimer != nil {
+		a.capture.stopTimer.Stop()
+		a.capture.stopTimer = nil
+	}
+	a.deviceManager.SetRawReportTap(nil)
+
+	if a.capture.writer != nil {
+		a.capture.writer.Flush()
+		a.capture.writer = nil
+	}
+	if a.capture.file != nil {
+		a.capture.file.Close()
+		a.capture.file = nil
+	}
+
+	a.logInfo("高频风扇数据捕获结束(%s): %s，共 %d 条报文", reason, a.capture.path, a.capture.count)
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventFanCaptureDone, map[string]any{
+			"path":    a.capture.path,
+			"reports": a.capture.count,
+			"reason":  reason,
+		})
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/version"
+)
+
+// CLI 退出码约定，供部署脚本和Watchdog判断结果
+const (
+	exitOK         = 0 // 执行成功
+	exitError      = 1 // 执行失败
+	exitNotRunning = 2 // 核心服务未运行或IPC不可达
+)
+
+// handleCLICommand 处理脚本化管理子命令（run/stop/status/version）。
+// 返回true表示命令已处理完毕，main应以exitCode退出；
+// 返回false表示继续走服务运行流程。
+func handleCLICommand(cmd string) (handled bool, exitCode int) {
+	switch cmd {
+	case "version":
+		fmt.Println(version.Get())
+		return true, exitOK
+
+	case "status":
+		status, err := queryCoreStatus()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "核心服务不可达: %v\n", err)
+			return true, exitNotRunning
+		}
+		out, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Println(string(out))
+		return true, exitOK
+
+	case "stop":
+		// 优先通过IPC通知运行中的实例退出（控制台模式下SCM不可用），
+		// IPC不可达时返回false回退到系统服务管理器的stop流程。
+		client := ipc.NewClient(nil)
+		defer client.Close()
+		if _, err := client.SendRequest(ipc.ReqStopService, nil); err != nil {
+			return false, exitOK
+		}
+		fmt.Println("已通知核心服务停止")
+		return true, exitOK
+
+	case "run":
+		// run 为显式前台运行，交回main走默认的s.Run()流程
+		return false, exitOK
+
+	case "--soak", "soak":
+		// 隐藏的浸泡测试模式，可选参数为持续小时数
+		return true, runSoakTest(parseSoakHours(os.Args[2:]))
+
+	case "--replay", "replay":
+		// 隐藏的流量回放模式，参数为录制文件路径和可选速度倍率
+		return true, runReplay(os.Args[2:])
+	}
+	return false, exitOK
+}
+
+// queryCoreStatus 通过IPC读取运行中核心的状态摘要
+func queryCoreStatus() (map[string]any, error) {
+	client := ipc.NewClient(nil)
+	defer client.Close()
+
+	// 声明为CLI客户端，避免短连接被核心误判为有GUI在线
+	_, _ = client.SendRequest(ipc.ReqSetClientMode, ipc.SetClientModeParams{
+		Mode:       ipc.ClientModeFull,
+		ClientType: ipc.ClientTypeCLI,
+	})
+
+	resp, err := client.SendRequest(ipc.ReqGetDeviceStatus, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	var status map[string]any
+	if err := json.Unmarshal(resp.Data, &status); err != nil {
+		return nil, fmt.Errorf("解析状态数据失败: %v", err)
+	}
+	status["version"] = version.Get()
+	status["transport"] = client.ActiveTransport()
+	return status, nil
+}
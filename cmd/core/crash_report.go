@@ -1,14 +1,25 @@
 package main
 
 import (
+	"compress/gzip"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// 崩溃报告保留策略
+const (
+	// crashReportKeepCount 最多保留的崩溃报告数（含已压缩的），超出删除最旧
+	crashReportKeepCount = 20
+	// crashReportCompressAfter 超过该时长的报告压缩为.gz节省空间
+	crashReportCompressAfter = 7 * 24 * time.Hour
 )
 
 func capturePanic(app *CoreApp, source string, recovered any) string {
@@ -53,6 +64,125 @@ func capturePanic(app *CoreApp, source string, recovered any) string {
 	return filePath
 }
 
+// countRecentCrashReports 统计 window 时间窗口内生成的崩溃报告数量，
+// 用于核心服务启动时判断是否陷入崩溃循环。
+func countRecentCrashReports(logDir string, window time.Duration) int {
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		return 0
+	}
+	cutoff := time.Now().Add(-window)
+	count := 0
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "crash_") || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+// isCrashReportName 判断文件名是否为崩溃报告（原始或已压缩）
+func isCrashReportName(name string) bool {
+	return strings.HasPrefix(name, "crash_") &&
+		(strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz"))
+}
+
+// listCrashReports 返回崩溃报告索引，按修改时间从新到旧排列
+func listCrashReports(logDir string) []ipc.CrashReportInfo {
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		return nil
+	}
+	reports := []ipc.CrashReportInfo{}
+	for _, entry := range entries {
+		if !isCrashReportName(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		reports = append(reports, ipc.CrashReportInfo{
+			File:       entry.Name(),
+			TimeUnix:   info.ModTime().Unix(),
+			SizeBytes:  info.Size(),
+			Compressed: strings.HasSuffix(entry.Name(), ".gz"),
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].TimeUnix > reports[j].TimeUnix })
+	return reports
+}
+
+// cleanupCrashReports 执行崩溃报告保留策略：压缩超龄的原始报告，
+// 总数超出上限时删除最旧的。返回压缩与删除的数量供日志记录。
+func cleanupCrashReports(logDir string) (compressed, removed int) {
+	reports := listCrashReports(logDir)
+	cutoff := time.Now().Add(-crashReportCompressAfter)
+
+	for _, r := range reports {
+		if r.Compressed || time.Unix(r.TimeUnix, 0).After(cutoff) {
+			continue
+		}
+		if err := gzipCrashReport(filepath.Join(logDir, r.File)); err == nil {
+			compressed++
+		}
+	}
+
+	// 重新扫描（压缩改变了文件名），删除超出保留数的最旧报告
+	reports = listCrashReports(logDir)
+	for i := crashReportKeepCount; i < len(reports); i++ {
+		if os.Remove(filepath.Join(logDir, reports[i].File)) == nil {
+			removed++
+		}
+	}
+	return compressed, removed
+}
+
+// gzipCrashReport 把报告压缩为同名.gz并删除原文件，保留原修改时间
+func gzipCrashReport(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	gzPath := path + ".gz"
+	out, err := os.Create(gzPath)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(out)
+	if _, err := gw.Write(data); err != nil {
+		gw.Close()
+		out.Close()
+		os.Remove(gzPath)
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		out.Close()
+		os.Remove(gzPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(gzPath)
+		return err
+	}
+
+	// 修改时间承载报告的生成时刻（索引与崩溃循环检测都依赖它），压缩后保留
+	os.Chtimes(gzPath, info.ModTime(), info.ModTime())
+	return os.Remove(path)
+}
+
 func resolveCrashLogDir(app *CoreApp) string {
 	if app != nil && app.logger != nil {
 		if logDir := app.logger.GetLogDir(); logDir != "" {
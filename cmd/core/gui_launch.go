@@ -0,0 +1,44 @@
+//go:build !headless
+
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+)
+
+// headlessBuild 标记本二进制是否为无GUI的headless构建（调试信息用）
+const headlessBuild = false
+
+// guiExeName GUI 可执行文件名（与核心同目录安装）
+const guiExeName = "BS2PRO-Controller.exe"
+
+// killAndRelaunchGui 强杀 GUI 进程并重新拉起
+func (a *CoreApp) killAndRelaunchGui() {
+	a.logInfo("GUI Watchdog: 开始强杀并重启 GUI")
+
+	killCmd := exec.Command("taskkill", "/F", "/IM", guiExeName)
+	killCmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if err := killCmd.Run(); err != nil {
+		a.logError("GUI Watchdog: 强杀 GUI 进程失败: %v", err)
+	}
+
+	a.clock.Sleep(1 * time.Second)
+	if err := a.launchGui("--autostart"); err != nil {
+		a.logError("GUI Watchdog: 重启 GUI 失败: %v", err)
+	} else {
+		a.logInfo("GUI Watchdog: GUI 已重新拉起")
+	}
+}
+
+// launchGui 启动 GUI 进程（带可选命令行参数）
+func (a *CoreApp) launchGui(args ...string) error {
+	exePath := filepath.Join(config.GetInstallDir(), guiExeName)
+	cmd := exec.Command(exePath, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.Start()
+}
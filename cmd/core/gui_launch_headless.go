@@ -0,0 +1,25 @@
+//go:build headless
+
+package main
+
+import "fmt"
+
+// headless构建面向无人值守场景（Windows服务/迷你主机守护进程）：
+// 核心只作为纯后台守护运行，编译期剥离所有拉起GUI进程的路径，
+// 不依赖同目录存在GUI可执行文件。IPC服务照常工作，
+// 需要界面时仍可手动启动GUI或用bs2proctl接入。
+//
+//	go build -tags headless ./cmd/core
+
+// headlessBuild 标记本二进制是否为无GUI的headless构建（调试信息用）
+const headlessBuild = true
+
+// killAndRelaunchGui headless构建不管理GUI进程，仅记录日志
+func (a *CoreApp) killAndRelaunchGui() {
+	a.logInfo("GUI Watchdog: headless构建不拉起GUI，跳过重启")
+}
+
+// launchGui headless构建不包含GUI拉起能力
+func (a *CoreApp) launchGui(args ...string) error {
+	return fmt.Errorf("headless构建不包含GUI拉起能力")
+}
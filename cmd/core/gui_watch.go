@@ -0,0 +1,41 @@
+package main
+
+import (
+	"sync/atomic"
+)
+
+// checkGuiHealth 检测 GUI 是否假死：有客户端连接但长时间没有心跳上报。
+// 根据配置策略仅记录日志或强杀并重启 GUI 进程。
+func (a *CoreApp) checkGuiHealth() {
+	if a.ipcServer == nil || !a.ipcServer.HasGUIClients() {
+		return
+	}
+	if !a.guiMonitorEnabled {
+		return
+	}
+
+	cfg := a.configManager.Get()
+	timeoutSec := cfg.GuiHangTimeoutSec
+	if timeoutSec <= 0 {
+		timeoutSec = 60
+	}
+
+	last := atomic.LoadInt64(&a.guiLastResponse)
+	elapsed := a.clock.Now().Unix() - last
+	if elapsed < int64(timeoutSec) {
+		return
+	}
+
+	count := atomic.AddInt64(&a.guiHangCount, 1)
+	a.logError("GUI Watchdog: 检测到 GUI 假死（%d 秒无心跳，累计 %d 次），处置策略: %s",
+		elapsed, count, cfg.GuiHangPolicy)
+
+	// 重置心跳时间，避免健康检查周期内重复触发
+	atomic.StoreInt64(&a.guiLastResponse, a.clock.Now().Unix())
+
+	if cfg.GuiHangPolicy == "relaunch" {
+		a.safeGo("relaunchGui", func() {
+			a.killAndRelaunchGui()
+		})
+	}
+}
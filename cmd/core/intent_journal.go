@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// intentJournalFile 意图日志文件名，与配置同目录
+const intentJournalFile = "intent_journal.json"
+
+// intentEntry 一次设备写入前记录的意图。
+// 写入确认后文件被删除；启动时文件仍存在说明上次进程在写入中途崩溃，
+// 硬件状态可能与配置不一致，需要按配置重新对账。
+type intentEntry struct {
+	Time   string `json:"time"`   // 记录时间
+	Action string `json:"action"` // 动作名，如 SetManualGear
+	Detail string `json:"detail"` // 动作参数摘要
+}
+
+// intentJournal 设备写入意图日志（单条、同步落盘）
+type intentJournal struct {
+	mutex  sync.Mutex
+	path   string
+	logger types.Logger
+}
+
+// newIntentJournal 创建意图日志，dir为存放目录
+func newIntentJournal(dir string, logger types.Logger) *intentJournal {
+	return &intentJournal{
+		path:   filepath.Join(dir, intentJournalFile),
+		logger: logger,
+	}
+}
+
+// Begin 在设备写入前同步记录意图
+func (j *intentJournal) Begin(action, detail string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entry := intentEntry{
+		Time:   time.Now().Format("2006-01-02 15:04:05"),
+		Action: action,
+		Detail: detail,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(j.path, data, 0644); err != nil && j.logger != nil {
+		j.logger.Debug("写入意图日志失败: %v", err)
+	}
+}
+
+// Clear 设备写入完成（无论成败）后清除意图
+func (j *intentJournal) Clear() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	os.Remove(j.path)
+}
+
+// Pending 读取遗留的未确认意图，没有则返回nil。不负责删除，由调用方Clear。
+func (j *intentJournal) Pending() *intentEntry {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		return nil
+	}
+	var entry intentEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples 延迟采样环形缓冲容量
+const maxLatencySamples = 256
+
+// latencyTracker 记录"温度采样→滤波决策→设备写入完成"的端到端耗时，
+// 用于量化回答"散热垫响应慢半拍"的反馈。
+type latencyTracker struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+	filled  bool
+}
+
+// Record 记录一次端到端延迟
+func (t *latencyTracker) Record(d time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.samples == nil {
+		t.samples = make([]time.Duration, maxLatencySamples)
+	}
+	t.samples[t.next] = d
+	t.next++
+	if t.next >= maxLatencySamples {
+		t.next = 0
+		t.filled = true
+	}
+}
+
+// Percentiles 计算当前采样窗口的p50/p95延迟，无数据时返回0
+func (t *latencyTracker) Percentiles() (p50, p95 time.Duration, count int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	count = t.next
+	if t.filled {
+		count = maxLatencySamples
+	}
+	if count == 0 {
+		return 0, 0, 0
+	}
+
+	sorted := make([]time.Duration, count)
+	copy(sorted, t.samples[:count])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	p50 = sorted[count/2]
+	idx95 := count * 95 / 100
+	if idx95 >= count {
+		idx95 = count - 1
+	}
+	p95 = sorted[idx95]
+	return p50, p95, count
+}
@@ -94,6 +94,12 @@ func main() {
 	// 处理服务相关的命令行指令 (install, uninstall, start, stop 等)
 	if len(os.Args) > 1 {
 		cmd := os.Args[1]
+
+		// 脚本化管理子命令（run/stop/status/version），带约定退出码
+		if handled, exitCode := handleCLICommand(cmd); handled {
+			os.Exit(exitCode)
+		}
+
 		if cmd == "install" || cmd == "uninstall" || cmd == "start" || cmd == "stop" || cmd == "restart" {
 			err = service.Control(s, cmd)
 			if err != nil {
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// 一键狂暴时长限制
+const (
+	maxBoostMinSec = 10
+	maxBoostMaxSec = 1800
+)
+
+// maxBoost 一键狂暴运行状态：立即切到设备支持的最高挡位，
+// 到期后自动恢复进入前的控制模式（复用OBS联动的状态快照结构）
+type maxBoost struct {
+	mutex       sync.Mutex
+	active      bool
+	gear        string
+	endsAt      time.Time
+	revertTimer *time.Timer
+	prev        *obsPrevState
+}
+
+// highestSupportedGear 返回当前设备支持的最高挡位。
+// 设备能力未知时按全量挡位（超频）处理，与validateGearSupported的宽松策略一致。
+func (a *CoreApp) highestSupportedGear() string {
+	best, bestRank := "", 0
+	maxRank := len(types.GearRank)
+	if fanData := a.deviceManager.GetCurrentFanData(); fanData != nil && fanData.MaxGear != "" {
+		if r, ok := types.GearRank[fanData.MaxGear]; ok {
+			maxRank = r
+		}
+	}
+	for gear, rank := range types.GearRank {
+		if rank <= maxRank && rank > bestRank {
+			best, bestRank = gear, rank
+		}
+	}
+	return best
+}
+
+// StartMaxBoost 开启一键狂暴：保存当前控制状态，切到最高挡位高转速，
+// durationSec<=0时取配置默认值。重复开启只刷新倒计时，不重复保存快照。
+// 与曲线测绘双向互斥：测绘进行中强切挡位会污染采样，且两边的恢复快照会互相覆盖。
+func (a *CoreApp) StartMaxBoost(durationSec int) (ipc.MaxBoostStatus, error) {
+	a.mutex.RLock()
+	connected := a.isConnected
+	a.mutex.RUnlock()
+	if !connected {
+		return ipc.MaxBoostStatus{}, fmt.Errorf("设备未连接，无法开启狂暴模式")
+	}
+	a.profile.mutex.Lock()
+	profiling := a.profile.active
+	a.profile.mutex.Unlock()
+	if profiling {
+		return ipc.MaxBoostStatus{}, fmt.Errorf("曲线测绘进行中，请先取消再开启狂暴模式")
+	}
+
+	cfg := a.configManager.Get()
+	if durationSec <= 0 {
+		durationSec = cfg.MaxBoostDurationSec
+	}
+	if durationSec < maxBoostMinSec {
+		durationSec = maxBoostMinSec
+	}
+	if durationSec > maxBoostMaxSec {
+		durationSec = maxBoostMaxSec
+	}
+
+	gear := a.highestSupportedGear()
+	duration := time.Duration(durationSec) * time.Second
+
+	a.boost.mutex.Lock()
+	refresh := a.boost.active
+	if !a.boost.active {
+		a.boost.prev = &obsPrevState{
+			autoControl: cfg.AutoControl,
+			customSpeed: cfg.CustomSpeedEnabled,
+			gear:        cfg.ManualGear,
+			level:       cfg.ManualLevel,
+		}
+		a.boost.active = true
+	}
+	a.boost.gear = gear
+	a.boost.endsAt = a.clock.Now().Add(duration)
+	if a.boost.revertTimer != nil {
+		a.boost.revertTimer.Stop()
+	}
+	a.boost.revertTimer = time.AfterFunc(duration, func() { a.endMaxBoost("到期") })
+	a.boost.mutex.Unlock()
+
+	if refresh {
+		a.logInfo("狂暴模式倒计时刷新: %d秒", durationSec)
+	} else {
+		a.logInfo("开启狂暴模式: 挡位=%s 持续%d秒", gear, durationSec)
+		if cfg.AutoControl {
+			if err := a.SetAutoControl(false); err != nil {
+				a.logError("狂暴模式关闭智能变频失败: %v", err)
+			}
+		}
+		if cfg.CustomSpeedEnabled {
+			if err := a.SetCustomSpeed(false, cfg.CustomSpeedRPM); err != nil {
+				a.logError("狂暴模式关闭自定义转速失败: %v", err)
+			}
+		}
+		a.SetManualGear(gear, "高")
+	}
+
+	status := a.maxBoostStatus()
+	a.broadcastMaxBoost(status)
+	return status, nil
+}
+
+// StopMaxBoost 提前结束狂暴模式，未开启时为空操作
+func (a *CoreApp) StopMaxBoost() ipc.MaxBoostStatus {
+	a.endMaxBoost("手动结束")
+	return a.maxBoostStatus()
+}
+
+// endMaxBoost 结束狂暴模式并恢复进入前的控制状态，恢复优先级与OBS联动一致
+func (a *CoreApp) endMaxBoost(reason string) {
+	a.boost.mutex.Lock()
+	if !a.boost.active {
+		a.boost.mutex.Unlock()
+		return
+	}
+	prev := a.boost.prev
+	a.boost.active = false
+	a.boost.prev = nil
+	a.boost.gear = ""
+	if a.boost.revertTimer != nil {
+		a.boost.revertTimer.Stop()
+		a.boost.revertTimer = nil
+	}
+	a.boost.mutex.Unlock()
+
+	a.logInfo("狂暴模式结束(%s)，恢复之前的控制状态", reason)
+
+	switch {
+	case prev == nil:
+	case prev.autoControl:
+		if err := a.SetAutoControl(true); err != nil {
+			a.logError("狂暴结束恢复智能变频失败: %v", err)
+		}
+	case prev.customSpeed:
+		cfg := a.configManager.Get()
+		if err := a.SetCustomSpeed(true, cfg.CustomSpeedRPM); err != nil {
+			a.logError("狂暴结束恢复自定义转速失败: %v", err)
+		}
+	default:
+		a.SetManualGear(prev.gear, prev.level)
+	}
+
+	a.broadcastMaxBoost(a.maxBoostStatus())
+}
+
+// maxBoostStatus 返回当前狂暴状态快照
+func (a *CoreApp) maxBoostStatus() ipc.MaxBoostStatus {
+	a.boost.mutex.Lock()
+	defer a.boost.mutex.Unlock()
+	if !a.boost.active {
+		return ipc.MaxBoostStatus{}
+	}
+	remaining := int(a.boost.endsAt.Sub(a.clock.Now()).Round(time.Second) / time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return ipc.MaxBoostStatus{
+		Active:       true,
+		Gear:         a.boost.gear,
+		EndsAtUnix:   a.boost.endsAt.Unix(),
+		RemainingSec: remaining,
+	}
+}
+
+// broadcastMaxBoost 向所有客户端推送狂暴状态变化
+func (a *CoreApp) broadcastMaxBoost(status ipc.MaxBoostStatus) {
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventMaxBoostChanged, status)
+	}
+}
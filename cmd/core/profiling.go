@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// 曲线测绘向导时长限制。稳态等待太短测出的是过渡温度，
+// 推荐曲线会整体偏冷；上限防止参数写错后向导占着设备跑一小时
+const (
+	thermalProfileDefaultSettleSec = 90
+	thermalProfileMinSettleSec     = 30
+	thermalProfileMaxSettleSec     = 600
+	thermalProfileDefaultSampleSec = 30
+	thermalProfileMinSampleSec     = 10
+	thermalProfileMaxSampleSec     = 120
+	// thermalProfileSampleIntervalSec 采样窗口内的读温间隔
+	thermalProfileSampleIntervalSec = 2
+)
+
+// thermalProfile 曲线测绘向导运行状态。向导期间接管风扇控制
+// （逐挡扫描），结束或取消后恢复进入前的控制模式（复用OBS联动的状态快照）
+type thermalProfile struct {
+	mutex  sync.Mutex
+	active bool
+	cancel chan struct{}
+	prev   *obsPrevState
+}
+
+// profileStep 向导单步：一个待测挡位及其标称转速
+type profileStep struct {
+	gear  string
+	level string
+	rpm   int
+}
+
+// StartThermalProfile 启动曲线测绘向导：用户先把典型负载跑起来（游戏/压测），
+// 向导按转速从低到高逐挡扫描，每挡等待稳态后采样平均温度，
+// 全部跑完后由测量结果生成推荐曲线并广播done事件。
+// 同一时间只允许一次测绘，且与一键狂暴互斥。
+func (a *CoreApp) StartThermalProfile(settleSec, sampleSec int) error {
+	a.mutex.RLock()
+	connected := a.isConnected
+	a.mutex.RUnlock()
+	if !connected {
+		return fmt.Errorf("设备未连接，无法启动曲线测绘")
+	}
+	if a.maxBoostStatus().Active {
+		return fmt.Errorf("狂暴模式进行中，请先结束再启动曲线测绘")
+	}
+	if temp := a.tempReader.Read(); !temp.BridgeOk {
+		return fmt.Errorf("温度读取不可用(%s)，无法测量稳态温度", temp.BridgeMsg)
+	}
+
+	if settleSec <= 0 {
+		settleSec = thermalProfileDefaultSettleSec
+	}
+	settleSec = clampInt(settleSec, thermalProfileMinSettleSec, thermalProfileMaxSettleSec)
+	if sampleSec <= 0 {
+		sampleSec = thermalProfileDefaultSampleSec
+	}
+	sampleSec = clampInt(sampleSec, thermalProfileMinSampleSec, thermalProfileMaxSampleSec)
+
+	steps := a.profileSteps()
+	if len(steps) == 0 {
+		return fmt.Errorf("未能确定可测绘的挡位列表")
+	}
+
+	cfg := a.configManager.Get()
+
+	a.profile.mutex.Lock()
+	if a.profile.active {
+		a.profile.mutex.Unlock()
+		return fmt.Errorf("已有曲线测绘在进行中")
+	}
+	a.profile.active = true
+	a.profile.cancel = make(chan struct{})
+	a.profile.prev = &obsPrevState{
+		autoControl: cfg.AutoControl,
+		customSpeed: cfg.CustomSpeedEnabled,
+		gear:        cfg.ManualGear,
+		level:       cfg.ManualLevel,
+	}
+	cancel := a.profile.cancel
+	a.profile.mutex.Unlock()
+
+	a.logInfo("曲线测绘开始: %d个挡位，每挡稳态等待%d秒+采样%d秒", len(steps), settleSec, sampleSec)
+
+	// 向导期间由本goroutine独占控制风扇
+	if cfg.AutoControl {
+		if err := a.SetAutoControl(false); err != nil {
+			a.logError("测绘前关闭智能变频失败: %v", err)
+		}
+	}
+	if cfg.CustomSpeedEnabled {
+		if err := a.SetCustomSpeed(false, cfg.CustomSpeedRPM); err != nil {
+			a.logError("测绘前关闭自定义转速失败: %v", err)
+		}
+	}
+
+	a.safeGo("thermalProfileRun", func() {
+		a.runThermalProfile(steps, settleSec, sampleSec, cancel)
+	})
+	return nil
+}
+
+// CancelThermalProfile 取消正在进行的测绘，返回是否确有测绘被取消。
+// 实际的收尾（恢复控制状态、广播done事件）由测绘goroutine完成
+func (a *CoreApp) CancelThermalProfile(reason string) bool {
+	a.profile.mutex.Lock()
+	defer a.profile.mutex.Unlock()
+	if !a.profile.active || a.profile.cancel == nil {
+		return false
+	}
+	select {
+	case <-a.profile.cancel:
+	default:
+		close(a.profile.cancel)
+		a.logInfo("曲线测绘取消请求(%s)", reason)
+	}
+	return true
+}
+
+// profileSteps 返回向导要扫描的挡位列表：设备支持的每个挡位取"中"级别，
+// 按转速从低到高排列。从低往高扫可以让负载温度单调下降，
+// 稳态判断更可靠（升转速降温比降转速升温收敛得快）
+func (a *CoreApp) profileSteps() []profileStep {
+	maxRank := len(types.GearRank)
+	if fanData := a.snapshotFanData(); fanData != nil && fanData.MaxGear != "" {
+		if r, ok := types.GearRank[fanData.MaxGear]; ok {
+			maxRank = r
+		}
+	}
+
+	var steps []profileStep
+	for gear, rank := range types.GearRank {
+		if rank > maxRank {
+			continue
+		}
+		commands := types.GearCommands[gear]
+		if len(commands) < 2 {
+			continue
+		}
+		mid := commands[len(commands)/2]
+		steps = append(steps, profileStep{gear: gear, level: "中", rpm: mid.RPM})
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].rpm < steps[j].rpm })
+	return steps
+}
+
+// runThermalProfile 测绘主循环：逐挡切换→等稳态→采样，结束后恢复状态并广播结果
+func (a *CoreApp) runThermalProfile(steps []profileStep, settleSec, sampleSec int, cancel chan struct{}) {
+	var measurements []ipc.ThermalProfileMeasurement
+	completed := true
+	reason := "完成"
+
+	for i, step := range steps {
+		a.mutex.RLock()
+		connected := a.isConnected
+		a.mutex.RUnlock()
+		if !connected {
+			completed, reason = false, "设备断开"
+			break
+		}
+
+		if !a.SetManualGear(step.gear, step.level) {
+			completed, reason = false, fmt.Sprintf("切换挡位 %s 失败", step.gear)
+			break
+		}
+
+		progress := ipc.ThermalProfileProgress{
+			Phase: "settling", Gear: step.gear, Level: step.level,
+			Step: i + 1, TotalSteps: len(steps), RemainingSec: settleSec,
+		}
+		if !a.profileWait(settleSec, &progress, cancel) {
+			completed, reason = false, "已取消"
+			break
+		}
+
+		m, ok := a.profileSample(step, i+1, len(steps), sampleSec, cancel)
+		if !ok {
+			completed, reason = false, "已取消"
+			break
+		}
+		a.logInfo("曲线测绘: %s%s (%d RPM) 稳态温度 %d°C (%d次采样)",
+			step.gear, step.level, step.rpm, m.AvgTemp, m.Samples)
+		measurements = append(measurements, m)
+	}
+
+	a.finishThermalProfile(measurements, completed, reason)
+}
+
+// profileWait 等待指定秒数，每秒广播一次剩余时间。返回false表示测绘被取消
+func (a *CoreApp) profileWait(seconds int, progress *ipc.ThermalProfileProgress, cancel chan struct{}) bool {
+	for remaining := seconds; remaining > 0; remaining-- {
+		progress.RemainingSec = remaining
+		a.broadcastProfileProgress(*progress)
+		select {
+		case <-cancel:
+			return false
+		case <-time.After(time.Second):
+		}
+	}
+	return true
+}
+
+// profileSample 在采样窗口内周期读温并求最高温度的平均值
+func (a *CoreApp) profileSample(step profileStep, stepNo, totalSteps, sampleSec int, cancel chan struct{}) (ipc.ThermalProfileMeasurement, bool) {
+	m := ipc.ThermalProfileMeasurement{Gear: step.gear, Level: step.level, RPM: step.rpm}
+	sum := 0
+	for remaining := sampleSec; remaining > 0; remaining -= thermalProfileSampleIntervalSec {
+		a.broadcastProfileProgress(ipc.ThermalProfileProgress{
+			Phase: "sampling", Gear: step.gear, Level: step.level,
+			Step: stepNo, TotalSteps: totalSteps, RemainingSec: remaining,
+		})
+		if temp := a.tempReader.Read(); temp.BridgeOk && temp.MaxTemp > 0 {
+			sum += temp.MaxTemp
+			m.Samples++
+		}
+		select {
+		case <-cancel:
+			return m, false
+		case <-time.After(thermalProfileSampleIntervalSec * time.Second):
+		}
+	}
+	if m.Samples > 0 {
+		m.AvgTemp = sum / m.Samples
+	}
+	return m, true
+}
+
+// finishThermalProfile 收尾测绘：恢复进入前的控制状态、生成推荐曲线并广播done事件
+func (a *CoreApp) finishThermalProfile(measurements []ipc.ThermalProfileMeasurement, completed bool, reason string) {
+	a.profile.mutex.Lock()
+	prev := a.profile.prev
+	a.profile.active = false
+	a.profile.cancel = nil
+	a.profile.prev = nil
+	a.profile.mutex.Unlock()
+
+	a.logInfo("曲线测绘结束(%s)，恢复之前的控制状态", reason)
+
+	switch {
+	case prev == nil:
+	case prev.autoControl:
+		if err := a.SetAutoControl(true); err != nil {
+			a.logError("测绘结束恢复智能变频失败: %v", err)
+		}
+	case prev.customSpeed:
+		cfg := a.configManager.Get()
+		if err := a.SetCustomSpeed(true, cfg.CustomSpeedRPM); err != nil {
+			a.logError("测绘结束恢复自定义转速失败: %v", err)
+		}
+	default:
+		a.SetManualGear(prev.gear, prev.level)
+	}
+
+	result := ipc.ThermalProfileResult{
+		Completed:    completed,
+		Reason:       reason,
+		Measurements: measurements,
+	}
+	if completed {
+		result.RecommendedCurve = recommendCurve(measurements)
+	}
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventThermalProfileDone, result)
+	}
+}
+
+// broadcastProfileProgress 向所有客户端推送测绘进度
+func (a *CoreApp) broadcastProfileProgress(progress ipc.ThermalProfileProgress) {
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventThermalProfileProgress, progress)
+	}
+}
+
+// recommendCurve 由稳态测量生成推荐曲线。测量给出的是"负载线"：
+// 转速越高稳态温度越低，在(温度,转速)平面上是一条下降的点列，不能直接当曲线用。
+// 这里把温度升序与转速升序按下标配对：最冷的稳态温度（最高挡测得）配最低转速，
+// 最热的稳态温度（最低挡测得）配最高转速。任何递增曲线与下降的负载线只有一个交点，
+// 这样配对保证平衡点落在测量温度区间内，且温度达到最弱挡的稳态值之前风扇已到全速。
+// 额外补一个低温端怠速点，让空闲时风扇保持安静。
+func recommendCurve(measurements []ipc.ThermalProfileMeasurement) []types.FanCurvePoint {
+	var valid []ipc.ThermalProfileMeasurement
+	for _, m := range measurements {
+		if m.Samples > 0 && m.AvgTemp > 0 {
+			valid = append(valid, m)
+		}
+	}
+	if len(valid) < 2 {
+		return nil
+	}
+
+	temps := make([]int, len(valid))
+	rpms := make([]int, len(valid))
+	for i, m := range valid {
+		temps[i] = m.AvgTemp
+		rpms[i] = m.RPM
+	}
+	sort.Ints(temps)
+	sort.Ints(rpms)
+
+	idleTemp := temps[0] - 10
+	if idleTemp < 30 {
+		idleTemp = 30
+	}
+	curve := []types.FanCurvePoint{{Temperature: idleTemp, RPM: rpms[0] * 2 / 3}}
+	for i := range valid {
+		curve = append(curve, types.FanCurvePoint{Temperature: temps[i], RPM: rpms[i]})
+	}
+
+	// 测量噪声可能产生相同或乱序的温度点，保留温度严格递增的子序列。
+	// SetFanCurve应用时还会做一次完整规整，这里只保证结构合法
+	deduped := curve[:1]
+	for _, p := range curve[1:] {
+		if p.Temperature > deduped[len(deduped)-1].Temperature {
+			deduped = append(deduped, p)
+		}
+	}
+	if len(deduped) < 2 {
+		return nil
+	}
+	return deduped
+}
+
+// clampInt 把v截断到[lo, hi]区间
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
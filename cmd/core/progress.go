@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// opSeq 操作实例ID的进程内递增序号，保证同毫秒启动的同类操作也不撞ID
+var opSeq atomic.Int64
+
+// opProgress 单次长操作的进度上报器。多秒操作在开头创建一个，
+// 各阶段调用Report，收尾调用Finish——成功失败都发Done事件，
+// 前端的进度条不会因为操作中途出错而挂在半截。
+type opProgress struct {
+	app       *CoreApp
+	operation string
+	id        string
+}
+
+// newOpProgress 创建进度上报器并广播0%的起始事件
+func (a *CoreApp) newOpProgress(operation string) *opProgress {
+	p := &opProgress{
+		app:       a,
+		operation: operation,
+		id:        fmt.Sprintf("%s-%d-%d", operation, a.clock.Now().UnixMilli(), opSeq.Add(1)),
+	}
+	p.Report("start", 0, "")
+	return p
+}
+
+// Report 广播一次阶段进度，percent取0-100，不确定时传-1
+func (p *opProgress) Report(phase string, percent int, message string) {
+	p.broadcast(ipc.OperationProgress{
+		OperationID: p.id,
+		Operation:   p.operation,
+		Phase:       phase,
+		Percent:     percent,
+		Message:     message,
+	})
+}
+
+// Finish 广播操作结束事件，err非nil时附带错误说明
+func (p *opProgress) Finish(err error) {
+	progress := ipc.OperationProgress{
+		OperationID: p.id,
+		Operation:   p.operation,
+		Phase:       "done",
+		Percent:     100,
+		Done:        true,
+	}
+	if err != nil {
+		progress.Error = err.Error()
+	}
+	p.broadcast(progress)
+}
+
+func (p *opProgress) broadcast(progress ipc.OperationProgress) {
+	if p.app.ipcServer != nil {
+		p.app.ipcServer.BroadcastEvent(ipc.EventOperationProgress, progress)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// IPC流量录制参数
+const (
+	// ipcRecordDefaultSec 未指定时长时的默认录制时长
+	ipcRecordDefaultSec = 600
+	// ipcRecordMaxSec 录制时长上限，防止忘记关闭后无限写盘
+	ipcRecordMaxSec = 7200
+	// ipcRecordMaxRecords 单次录制的记录数上限（第二道有界保护）
+	ipcRecordMaxRecords = 200000
+)
+
+// ipcRecorder 诊断录制状态：IPC服务器收到的每个请求和发出的每个广播
+// 经脱敏后带时间戳逐行写入JSONL文件，之后可用 --replay 对着无设备的
+// 核心实例精确重放，复现难以重现的GUI/核心状态不同步问题
+type ipcRecorder struct {
+	mutex     sync.Mutex
+	active    bool
+	path      string
+	file      *os.File
+	writer    *bufio.Writer
+	count     int
+	stopTimer *time.Timer
+}
+
+// StartIPCRecording 开启有界时长的IPC流量录制，返回录制文件路径。
+// 同一时间只允许一次录制，到达时长或记录数上限后自动停止并广播完成事件。
+func (a *CoreApp) StartIPCRecording(durationSec int) (string, error) {
+	if durationSec <= 0 {
+		durationSec = ipcRecordDefaultSec
+	}
+	if durationSec > ipcRecordMaxSec {
+		durationSec = ipcRecordMaxSec
+	}
+
+	a.recorder.mutex.Lock()
+	defer a.recorder.mutex.Unlock()
+
+	if a.recorder.active {
+		return "", fmt.Errorf("已有录制在进行中: %s", a.recorder.path)
+	}
+	if a.ipcServer == nil {
+		return "", fmt.Errorf("IPC服务器未启动")
+	}
+
+	logDir := config.GetLogDir()
+	if a.logger != nil && a.logger.GetLogDir() != "" {
+		logDir = a.logger.GetLogDir()
+	}
+	path := filepath.Join(logDir, fmt.Sprintf("ipc_traffic_%s.jsonl",
+		a.clock.Now().Format("2006-01-02_15-04-05")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建录制文件失败: %v", err)
+	}
+
+	a.recorder.active = true
+	a.recorder.path = path
+	a.recorder.file = file
+	a.recorder.writer = bufio.NewWriter(file)
+	a.recorder.count = 0
+
+	a.ipcServer.SetTrafficTap(a.onTrafficRecorded)
+	a.recorder.stopTimer = time.AfterFunc(time.Duration(durationSec)*time.Second, func() {
+		a.StopIPCRecording("到达录制时长")
+	})
+
+	a.logInfo("IPC流量录制开始: %s (时长 %d 秒)", path, durationSec)
+	return path, nil
+}
+
+// onTrafficRecorded IPC服务器的流量旁路回调，逐行写入脱敏后的记录
+func (a *CoreApp) onTrafficRecorded(rec ipc.TrafficRecord) {
+	a.recorder.mutex.Lock()
+	defer a.recorder.mutex.Unlock()
+
+	if !a.recorder.active {
+		return
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		a.logError("序列化流量记录失败: %v", err)
+		return
+	}
+	if _, err := a.recorder.writer.Write(append(line, '\n')); err != nil {
+		a.logError("写入录制文件失败: %v", err)
+		a.stopIPCRecordingLocked("写入失败")
+		return
+	}
+	a.recorder.count++
+	if a.recorder.count >= ipcRecordMaxRecords {
+		a.stopIPCRecordingLocked("达到记录数上限")
+	}
+}
+
+// StopIPCRecording 停止当前录制，返回是否确有录制被停止
+func (a *CoreApp) StopIPCRecording(reason string) bool {
+	a.recorder.mutex.Lock()
+	defer a.recorder.mutex.Unlock()
+	return a.stopIPCRecordingLocked(reason)
+}
+
+// stopIPCRecordingLocked 收尾录制（调用方须持有recorder.mutex）：
+// 关闭旁路、落盘、广播完成事件。完成事件在旁路关闭后广播，不会录到自己。
+func (a *CoreApp) stopIPCRecordingLocked(reason string) bool {
+	if !a.recorder.active {
+		return false
+	}
+	a.recorder.active = false
+
+	if a.recorder.stopTimer != nil {
+		a.recorder.stopTimer.Stop()
+		a.recorder.stopTimer = nil
+	}
+	if a.ipcServer != nil {
+		a.ipcServer.SetTrafficTap(nil)
+	}
+
+	if a.recorder.writer != nil {
+		a.recorder.writer.Flush()
+		a.recorder.writer = nil
+	}
+	if a.recorder.file != nil {
+		a.recorder.file.Close()
+		a.recorder.file = nil
+	}
+
+	a.logInfo("IPC流量录制结束(%s): %s，共 %d 条记录", reason, a.recorder.path, a.recorder.count)
+	if a.ipcServer != nil {
+		a.ipcServer.BroadcastEvent(ipc.EventIPCRecordingDone, map[string]any{
+			"path":    a.recorder.path,
+			"records": a.recorder.count,
+			"reason":  reason,
+		})
+	}
+	return true
+}
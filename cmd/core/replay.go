@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+// 回放参数
+const (
+	// replayMaxGap 相邻请求的最大等待间隔，录制中的长空窗压缩到此值
+	replayMaxGap = 5 * time.Second
+	// replayScannerBuf 单条记录的大小上限（完整配置对象可能较大）
+	replayScannerBuf = 1 << 20
+)
+
+// replaySkipRequests 回放时跳过的请求类型：
+// 停服/重启会终结回放进程本身，录制控制请求会让回放套娃录制
+var replaySkipRequests = map[ipc.RequestType]bool{
+	ipc.ReqStopService:       true,
+	ipc.ReqRestartService:    true,
+	ipc.ReqQuitApp:           true,
+	ipc.ReqStartIPCRecording: true,
+	ipc.ReqStopIPCRecording:  true,
+}
+
+// replayReport 回放结果摘要
+type replayReport struct {
+	File        string  `json:"file"`
+	Records     int     `json:"records"`     // 文件中的总记录数
+	Replayed    int     `json:"replayed"`    // 实际重放的请求数
+	Skipped     int     `json:"skipped"`     // 按类型跳过的请求数
+	Events      int     `json:"events"`      // 事件记录数（仅用于还原节奏，不回放）
+	Failures    int     `json:"failures"`    // 返回失败的请求数（设备不在时属预期）
+	ParseErrors int     `json:"parseErrors"` // 无法解析的记录数
+	DurationSec int64   `json:"durationSec"` // 回放耗时
+	SpeedFactor float64 `json:"speedFactor"` // 使用的速度倍率
+}
+
+// runReplay 流量回放模式（隐藏的 --replay 参数）：
+// 读取StartIPCRecording生成的JSONL文件，把其中的请求按原始相对节奏
+// 重新灌入一个完整核心实例，用于离线复现GUI/核心状态不同步问题。
+// 与浸泡测试一样不要求设备在场：请求会失败返回，但配置/广播/会话
+// 记录等完整路径都会走到，日志与崩溃检测照常工作。
+// 可选第二个参数为速度倍率（如 10 表示按10倍速回放，0 表示不等待）。
+func runReplay(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "用法: --replay <录制文件> [速度倍率]")
+		return exitError
+	}
+	path := args[0]
+	speed := parseReplaySpeed(args[1:])
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "打开录制文件失败: %v\n", err)
+		return exitError
+	}
+	defer file.Close()
+
+	fmt.Printf("流量回放启动: %s (速度倍率 %.1f)\n", path, speed)
+
+	app := NewCoreApp(true)
+	if err := app.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "核心启动失败: %v\n", err)
+		return exitError
+	}
+	defer app.Stop()
+
+	// 预热：与录制环境一致，等启动期goroutine就绪后再开始灌请求
+	time.Sleep(3 * time.Second)
+
+	report := replayReport{File: path, SpeedFactor: speed}
+	start := time.Now()
+	var lastMs int64
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), replayScannerBuf)
+	for scanner.Scan() {
+		report.Records++
+
+		var rec ipc.TrafficRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			report.ParseErrors++
+			continue
+		}
+		if rec.Dir != "request" {
+			report.Events++
+			continue
+		}
+
+		// 按录制时间戳还原请求间隔，长空窗压缩、速度倍率缩放
+		if lastMs > 0 && speed > 0 && rec.TimeUnixMs > lastMs {
+			gap := time.Duration(float64(rec.TimeUnixMs-lastMs)/speed) * time.Millisecond
+			if gap > replayMaxGap {
+				gap = replayMaxGap
+			}
+			time.Sleep(gap)
+		}
+		lastMs = rec.TimeUnixMs
+
+		reqType := ipc.RequestType(rec.Type)
+		if replaySkipRequests[reqType] {
+			report.Skipped++
+			continue
+		}
+
+		resp := app.handleIPCRequest(ipc.Request{Type: reqType, Data: rec.Data, TraceID: rec.TraceID})
+		report.Replayed++
+		if !resp.Success {
+			report.Failures++
+			app.logInfo("回放请求失败: %s (trace %s): %s", rec.Type, rec.TraceID, resp.Error)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "读取录制文件失败: %v\n", err)
+		return exitError
+	}
+
+	report.DurationSec = int64(time.Since(start).Seconds())
+	data, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(data))
+	return exitOK
+}
+
+// parseReplaySpeed 解析 --replay 的可选速度倍率参数，非法值回退为1倍速
+func parseReplaySpeed(args []string) float64 {
+	if len(args) == 0 {
+		return 1
+	}
+	speed, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || speed < 0 {
+		return 1
+	}
+	return speed
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// sessionLogFile 设备会话记录文件名（JSONL，每行一个完整会话）
+const sessionLogFile = "device_sessions.jsonl"
+
+// maxSessionCommands 单个会话最多保留的命令条数，防止长会话无限膨胀
+const maxSessionCommands = 200
+
+// maxSessionHistory 查询时返回的历史会话上限
+const maxSessionHistory = 50
+
+// SessionCommand 会话中下发的单条设备命令及结果
+type SessionCommand struct {
+	Time    int64  `json:"time"`             // 命令时间戳(Unix秒)
+	Command string `json:"command"`          // 命令名称
+	Detail  string `json:"detail,omitempty"` // 参数摘要
+	Success bool   `json:"success"`          // 执行结果
+}
+
+// DeviceSession 一次设备连接会话的结构化记录
+type DeviceSession struct {
+	StartTime        int64             `json:"startTime"`                  // 连接时间戳(Unix秒)
+	EndTime          int64             `json:"endTime,omitempty"`          // 断开时间戳(进行中为0)
+	DeviceInfo       map[string]string `json:"deviceInfo,omitempty"`       // 连接时的设备信息
+	Commands         []SessionCommand  `json:"commands"`                   // 会话期间下发的命令
+	DisconnectReason string            `json:"disconnectReason,omitempty"` // 断开原因
+}
+
+// sessionRecorder 记录设备会话并持久化为JSONL，补充普通日志用于事后分析
+type sessionRecorder struct {
+	mutex   sync.Mutex
+	current *DeviceSession
+	logDir  string
+	logger  types.Logger
+}
+
+func newSessionRecorder(logDir string, logger types.Logger) *sessionRecorder {
+	return &sessionRecorder{
+		logDir: logDir,
+		logger: logger,
+	}
+}
+
+// Begin 开始一个新会话。已有未结束的会话时先以"被新连接取代"收尾。
+func (r *sessionRecorder) Begin(deviceInfo map[string]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.current != nil {
+		r.finishLocked("被新连接取代")
+	}
+	r.current = &DeviceSession{
+		StartTime:  time.Now().Unix(),
+		DeviceInfo: deviceInfo,
+	}
+}
+
+// RecordCommand 记录会话期间下发的一条设备命令
+func (r *sessionRecorder) RecordCommand(command, detail string, success bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.current == nil {
+		return
+	}
+	r.current.Commands = append(r.current.Commands, SessionCommand{
+		Time:    time.Now().Unix(),
+		Command: command,
+		Detail:  detail,
+		Success: success,
+	})
+	if len(r.current.Commands) > maxSessionCommands {
+		r.current.Commands = r.current.Commands[len(r.current.Commands)-maxSessionCommands:]
+	}
+}
+
+// End 结束当前会话并追加写入JSONL文件
+func (r *sessionRecorder) End(reason string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.finishLocked(reason)
+}
+
+func (r *sessionRecorder) finishLocked(reason string) {
+	if r.current == nil {
+		return
+	}
+	r.current.EndTime = time.Now().Unix()
+	r.current.DisconnectReason = reason
+
+	line, err := json.Marshal(r.current)
+	r.current = nil
+	if err != nil {
+		return
+	}
+
+	path := filepath.Join(r.logDir, sessionLogFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		if r.logger != nil {
+			r.logger.Error("写入设备会话记录失败: %v", err)
+		}
+		return
+	}
+	defer f.Close()
+	f.Write(append(line, '\n'))
+}
+
+// Sessions 返回历史会话(最多maxSessionHistory条)及进行中的当前会话
+func (r *sessionRecorder) Sessions() []DeviceSession {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var sessions []DeviceSession
+
+	path := filepath.Join(r.logDir, sessionLogFile)
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var s DeviceSession
+			if err := json.Unmarshal(scanner.Bytes(), &s); err == nil {
+				sessions = append(sessions, s)
+			}
+		}
+		f.Close()
+	}
+
+	if len(sessions) > maxSessionHistory {
+		sessions = sessions[len(sessions)-maxSessionHistory:]
+	}
+	if r.current != nil {
+		sessions = append(sessions, *r.current)
+	}
+	return sessions
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// 浸泡测试参数
+const (
+	// soakOpInterval 相邻随机操作的间隔
+	soakOpInterval = 50 * time.Millisecond
+	// soakGoroutineMargin 结束时允许的goroutine增量（事件广播等短命goroutine的余量）
+	soakGoroutineMargin = 10
+	// soakReportFile 报告文件名，写入日志目录
+	soakReportFile = "soak_report.json"
+)
+
+// soakReport 浸泡测试结果报告
+type soakReport struct {
+	StartTime       string         `json:"startTime"`
+	EndTime         string         `json:"endTime"`
+	DurationSec     int64          `json:"durationSec"`
+	Ops             map[string]int `json:"ops"`             // 各操作执行次数
+	OpErrors        int            `json:"opErrors"`        // 操作返回错误的次数（不算失败，设备未连接时属预期）
+	Panics          int            `json:"panics"`          // 操作中捕获的panic次数
+	GoroutinesStart int            `json:"goroutinesStart"` // 预热后的goroutine基线
+	GoroutinesMax   int            `json:"goroutinesMax"`
+	GoroutinesEnd   int            `json:"goroutinesEnd"`
+	Passed          bool           `json:"passed"`
+	FailReason      string         `json:"failReason,omitempty"`
+}
+
+// runSoakTest 浸泡测试模式（隐藏的 --soak 参数）：
+// 启动完整核心后随机执行有界操作（挡位切换、曲线更新、RGB切换、模拟断连），
+// 持续指定小时数，期间监控goroutine数量并捕获panic，结束后输出报告。
+// 设备未连接时各操作会失败返回，但仍会走完配置/广播/会话记录等完整路径。
+func runSoakTest(hours float64) int {
+	if hours <= 0 {
+		hours = 1
+	}
+
+	fmt.Printf("浸泡测试启动: 时长 %.1f 小时\n", hours)
+
+	app := NewCoreApp(true)
+	if err := app.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "核心启动失败: %v\n", err)
+		return exitError
+	}
+	defer app.Stop()
+
+	// 预热：等待启动期goroutine（延迟连接、健康监控）就绪后再取基线
+	time.Sleep(3 * time.Second)
+
+	report := soakReport{
+		StartTime:       time.Now().Format("2006-01-02 15:04:05"),
+		Ops:             make(map[string]int),
+		GoroutinesStart: runtime.NumGoroutine(),
+	}
+	report.GoroutinesMax = report.GoroutinesStart
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	start := time.Now()
+	deadline := start.Add(time.Duration(hours * float64(time.Hour)))
+
+	for time.Now().Before(deadline) {
+		op := soakOps[rng.Intn(len(soakOps))]
+		report.Ops[op.name]++
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					report.Panics++
+					app.logError("浸泡测试操作 %s panic: %v", op.name, r)
+				}
+			}()
+			if !op.run(app, rng) {
+				report.OpErrors++
+			}
+		}()
+
+		if g := runtime.NumGoroutine(); g > report.GoroutinesMax {
+			report.GoroutinesMax = g
+		}
+		time.Sleep(soakOpInterval)
+	}
+
+	// 静置让广播/重连等短命goroutine退出后再取终值
+	time.Sleep(5 * time.Second)
+	report.GoroutinesEnd = runtime.NumGoroutine()
+	report.EndTime = time.Now().Format("2006-01-02 15:04:05")
+	report.DurationSec = int64(time.Since(start).Seconds())
+
+	report.Passed = true
+	if report.Panics > 0 {
+		report.Passed = false
+		report.FailReason = fmt.Sprintf("捕获到 %d 次panic", report.Panics)
+	} else if report.GoroutinesEnd > report.GoroutinesStart+soakGoroutineMargin {
+		report.Passed = false
+		report.FailReason = fmt.Sprintf("goroutine疑似泄漏: 基线 %d -> 结束 %d",
+			report.GoroutinesStart, report.GoroutinesEnd)
+	}
+
+	writeSoakReport(app, report)
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(data))
+	if !report.Passed {
+		return exitError
+	}
+	return exitOK
+}
+
+// writeSoakReport 将报告写入日志目录
+func writeSoakReport(app *CoreApp, report soakReport) {
+	logDir := config.GetLogDir()
+	if app.logger != nil && app.logger.GetLogDir() != "" {
+		logDir = app.logger.GetLogDir()
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return
+	}
+	reportPath := filepath.Join(logDir, soakReportFile)
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "写入浸泡报告失败: %v\n", err)
+	} else {
+		fmt.Printf("浸泡报告已写入: %s\n", reportPath)
+	}
+}
+
+// soakOp 一种随机操作，run返回操作是否成功（失败计入OpErrors统计）
+type soakOp struct {
+	name string
+	run  func(app *CoreApp, rng *rand.Rand) bool
+}
+
+var soakGears = []string{"静音", "标准", "强劲", "超频"}
+var soakLevels = []string{"低", "中", "高"}
+var soakRGBModes = []string{"off", "smart", "static_single", "rotation", "breathing", "flowing"}
+
+var soakOps = []soakOp{
+	{"SetManualGear", func(app *CoreApp, rng *rand.Rand) bool {
+		return app.SetManualGear(soakGears[rng.Intn(len(soakGears))], soakLevels[rng.Intn(len(soakLevels))])
+	}},
+	{"SetFanCurve", func(app *CoreApp, rng *rand.Rand) bool {
+		points := 2 + rng.Intn(4)
+		curve := make([]types.FanCurvePoint, points)
+		temp := 30 + rng.Intn(10)
+		for i := range curve {
+			curve[i] = types.FanCurvePoint{
+				Temperature: temp,
+				RPM:         1000 + rng.Intn(31)*100,
+			}
+			temp += 5 + rng.Intn(10)
+		}
+		_, err := app.SetFanCurve(curve)
+		return err == nil
+	}},
+	{"UpdateConfig", func(app *CoreApp, rng *rand.Rand) bool {
+		cfg := app.configManager.Get()
+		cfg.Brightness = rng.Intn(101)
+		cfg.TempUpdateRate = 1 + rng.Intn(5)
+		return app.UpdateConfig(cfg) == nil
+	}},
+	{"SetRGBMode", func(app *CoreApp, rng *rand.Rand) bool {
+		return app.SetRGBMode(ipc.SetRGBModeParams{
+			Mode:       soakRGBModes[rng.Intn(len(soakRGBModes))],
+			Brightness: rng.Intn(101),
+			Speed:      "medium",
+			Colors:     []ipc.RGBColorParam{{R: rng.Intn(256), G: rng.Intn(256), B: rng.Intn(256)}},
+		})
+	}},
+	{"SetAutoControl", func(app *CoreApp, rng *rand.Rand) bool {
+		return app.SetAutoControl(rng.Intn(2) == 0) == nil
+	}},
+	{"GetDebugInfo", func(app *CoreApp, rng *rand.Rand) bool {
+		return app.GetDebugInfo() != nil
+	}},
+	{"SimulateDisconnect", func(app *CoreApp, rng *rand.Rand) bool {
+		// 模拟意外断连，触发重连调度路径（设备不在时重连最终失败，属预期）
+		app.onDeviceDisconnect()
+		return true
+	}},
+}
+
+// parseSoakHours 解析 --soak 的可选小时数参数
+func parseSoakHours(args []string) float64 {
+	if len(args) == 0 {
+		return 1
+	}
+	hours, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || hours <= 0 {
+		return 1
+	}
+	return hours
+}
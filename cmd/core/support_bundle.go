@@ -0,0 +1,122 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/version"
+)
+
+// CreateSupportBundle 打包一份支持包zip到日志目录并返回路径。
+// 内容：清单、调试信息快照、健康报告、脱敏后的配置、崩溃报告索引、
+// 当天的核心日志以及最近一份崩溃报告——用户报障时一个附件带齐全部现场。
+// 打包可能持续数秒（日志文件大时更久），进度经operation-progress事件上报
+func (a *CoreApp) CreateSupportBundle() (string, error) {
+	op := a.newOpProgress("support-bundle")
+	path, err := a.createSupportBundle(op)
+	op.Finish(err)
+	return path, err
+}
+
+// createSupportBundle 实际的打包流程，各阶段经op上报进度
+func (a *CoreApp) createSupportBundle(op *opProgress) (string, error) {
+	logDir := config.GetLogDir()
+	if a.logger != nil && a.logger.GetLogDir() != "" {
+		logDir = a.logger.GetLogDir()
+	}
+
+	now := a.clock.Now()
+	path := filepath.Join(logDir, fmt.Sprintf("support_bundle_%s.zip", now.Format("2006-01-02_15-04-05")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建支持包失败: %v", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+
+	manifest := map[string]any{
+		"version":        version.Get(),
+		"sessionId":      a.sessionID,
+		"installationId": a.configManager.Get().InstallationID,
+		"createdUnix":    now.Unix(),
+		"created":        now.Format(time.RFC3339),
+	}
+	if err := writeBundleJSON(zw, "manifest.json", manifest); err != nil {
+		return "", err
+	}
+	op.Report("snapshots", 20, "采集调试信息与健康快照")
+	if err := writeBundleJSON(zw, "debug.json", a.GetDebugInfo()); err != nil {
+		return "", err
+	}
+	if err := writeBundleJSON(zw, "health.json", a.healthReport()); err != nil {
+		return "", err
+	}
+	op.Report("config", 40, "打包脱敏配置")
+
+	// 配置脱敏后打包：远程访问令牌绝不随支持包外传
+	cfg := a.configManager.Get()
+	cfg.RemoteAccessToken = ""
+	if err := writeBundleJSON(zw, "config.json", cfg); err != nil {
+		return "", err
+	}
+
+	op.Report("crash-reports", 60, "附加崩溃报告")
+	reports := listCrashReports(logDir)
+	if err := writeBundleJSON(zw, "crash_reports.json", reports); err != nil {
+		return "", err
+	}
+	if len(reports) > 0 {
+		if err := writeBundleFile(zw, logDir, reports[0].File); err != nil {
+			a.logError("支持包附加崩溃报告失败: %v", err)
+		}
+	}
+
+	op.Report("logs", 80, "附加当天核心日志")
+	coreLog := fmt.Sprintf("core_%s.log", now.Format("2006-01-02"))
+	if err := writeBundleFile(zw, logDir, coreLog); err != nil {
+		a.logError("支持包附加核心日志失败: %v", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("写入支持包失败: %v", err)
+	}
+	a.logInfo("支持包已生成: %s", path)
+	return path, nil
+}
+
+// writeBundleJSON 把对象以缩进JSON写入zip条目
+func writeBundleJSON(zw *zip.Writer, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 %s 失败: %v", name, err)
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("创建zip条目 %s 失败: %v", name, err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeBundleFile 把日志目录下的文件原样复制进zip，文件不存在时返回错误
+func writeBundleFile(zw *zip.Writer, dir, name string) error {
+	src, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
@@ -0,0 +1,48 @@
+package main
+
+import "time"
+
+// 时钟监控参数
+const (
+	// clockCheckInterval 挂钟漂移检测周期
+	clockCheckInterval = 30 * time.Second
+	// clockJumpThreshold 单调时钟与挂钟的漂移差超过该值判定为时钟跳变
+	clockJumpThreshold = 5 * time.Second
+)
+
+// watchClockChanges 监控系统挂钟突变（手动改时间、夏令时切换、休眠唤醒补偿）。
+// 服务进程没有消息窗口，收不到WM_TIMECHANGE，改用漂移差检测：
+// Go的time.Now同时携带单调读数，Sub直接得到单调间隔，Round(0)剥离单调部分
+// 后相减得到挂钟间隔，两者之差超过阈值即说明挂钟被调整过。
+// 周期性间隔（健康检查、重连退避等）本身基于Go计时器的单调时钟，不受跳变影响；
+// 受影响的是按挂钟日期命名的日志文件与按修改时间清理的旧日志，
+// 跳变或跨天时触发日志滚动与清理的重新评估。
+func (a *CoreApp) watchClockChanges() {
+	// 此处刻意使用真实时钟而非a.clock：检测对象正是真实挂钟与单调时钟的偏差
+	ref := time.Now()
+	for {
+		select {
+		case <-a.clock.After(clockCheckInterval):
+			monoElapsed := time.Since(ref)
+			wallElapsed := time.Now().Round(0).Sub(ref.Round(0))
+			drift := wallElapsed - monoElapsed
+			if drift < 0 {
+				drift = -drift
+			}
+
+			if drift > clockJumpThreshold {
+				a.logInfo("检测到系统时钟跳变（挂钟漂移 %v），重新评估日志滚动与清理", drift.Round(time.Second))
+				if a.logger != nil {
+					a.logger.CheckRollover()
+					go a.logger.CleanOldLogs()
+				}
+			} else if a.logger != nil {
+				// 正常跨天（含夏令时整点切换）时换到新日期文件
+				a.logger.CheckRollover()
+			}
+			ref = time.Now()
+		case <-a.clockWatchStop:
+			return
+		}
+	}
+}
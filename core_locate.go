@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/sys/windows/registry"
+)
+
+// coreServiceExeName 核心服务可执行文件名（安装器产物）
+const coreServiceExeName = "BS2PRO-CoreService.exe"
+
+// coreInstallLocationKey 安装器写入的卸载信息注册表键，InstallLocation记录安装目录
+const coreInstallLocationKey = `Software\Microsoft\Windows\CurrentVersion\Uninstall\BS2PRO-Controller`
+
+// corePathFileName 手动指定核心路径的持久化文件（与config.json同目录）
+const corePathFileName = "core_path.txt"
+
+// CoreServiceError 核心服务不可用的结构化错误，供前端展示排障指引
+type CoreServiceError struct {
+	Message     string   `json:"message"`     // 错误摘要
+	Searched    []string `json:"searched"`    // 已搜索过的位置
+	Remediation []string `json:"remediation"` // 建议的处理步骤
+}
+
+// corePathFilePath 核心路径持久化文件的完整路径
+func corePathFilePath() string {
+	programData := os.Getenv("PROGRAMDATA")
+	if programData == "" {
+		return filepath.Join(config.GetInstallDir(), "config", corePathFileName)
+	}
+	return filepath.Join(programData, "BS2PRO-Controller", corePathFileName)
+}
+
+// loadUserCorePath 读取用户手动指定的核心路径，文件不存在或路径失效返回空
+func loadUserCorePath() string {
+	data, err := os.ReadFile(corePathFilePath())
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(data))
+	if path == "" {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// saveUserCorePath 持久化用户手动指定的核心路径
+func saveUserCorePath(path string) error {
+	filePath := corePathFilePath()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, []byte(path), 0644)
+}
+
+// readRegistryInstallDir 从安装器写入的注册表项读取安装目录
+func readRegistryInstallDir() string {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, coreInstallLocationKey, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer key.Close()
+
+	installDir, _, err := key.GetStringValue("InstallLocation")
+	if err != nil {
+		return ""
+	}
+	return installDir
+}
+
+// locateCoreService 按优先级搜索核心服务可执行文件：
+// 用户手动指定路径 > GUI同目录 > 注册表记录的安装目录 > 系统PATH。
+// 返回找到的路径和已搜索过的位置列表（用于错误提示）。
+func locateCoreService() (string, []string) {
+	var searched []string
+
+	if userPath := loadUserCorePath(); userPath != "" {
+		return userPath, searched
+	}
+
+	installPath := filepath.Join(config.GetInstallDir(), coreServiceExeName)
+	searched = append(searched, installPath)
+	if _, err := os.Stat(installPath); err == nil {
+		return installPath, searched
+	}
+
+	if regDir := readRegistryInstallDir(); regDir != "" {
+		regPath := filepath.Join(regDir, coreServiceExeName)
+		searched = append(searched, regPath)
+		if _, err := os.Stat(regPath); err == nil {
+			return regPath, searched
+		}
+	}
+
+	searched = append(searched, "PATH")
+	if pathExe, err := exec.LookPath(coreServiceExeName); err == nil {
+		return pathExe, searched
+	}
+
+	return "", searched
+}
+
+// ensureCoreServiceRunning 确保核心服务在运行：
+// 已运行直接返回；否则按搜索路径定位可执行文件并尝试拉起（优先SCM，失败回退前台进程），
+// 全部失败时返回结构化错误。
+func (a *App) ensureCoreServiceRunning() *CoreServiceError {
+	if ipc.CheckCoreServiceRunning() {
+		return nil
+	}
+
+	corePath, searched := locateCoreService()
+	if corePath == "" {
+		guiLogger.Errorf("未找到核心服务可执行文件，已搜索: %v", searched)
+		return &CoreServiceError{
+			Message:  fmt.Sprintf("未找到核心服务 %s", coreServiceExeName),
+			Searched: searched,
+			Remediation: []string{
+				"确认安装完整，必要时重新运行安装程序",
+				"若核心服务被移动到其他目录，请在设置中手动指定其位置",
+				"检查杀毒软件是否误删或隔离了核心服务文件",
+			},
+		}
+	}
+
+	guiLogger.Infof("尝试启动核心服务: %s", corePath)
+
+	// 优先走SCM启动（服务已安装的正常情况），失败时回退为前台进程直接拉起
+	if err := exec.Command(corePath, "start").Run(); err != nil {
+		guiLogger.Warnf("通过服务管理器启动核心失败: %v，回退为直接启动", err)
+		if err := exec.Command(corePath).Start(); err != nil {
+			return &CoreServiceError{
+				Message:  fmt.Sprintf("核心服务启动失败: %v", err),
+				Searched: []string{corePath},
+				Remediation: []string{
+					"以管理员身份运行控制台后重试",
+					"查看核心服务日志确认启动失败原因",
+				},
+			}
+		}
+	}
+
+	// 等待IPC端点就绪
+	for i := 0; i < 10; i++ {
+		if ipc.CheckCoreServiceRunning() {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return &CoreServiceError{
+		Message:  "核心服务已启动但IPC端点未就绪",
+		Searched: []string{corePath},
+		Remediation: []string{
+			"稍候片刻后重试连接",
+			"查看核心服务日志确认是否启动异常",
+		},
+	}
+}
+
+// BrowseForCoreService 弹出文件选择框让用户手动指定核心服务位置，
+// 选定后持久化该路径并尝试启动核心、重建IPC连接。返回选择的路径，取消时返回空。
+func (a *App) BrowseForCoreService() string {
+	if a.ctx == nil {
+		return ""
+	}
+
+	selected, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择核心服务程序",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "核心服务程序", Pattern: coreServiceExeName},
+			{DisplayName: "可执行文件 (*.exe)", Pattern: "*.exe"},
+		},
+	})
+	if err != nil || selected == "" {
+		return ""
+	}
+
+	if err := saveUserCorePath(selected); err != nil {
+		guiLogger.Errorf("保存核心服务路径失败: %v", err)
+	} else {
+		guiLogger.Infof("已手动指定核心服务路径: %s", selected)
+	}
+
+	go func() {
+		if coreErr := a.ensureCoreServiceRunning(); coreErr != nil {
+			runtime.EventsEmit(a.ctx, "core-service-missing", coreErr)
+			return
+		}
+		if err := a.ipcClient.Connect(); err == nil {
+			a.ipcClient.SetEventHandler(a.handleCoreEvent)
+			a.handshakeClientMode()
+			runtime.EventsEmit(a.ctx, "core-service-connected", nil)
+		}
+	}()
+
+	return selected
+}
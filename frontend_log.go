@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 前端错误上报防护参数。LogFrontendError的入参完全由前端页面控制，
+// 出错循环或被注入的页面可能高频上报超长字符串，这里做硬性限流防止刷爆磁盘
+const (
+	// frontendLogMaxMessageBytes 单条消息部分的大小上限，超出截断
+	frontendLogMaxMessageBytes = 1024
+	// frontendLogMaxEntryBytes 单条目（消息+堆栈）的总大小上限
+	frontendLogMaxEntryBytes = 4096
+	// frontendLogRatePerMin 每分钟写入日志的条数上限，超出直接丢弃
+	frontendLogRatePerMin = 60
+	// frontendLogDedupWindow 相同消息在此窗口内只写一次，重复计数
+	frontendLogDedupWindow = time.Minute
+)
+
+// frontendLogGuard 前端错误上报的防护状态与统计计数
+type frontendLogGuard struct {
+	mutex       sync.Mutex
+	windowStart time.Time // 限流窗口起点
+	windowCount int       // 当前窗口内已写入条数
+	lastMessage string    // 最近一次写入的消息（去重比对用）
+	lastSeen    time.Time // 最近一次收到该消息的时刻
+	dupStreak   int       // 当前消息的连续重复次数
+
+	// 统计计数，经GetDebugInfo暴露给调试页
+	total     int64 // 收到的上报总数
+	written   int64 // 实际写入日志的条数
+	truncated int64 // 因超长被截断的条数
+	deduped   int64 // 因窗口内重复被合并的条数
+	dropped   int64 // 因限流被丢弃的条数
+}
+
+// LogFrontendError 接收前端上报的JS错误，写入gui日志文件。
+// 入参不可信：超长截断、重复合并、超速丢弃，统计见GetDebugInfo的frontendLogStats。
+func (a *App) LogFrontendError(level, source, message, stack string) {
+	if guiLogger == nil {
+		return
+	}
+
+	g := &a.feGuard
+	g.mutex.Lock()
+	g.total++
+	now := time.Now()
+
+	// 窗口内重复消息只写第一条，重复计数在消息变化时补记一行
+	if message == g.lastMessage && now.Sub(g.lastSeen) < frontendLogDedupWindow {
+		g.dupStreak++
+		g.deduped++
+		g.lastSeen = now
+		g.mutex.Unlock()
+		return
+	}
+	suppressed := g.dupStreak
+	g.dupStreak = 0
+	g.lastMessage = message
+	g.lastSeen = now
+
+	// 固定窗口限流：超出配额的上报直接丢弃（计数仍然累加）
+	if now.Sub(g.windowStart) >= time.Minute {
+		g.windowStart = now
+		g.windowCount = 0
+	}
+	if g.windowCount >= frontendLogRatePerMin {
+		g.dropped++
+		g.mutex.Unlock()
+		return
+	}
+	g.windowCount++
+
+	// 截断超长输入：消息与堆栈分别限长，保证单条目有界
+	truncated := false
+	if len(message) > frontendLogMaxMessageBytes {
+		message = message[:frontendLogMaxMessageBytes] + "...[截断]"
+		truncated = true
+	}
+	if maxStack := frontendLogMaxEntryBytes - len(message); len(stack) > maxStack {
+		if maxStack < 0 {
+			maxStack = 0
+		}
+		stack = stack[:maxStack] + "...[截断]"
+		truncated = true
+	}
+	if truncated {
+		g.truncated++
+	}
+	g.written++
+	g.mutex.Unlock()
+
+	if suppressed > 0 {
+		guiLogger.Info("[frontend] 上一条消息在去重窗口内重复 %d 次", suppressed)
+	}
+
+	entry := fmt.Sprintf("[frontend][%s] %s\n  stack: %s", source, message, stack)
+	switch level {
+	case "warn":
+		guiLogger.Warn(entry)
+	case "crash", "error":
+		guiLogger.Error(entry)
+	default:
+		guiLogger.Info(entry)
+	}
+}
+
+// frontendLogStats 返回前端错误上报的防护统计快照
+func (a *App) frontendLogStats() map[string]any {
+	g := &a.feGuard
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return map[string]any{
+		"total":     g.total,
+		"written":   g.written,
+		"truncated": g.truncated,
+		"deduped":   g.deduped,
+		"dropped":   g.dropped,
+	}
+}
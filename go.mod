@@ -5,6 +5,7 @@ go 1.26.0
 require (
 	fyne.io/systray v1.12.0
 	github.com/Microsoft/go-winio v0.6.2
+	github.com/gorilla/websocket v1.5.3
 	github.com/kardianos/service v1.2.4
 	github.com/shirou/gopsutil/v4 v4.26.1
 	github.com/sstallion/go-hid v0.15.0
@@ -20,7 +21,6 @@ require (
 	github.com/go-ole/go-ole v1.3.0 // indirect
 	github.com/godbus/dbus/v5 v5.1.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/jchv/go-winloader v0.0.0-20210711035445-715c2860da7e // indirect
 	github.com/labstack/echo/v4 v4.13.3 // indirect
 	github.com/labstack/gommon v0.4.2 // indirect
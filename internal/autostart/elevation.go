@@ -0,0 +1,107 @@
+package autostart
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// 完整性级别SID的RID区间起点（SECURITY_MANDATORY_*_RID）
+const (
+	mandatoryUntrustedRID = 0x0000
+	mandatoryLowRID       = 0x1000
+	mandatoryMediumRID    = 0x2000
+	mandatoryHighRID      = 0x3000
+	mandatorySystemRID    = 0x4000
+)
+
+// ElevationStatus 当前进程的提权状态详情。单个"是否管理员"布尔
+// 在UAC拆分令牌（管理员账号、受限令牌运行）的场景下会误导前端：
+// 看似没权限，实际一次UAC确认就能提权。前端据此给出正确的引导：
+// CanElevate为true时提示"以管理员身份重启"，否则提示需要管理员账号。
+type ElevationStatus struct {
+	Elevated       bool   `json:"elevated"`         // 当前令牌是否已提权
+	ElevationType  string `json:"elevationType"`    // default(无UAC拆分)/full(已提权)/limited(受限令牌)
+	IntegrityLevel string `json:"integrityLevel"`   // untrusted/low/medium/high/system
+	CanElevate     bool   `json:"canElevate"`       // 存在关联的完整令牌，经UAC确认即可提权
+	Detail         string `json:"detail,omitempty"` // 查询失败时的错误说明
+}
+
+// GetElevationStatus 查询当前进程令牌的提权详情。
+// 只读取进程自身令牌，不触碰WMI，失败时降级为仅Elevated可信
+func GetElevationStatus() ElevationStatus {
+	token := windows.GetCurrentProcessToken()
+	status := ElevationStatus{Elevated: token.IsElevated()}
+
+	elevType, err := tokenElevationType(token)
+	if err != nil {
+		status.Detail = fmt.Sprintf("查询令牌提权类型失败: %v", err)
+		return status
+	}
+	switch elevType {
+	case 1: // TokenElevationTypeDefault：未开UAC或内置Administrator等无拆分场景
+		status.ElevationType = "default"
+	case 2: // TokenElevationTypeFull：已提权的完整令牌
+		status.ElevationType = "full"
+	case 3: // TokenElevationTypeLimited：管理员账号的受限令牌，UAC可换完整令牌
+		status.ElevationType = "limited"
+		status.CanElevate = true
+	default:
+		status.ElevationType = fmt.Sprintf("unknown(%d)", elevType)
+	}
+
+	if level, err := tokenIntegrityLevel(token); err != nil {
+		if status.Detail == "" {
+			status.Detail = fmt.Sprintf("查询完整性级别失败: %v", err)
+		}
+	} else {
+		status.IntegrityLevel = level
+	}
+	return status
+}
+
+// tokenElevationType 读取令牌的TokenElevationType信息
+func tokenElevationType(token windows.Token) (uint32, error) {
+	var elevType uint32
+	var outLen uint32
+	err := windows.GetTokenInformation(token, windows.TokenElevationType,
+		(*byte)(unsafe.Pointer(&elevType)), uint32(unsafe.Sizeof(elevType)), &outLen)
+	if err != nil {
+		return 0, err
+	}
+	return elevType, nil
+}
+
+// tokenIntegrityLevel 读取令牌完整性级别SID并映射为级别名
+func tokenIntegrityLevel(token windows.Token) (string, error) {
+	var outLen uint32
+	// 第一次调用取所需缓冲区大小，预期返回 ERROR_INSUFFICIENT_BUFFER
+	err := windows.GetTokenInformation(token, windows.TokenIntegrityLevel, nil, 0, &outLen)
+	if err != windows.ERROR_INSUFFICIENT_BUFFER {
+		if err == nil {
+			return "", fmt.Errorf("意外的零长度令牌信息")
+		}
+		return "", err
+	}
+	buf := make([]byte, outLen)
+	if err := windows.GetTokenInformation(token, windows.TokenIntegrityLevel, &buf[0], outLen, &outLen); err != nil {
+		return "", err
+	}
+
+	label := (*windows.Tokenmandatorylabel)(unsafe.Pointer(&buf[0]))
+	sid := label.Label.Sid
+	rid := sid.SubAuthority(uint32(sid.SubAuthorityCount() - 1))
+	switch {
+	case rid >= mandatorySystemRID:
+		return "system", nil
+	case rid >= mandatoryHighRID:
+		return "high", nil
+	case rid >= mandatoryMediumRID:
+		return "medium", nil
+	case rid >= mandatoryLowRID:
+		return "low", nil
+	default: // mandatoryUntrustedRID 起的区间
+		return "untrusted", nil
+	}
+}
@@ -6,17 +6,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
 )
 
-// Manager 配置管理器
+// Manager 配置管理器。
+// Save/Get/Set会被IPC处理器、温控循环和托盘回调并发调用，
+// 内部用读写锁保护，避免写入交错丢字段。
 type Manager struct {
+	mutex      sync.RWMutex
 	config     types.AppConfig
 	installDir string
 	logger     types.Logger
+
+	// 写入合并去抖：高频更新（如拖动亮度滑块）只在静默期后落盘一次
+	dirty      bool
+	saveTimer  *time.Timer
+	writeCount int64 // 累计磁盘写入次数（诊断用）
+
+	// 最近一次落盘结果（健康检查用）
+	lastSaveErr  string
+	lastSaveUnix int64
+
+	// 更新通道下发的功能开关默认值，Load时读取一次
+	featureDefaults FeatureDefaults
 }
 
+// saveDebounce 配置更新后的落盘去抖时长
+const saveDebounce = 500 * time.Millisecond
+
 // NewManager 创建新的配置管理器
 func NewManager(installDir string, logger types.Logger) *Manager {
 	return &Manager{
@@ -27,6 +47,12 @@ func NewManager(installDir string, logger types.Logger) *Manager {
 
 // Load 加载配置
 func (m *Manager) Load(isAutoStart bool) types.AppConfig {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// 更新通道的功能开关默认值与配置一同加载
+	m.loadFeatureDefaults()
+
 	// 优先尝试从默认目录加载配置
 	defaultConfigDir := m.GetDefaultConfigDir()
 	defaultConfigPath := filepath.Join(defaultConfigDir, "config.json")
@@ -55,7 +81,7 @@ func (m *Manager) Load(isAutoStart bool) types.AppConfig {
 
 	m.config = types.GetDefaultConfig(isAutoStart)
 	m.config.ConfigPath = defaultConfigPath
-	if err := m.Save(); err != nil {
+	if err := m.saveLocked(); err != nil {
 		m.logError("保存默认配置失败: %v", err)
 	}
 
@@ -87,6 +113,35 @@ func (m *Manager) tryLoadFromPath(configPath string) bool {
 
 // Save 保存配置
 func (m *Manager) Save() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.saveLocked()
+}
+
+// saveLocked 落盘并维护脏标记与写入计数，调用方必须已持有写锁
+func (m *Manager) saveLocked() error {
+	err := m.writeConfigFile()
+	if err == nil {
+		m.dirty = false
+		m.writeCount++
+		m.lastSaveUnix = time.Now().Unix()
+		m.lastSaveErr = ""
+	} else {
+		m.lastSaveErr = err.Error()
+	}
+	return err
+}
+
+// SaveHealth 返回最近一次落盘的结果（健康检查用）：
+// 最近错误（空表示正常）与最近成功落盘的时间戳（0表示本次启动尚未写过）
+func (m *Manager) SaveHealth() (lastErr string, lastSuccessUnix int64) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.lastSaveErr, m.lastSaveUnix
+}
+
+// writeConfigFile 执行实际的磁盘写入，调用方必须已持有写锁
+func (m *Manager) writeConfigFile() error {
 	// 首先尝试保存到默认目录
 	defaultConfigDir := m.GetDefaultConfigDir()
 	defaultConfigPath := filepath.Join(defaultConfigDir, "config.json")
@@ -149,18 +204,71 @@ func (m *Manager) GetDefaultConfigDir() string {
 
 // Get 获取当前配置
 func (m *Manager) Get() types.AppConfig {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	return m.config
 }
 
 // Set 设置配置
 func (m *Manager) Set(config types.AppConfig) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.config = config
 }
 
-// Update 更新配置并保存
+// Update 更新配置并安排落盘。
+// 配置立即在内存生效（Get马上可见），磁盘写入按saveDebounce合并去抖，
+// 写入失败在去抖回调中记录日志。进程退出前需调用Flush确保未落盘的变更写入。
 func (m *Manager) Update(config types.AppConfig) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.config = config
-	return m.Save()
+	m.scheduleSaveLocked()
+	return nil
+}
+
+// scheduleSaveLocked 标记配置已变更并安排去抖落盘，调用方必须已持有写锁
+func (m *Manager) scheduleSaveLocked() {
+	m.dirty = true
+	if m.saveTimer == nil {
+		m.saveTimer = time.AfterFunc(saveDebounce, m.flushDebounced)
+	} else {
+		m.saveTimer.Reset(saveDebounce)
+	}
+}
+
+// flushDebounced 去抖定时器到期后的落盘回调
+func (m *Manager) flushDebounced() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.dirty {
+		return
+	}
+	if err := m.saveLocked(); err != nil {
+		m.logError("去抖落盘配置失败: %v", err)
+	}
+}
+
+// Flush 立即写入尚未落盘的配置变更，核心服务停止前调用
+func (m *Manager) Flush() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.saveTimer != nil {
+		m.saveTimer.Stop()
+	}
+	if !m.dirty {
+		return
+	}
+	if err := m.saveLocked(); err != nil {
+		m.logError("退出前落盘配置失败: %v", err)
+	}
+}
+
+// WriteCount 返回累计的配置磁盘写入次数
+func (m *Manager) WriteCount() int64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.writeCount
 }
 
 // 日志辅助方法
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// featureDefaultsFileName 更新器随发布通道写入配置目录的功能开关默认值文件。
+// beta通道的安装包会在此下发实验性功能的启用默认值，
+// 同一份二进制据此做灰度放量，无需为各通道单独出包。
+const featureDefaultsFileName = "feature-defaults.json"
+
+// FlagExperimentalControl 实验性控制策略总开关（beta通道默认开启）
+const FlagExperimentalControl = "experimental-control"
+
+// builtinFeatureFlags 内置的开关默认值，同时充当已知开关名的注册表。
+// 合并时未注册的开关名也会保留，保证新通道文件配旧二进制时不丢数据。
+var builtinFeatureFlags = map[string]bool{
+	FlagExperimentalControl: false,
+}
+
+// FeatureDefaults 更新通道下发的功能开关默认值
+type FeatureDefaults struct {
+	Channel string          `json:"channel"` // 发布通道: stable/beta/dev
+	Flags   map[string]bool `json:"flags"`   // 该通道下发的开关默认值
+}
+
+// loadFeatureDefaults 读取通道默认值文件，搜索顺序与config.json一致：
+// 默认配置目录优先，其次安装目录。文件不存在或损坏时视为stable通道无下发。
+// 调用方必须已持有写锁。
+func (m *Manager) loadFeatureDefaults() {
+	candidates := []string{
+		filepath.Join(m.GetDefaultConfigDir(), featureDefaultsFileName),
+		filepath.Join(m.installDir, "config", featureDefaultsFileName),
+	}
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var defaults FeatureDefaults
+		if err := json.Unmarshal(data, &defaults); err != nil {
+			m.logError("解析功能开关默认值失败 %s: %v", path, err)
+			continue
+		}
+		m.featureDefaults = defaults
+		m.logInfo("已加载 %s 通道的功能开关默认值: %s", defaults.Channel, path)
+		return
+	}
+	m.featureDefaults = FeatureDefaults{}
+}
+
+// FeatureChannel 返回更新器下发的发布通道名，未下发时为stable
+func (m *Manager) FeatureChannel() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.featureDefaults.Channel == "" {
+		return "stable"
+	}
+	return m.featureDefaults.Channel
+}
+
+// EffectiveFeatureFlags 返回三层合并后的功能开关：
+// 内置默认 < 通道下发默认 < 用户显式覆盖（AppConfig.FeatureFlags）
+func (m *Manager) EffectiveFeatureFlags() map[string]bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	flags := make(map[string]bool, len(builtinFeatureFlags))
+	for name, enabled := range builtinFeatureFlags {
+		flags[name] = enabled
+	}
+	for name, enabled := range m.featureDefaults.Flags {
+		flags[name] = enabled
+	}
+	for name, enabled := range m.config.FeatureFlags {
+		flags[name] = enabled
+	}
+	return flags
+}
+
+// FeatureEnabled 查询合并后指定开关是否开启，未知开关名返回false
+func (m *Manager) FeatureEnabled(name string) bool {
+	return m.EffectiveFeatureFlags()[name]
+}
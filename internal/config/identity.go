@@ -0,0 +1,76 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// EnsureInstallationID 确保配置中存在稳定的安装标识，缺失时生成并立即落盘。
+// 标识随配置存放在ProgramData下，GUI重装不会丢失，
+// 支持侧据此把同一台机器的多次报障关联起来
+func (m *Manager) EnsureInstallationID() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.config.InstallationID != "" {
+		return m.config.InstallationID
+	}
+
+	id, err := newInstallationID()
+	if err != nil {
+		m.logError("生成安装标识失败: %v", err)
+		return ""
+	}
+	m.config.InstallationID = id
+	m.logInfo("首次启动，生成安装标识: %s", id)
+	if err := m.saveLocked(); err != nil {
+		m.logError("保存安装标识失败: %v", err)
+	}
+	return id
+}
+
+// newInstallationID 用加密随机数生成UUID v4格式的标识
+func newInstallationID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// RecordDevicePairing 记录一次设备连接成功：按序列号新建或更新配对记录。
+// 序列号为空或未知时不记录（无法区分具体设备），落盘走去抖合并
+func (m *Manager) RecordDevicePairing(serial, model string) {
+	if serial == "" || serial == "Unknown" {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now().Unix()
+	for i := range m.config.PairedDevices {
+		if m.config.PairedDevices[i].Serial == serial {
+			m.config.PairedDevices[i].Model = model
+			m.config.PairedDevices[i].LastSeenUnix = now
+			m.config.PairedDevices[i].TimesConnected++
+			m.scheduleSaveLocked()
+			return
+		}
+	}
+
+	m.config.PairedDevices = append(m.config.PairedDevices, types.PairedDevice{
+		Serial:          serial,
+		Model:           model,
+		FirstPairedUnix: now,
+		LastSeenUnix:    now,
+		TimesConnected:  1,
+	})
+	m.logInfo("记录新配对设备: %s (%s)", serial, model)
+	m.scheduleSaveLocked()
+}
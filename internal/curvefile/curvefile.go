@@ -0,0 +1,176 @@
+// Package curvefile 定义可移植的 .bs2curve 温控曲线分享格式。
+// 社区分享曲线时只携带曲线本身与来源元数据，而不是整份配置的原始JSON：
+// 文件为带固定format标识的JSON文档，包含曲线点、插值方式、作者/设备/版本元数据，
+// 以及可选的内容校验签名（导出时自动计算，导入时校验，发现篡改或截断即拒绝）。
+package curvefile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+const (
+	// FormatName 文件format字段的固定取值，用于识别.bs2curve文档
+	FormatName = "bs2curve"
+	// FormatVersion 当前格式版本，结构不兼容变化时递增
+	FormatVersion = 1
+	// FileExtension 推荐的文件扩展名
+	FileExtension = ".bs2curve"
+)
+
+// 插值方式：曲线段之间如何过渡
+const (
+	// InterpolationLinear 相邻点线性插值（本应用的默认行为，见ResolveCurve）
+	InterpolationLinear = "linear"
+	// InterpolationStep 阶梯式：到达下一点温度前保持当前转速
+	InterpolationStep = "step"
+)
+
+// 曲线点数与温度的合法范围
+const (
+	minPoints = 2
+	maxPoints = 64
+	minTemp   = 0
+	maxTemp   = 120
+)
+
+// Metadata 曲线的来源元数据
+type Metadata struct {
+	Name        string `json:"name"`                  // 曲线名称
+	Author      string `json:"author,omitempty"`      // 作者署名
+	Description string `json:"description,omitempty"` // 适用场景说明
+	DeviceModel string `json:"deviceModel,omitempty"` // 导出时的设备型号
+	AppVersion  string `json:"appVersion,omitempty"`  // 导出时的应用版本
+	CreatedUnix int64  `json:"createdUnix,omitempty"` // 导出时刻(Unix秒)
+}
+
+// File .bs2curve 文档结构
+type File struct {
+	Format        string                `json:"format"`
+	Version       int                   `json:"version"`
+	Interpolation string                `json:"interpolation"`
+	Metadata      Metadata              `json:"metadata"`
+	Points        []types.FanCurvePoint `json:"points"`
+	// Signature 内容校验签名（对规范化内容的SHA-256）。用于发现传输截断
+	// 或手工篡改，不承载作者身份认证——那需要密钥体系，社区分享场景不引入。
+	Signature string `json:"signature,omitempty"`
+}
+
+// Export 把曲线打包为.bs2curve文档内容。曲线点会被排序并校验，
+// CreatedUnix为0时填充当前时间，签名自动计算。
+func Export(points []types.FanCurvePoint, interpolation string, meta Metadata) ([]byte, error) {
+	if interpolation == "" {
+		interpolation = InterpolationLinear
+	}
+	if interpolation != InterpolationLinear && interpolation != InterpolationStep {
+		return nil, fmt.Errorf("不支持的插值方式: %s", interpolation)
+	}
+
+	sorted := make([]types.FanCurvePoint, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Temperature < sorted[j].Temperature
+	})
+	if err := validatePoints(sorted); err != nil {
+		return nil, err
+	}
+
+	if meta.CreatedUnix == 0 {
+		meta.CreatedUnix = time.Now().Unix()
+	}
+
+	file := File{
+		Format:        FormatName,
+		Version:       FormatVersion,
+		Interpolation: interpolation,
+		Metadata:      meta,
+		Points:        sorted,
+	}
+	signature, err := file.computeSignature()
+	if err != nil {
+		return nil, err
+	}
+	file.Signature = signature
+
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// Parse 解析并校验.bs2curve文档。携带签名时校验内容完整性，
+// 签名不符（被篡改或截断）返回错误；无签名的文件仅做结构校验。
+func Parse(data []byte) (*File, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析曲线文件失败: %v", err)
+	}
+
+	if file.Format != FormatName {
+		return nil, fmt.Errorf("不是有效的%s文件(format=%q)", FileExtension, file.Format)
+	}
+	if file.Version < 1 || file.Version > FormatVersion {
+		return nil, fmt.Errorf("不支持的曲线文件版本: %d（本版本最高支持%d）", file.Version, FormatVersion)
+	}
+	if file.Interpolation != InterpolationLinear && file.Interpolation != InterpolationStep {
+		return nil, fmt.Errorf("不支持的插值方式: %s", file.Interpolation)
+	}
+	if err := validatePoints(file.Points); err != nil {
+		return nil, err
+	}
+
+	if file.Signature != "" {
+		expected, err := file.computeSignature()
+		if err != nil {
+			return nil, err
+		}
+		if file.Signature != expected {
+			return nil, fmt.Errorf("曲线文件签名校验失败，内容可能被篡改或截断")
+		}
+	}
+
+	return &file, nil
+}
+
+// validatePoints 校验曲线点：点数范围、温度区间、温度严格递增、转速合法
+func validatePoints(points []types.FanCurvePoint) error {
+	if len(points) < minPoints {
+		return fmt.Errorf("曲线点数不足(%d)，至少需要%d个点", len(points), minPoints)
+	}
+	if len(points) > maxPoints {
+		return fmt.Errorf("曲线点数过多(%d)，最多%d个点", len(points), maxPoints)
+	}
+	for i, p := range points {
+		if p.Temperature < minTemp || p.Temperature > maxTemp {
+			return fmt.Errorf("第%d个点温度越界: %d°C（合法范围%d~%d）", i+1, p.Temperature, minTemp, maxTemp)
+		}
+		if i > 0 && p.Temperature <= points[i-1].Temperature {
+			return fmt.Errorf("第%d个点温度未递增: %d°C", i+1, p.Temperature)
+		}
+		if p.RPM < 0 {
+			return fmt.Errorf("第%d个点转速为负: %d", i+1, p.RPM)
+		}
+		if p.Unit == types.CurveUnitPercent && p.RPM > 100 {
+			return fmt.Errorf("第%d个点百分比越界: %d", i+1, p.RPM)
+		}
+		if p.Unit != "" && p.Unit != types.CurveUnitRPM && p.Unit != types.CurveUnitPercent {
+			return fmt.Errorf("第%d个点单位无效: %s", i+1, p.Unit)
+		}
+	}
+	return nil
+}
+
+// computeSignature 对除签名外的规范化文档内容计算SHA-256
+func (f *File) computeSignature() (string, error) {
+	unsigned := *f
+	unsigned.Signature = ""
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("序列化签名内容失败: %v", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
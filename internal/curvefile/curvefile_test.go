@@ -0,0 +1,95 @@
+package curvefile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+func testPoints() []types.FanCurvePoint {
+	return []types.FanCurvePoint{
+		{Temperature: 40, RPM: 1000},
+		{Temperature: 60, RPM: 1800},
+		{Temperature: 80, RPM: 2600},
+	}
+}
+
+func TestExportParseRoundTrip(t *testing.T) {
+	meta := Metadata{Name: "静音日常", Author: "tester", DeviceModel: "BS2PRO", AppVersion: "1.2.3"}
+	data, err := Export(testPoints(), InterpolationLinear, meta)
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	file, err := Parse(data)
+	if err != nil {
+		t.Fatalf("解析导出内容失败: %v", err)
+	}
+	if file.Metadata.Name != meta.Name || file.Metadata.Author != meta.Author {
+		t.Errorf("元数据丢失: %+v", file.Metadata)
+	}
+	if len(file.Points) != 3 || file.Points[1].RPM != 1800 {
+		t.Errorf("曲线点不符: %+v", file.Points)
+	}
+	if file.Signature == "" {
+		t.Error("导出应自动计算签名")
+	}
+	if file.Metadata.CreatedUnix == 0 {
+		t.Error("导出应填充创建时间")
+	}
+}
+
+func TestParseRejectsTamperedContent(t *testing.T) {
+	data, err := Export(testPoints(), InterpolationLinear, Metadata{Name: "test"})
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	tampered := strings.Replace(string(data), `"rpm": 1800`, `"rpm": 9000`, 1)
+	if tampered == string(data) {
+		t.Fatal("未能构造篡改样本")
+	}
+	if _, err := Parse([]byte(tampered)); err == nil {
+		t.Error("篡改后的内容应当校验失败")
+	}
+}
+
+func TestParseRejectsInvalidStructure(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+	}{
+		{"非bs2curve文档", `{"format":"other","version":1,"interpolation":"linear","points":[]}`},
+		{"未来版本", `{"format":"bs2curve","version":99,"interpolation":"linear","points":[{"temperature":40,"rpm":1000},{"temperature":60,"rpm":1800}]}`},
+		{"点数不足", `{"format":"bs2curve","version":1,"interpolation":"linear","points":[{"temperature":40,"rpm":1000}]}`},
+		{"温度未递增", `{"format":"bs2curve","version":1,"interpolation":"linear","points":[{"temperature":60,"rpm":1000},{"temperature":40,"rpm":1800}]}`},
+		{"百分比越界", `{"format":"bs2curve","version":1,"interpolation":"linear","points":[{"temperature":40,"rpm":120,"unit":"percent"},{"temperature":60,"rpm":130,"unit":"percent"}]}`},
+	}
+	for _, tc := range cases {
+		if _, err := Parse([]byte(tc.content)); err == nil {
+			t.Errorf("%s: 应当解析失败", tc.name)
+		}
+	}
+}
+
+func TestExportSortsPoints(t *testing.T) {
+	points := []types.FanCurvePoint{
+		{Temperature: 80, RPM: 2600},
+		{Temperature: 40, RPM: 1000},
+	}
+	data, err := Export(points, "", Metadata{Name: "乱序"})
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+	file, err := Parse(data)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if file.Points[0].Temperature != 40 {
+		t.Errorf("导出应按温度排序，实际: %+v", file.Points)
+	}
+	if file.Interpolation != InterpolationLinear {
+		t.Errorf("空插值方式应默认为linear，实际: %s", file.Interpolation)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/TIANLI0/BS2PRO-Controller/internal/protocol"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/rgb"
 	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
 	"github.com/sstallion/go-hid"
@@ -22,6 +23,18 @@ const (
 	ProductID2 = 0x1001
 )
 
+// 风扇控制命令ID（帧构建与校验统一由 internal/protocol 处理）
+const (
+	cmdSetSpeed       = 0x21 // 转速下发
+	cmdEnterAutoMode  = 0x23 // 进入自动模式(实时转速)
+	cmdPowerOnStart   = 0x0C // 通电自启动
+	cmdSmartStartStop = 0x0D // 智能启停
+	cmdBrightnessOff  = 0x47 // 亮度关闭(矩阵数据帧)
+	cmdBrightnessFull = 0x43 // 亮度100%
+	cmdGearLight      = 0x48 // 挡位灯开关
+	cmdHostWatchdog   = 0x25 // 主机看门狗配置/保活（超时未收到主机帧回退板载挡位控制）
+)
+
 // Manager HID 设备管理器
 type Manager struct {
 	device         *hid.Device
@@ -30,12 +43,26 @@ type Manager struct {
 	mutex          sync.RWMutex
 	deviceOpMutex  sync.Mutex // 设备操作互斥锁，确保同一时间只有一个读/写操作
 	logger         types.Logger
+	clock          types.Clock
 	currentFanData *types.FanData
 
+	// 首选HID接口路径(空则自动匹配)与当前实际连接的路径
+	preferredPath string
+	connectedPath string
+
 	// RGB 控制器与ACK通道
 	rgbCtrl    *rgb.Controller
 	rgbAckChan chan []byte
 
+	// 原始报文旁路（诊断捕获用），nil时读循环零开销跳过
+	tapMutex sync.RWMutex
+	rawTap   func(report []byte)
+
+	// 固件主机看门狗保活状态
+	watchdogMutex sync.Mutex
+	watchdogOn    bool
+	watchdogStop  chan struct{}
+
 	// 回调函数
 	onFanDataUpdate func(data *types.FanData)
 	onDisconnect    func()
@@ -45,18 +72,38 @@ type Manager struct {
 func NewManager(logger types.Logger) *Manager {
 	m := &Manager{
 		logger:     logger,
+		clock:      types.SystemClock{},
 		rgbAckChan: make(chan []byte, 100),
 	}
 	// 注入自己作为 RGB 的底层传输通道 (实现 rgb.Transport 接口)
 	m.rgbCtrl = rgb.NewController(m)
+	// 进程级共享对齐器：多设备时各Manager的动效相位一致
+	m.rgbCtrl.SetSynchronizer(sharedRGBSync)
 	return m
 }
 
+// sharedRGBSync 全部设备管理器共享的灯效相位对齐器
+var sharedRGBSync = rgb.NewSynchronizer()
+
+// SetClock 替换时间源，仅供测试注入假时钟（同步注入RGB控制器）
+func (m *Manager) SetClock(clock types.Clock) {
+	m.clock = clock
+	m.rgbCtrl.SetClock(clock)
+}
+
 // RGB 获取 RGB 控制器实例
 func (m *Manager) RGB() *rgb.Controller {
 	return m.rgbCtrl
 }
 
+// SetRawReportTap 设置原始报文旁路回调，读循环收到的每个报文都会抄送一份拷贝。
+// 仅供诊断捕获使用，传nil关闭旁路。
+func (m *Manager) SetRawReportTap(tap func(report []byte)) {
+	m.tapMutex.Lock()
+	m.rawTap = tap
+	m.tapMutex.Unlock()
+}
+
 // SetCallbacks 设置回调函数
 func (m *Manager) SetCallbacks(onFanDataUpdate func(data *types.FanData), onDisconnect func()) {
 	m.onFanDataUpdate = onFanDataUpdate
@@ -82,31 +129,53 @@ func (m *Manager) Connect() (bool, map[string]string) {
 		return true, nil
 	}
 
-	productIDs := []uint16{ProductID1, ProductID2}
 	var device *hid.Device
 	var err error
-
 	var connectedProductID uint16
-	for _, productID := range productIDs {
-		m.logInfo("正在连接设备 - 厂商ID: 0x%04X, 产品ID: 0x%04X", VendorID, productID)
 
-		device, err = hid.OpenFirst(VendorID, productID)
-		if err == nil {
-			m.logInfo("成功连接到产品ID: 0x%04X", productID)
-			connectedProductID = productID
-			break
-		} else {
-			m.logError("产品ID 0x%04X 连接失败: %v", productID, err)
+	// 优先尝试用户/探测确定的首选接口路径，复合设备多接口时避免OpenFirst连错
+	if m.preferredPath != "" {
+		m.logInfo("按首选接口路径连接设备: %s", m.preferredPath)
+		device, err = hid.OpenPath(m.preferredPath)
+		if err != nil {
+			m.logError("首选接口路径连接失败，回退到自动匹配: %v", err)
+			device = nil
 		}
 	}
 
-	if err != nil {
-		m.logError("所有设备连接尝试都失败")
-		return false, nil
+	if device == nil {
+		productIDs := []uint16{ProductID1, ProductID2}
+		for _, productID := range productIDs {
+			m.logInfo("正在连接设备 - 厂商ID: 0x%04X, 产品ID: 0x%04X", VendorID, productID)
+
+			device, err = hid.OpenFirst(VendorID, productID)
+			if err == nil {
+				m.logInfo("成功连接到产品ID: 0x%04X", productID)
+				connectedProductID = productID
+				break
+			} else {
+				m.logError("产品ID 0x%04X 连接失败: %v", productID, err)
+			}
+		}
+
+		if err != nil {
+			m.logError("所有设备连接尝试都失败")
+			return false, nil
+		}
 	}
 
 	m.device = device
 	m.isConnected = true
+
+	// 获取设备信息
+	deviceInfo, err := device.GetDeviceInfo()
+	if err == nil {
+		// 按路径打开时事先不知道产品ID，统一以设备上报为准
+		if connectedProductID == 0 {
+			connectedProductID = deviceInfo.ProductID
+		}
+		m.connectedPath = deviceInfo.Path
+	}
 	m.productID = connectedProductID
 
 	modelName := "BS2PRO"
@@ -114,8 +183,6 @@ func (m *Manager) Connect() (bool, map[string]string) {
 		modelName = "BS2"
 	}
 
-	// 获取设备信息
-	deviceInfo, err := device.GetDeviceInfo()
 	var info map[string]string
 	if err == nil {
 		m.logInfo("设备连接成功: %s %s (型号: %s)", deviceInfo.MfrStr, deviceInfo.ProductStr, modelName)
@@ -151,6 +218,60 @@ func (m *Manager) Connect() (bool, map[string]string) {
 	return true, info
 }
 
+// SetPreferredPath 设置首选HID接口路径，下次Connect时优先按路径打开
+func (m *Manager) SetPreferredPath(path string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.preferredPath = path
+}
+
+// ConnectedPath 获取当前连接接口的设备路径（未连接时为空）
+func (m *Manager) ConnectedPath() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if !m.isConnected {
+		return ""
+	}
+	return m.connectedPath
+}
+
+// EnumeratedDevice 枚举到的候选HID接口信息
+type EnumeratedDevice struct {
+	Path            string `json:"path"`            // 平台相关设备路径
+	ProductID       uint16 `json:"productId"`       // 产品ID
+	SerialNumber    string `json:"serialNumber"`    // 序列号
+	Manufacturer    string `json:"manufacturer"`    // 厂商名称
+	Product         string `json:"product"`         // 产品名称
+	UsagePage       uint16 `json:"usagePage"`       // Usage Page
+	Usage           uint16 `json:"usage"`           // Usage
+	InterfaceNumber int    `json:"interfaceNumber"` // USB接口编号
+}
+
+// EnumerateDevices 枚举所有匹配厂商ID的HID接口（无需先连接）。
+// 复合设备会暴露多个接口，GUI 可据此让用户选择具体接口，
+// 避免 OpenFirst 连到没有输入报告的错误接口。
+func (m *Manager) EnumerateDevices() ([]EnumeratedDevice, error) {
+	var devices []EnumeratedDevice
+	err := hid.Enumerate(VendorID, hid.ProductIDAny, func(info *hid.DeviceInfo) error {
+		devices = append(devices, EnumeratedDevice{
+			Path:            info.Path,
+			ProductID:       info.ProductID,
+			SerialNumber:    info.SerialNbr,
+			Manufacturer:    info.MfrStr,
+			Product:         info.ProductStr,
+			UsagePage:       info.UsagePage,
+			Usage:           info.Usage,
+			InterfaceNumber: info.InterfaceNbr,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("枚举HID设备失败: %v", err)
+	}
+	m.logInfo("枚举到 %d 个候选HID接口", len(devices))
+	return devices, nil
+}
+
 // Disconnect 断开设备连接
 func (m *Manager) Disconnect() {
 	m.mutex.Lock()
@@ -226,13 +347,23 @@ func (m *Manager) monitorDeviceData() {
 				m.logError("连续读取失败，设备断开: %v", err)
 				break
 			}
-			time.Sleep(500 * time.Millisecond)
+			m.clock.Sleep(500 * time.Millisecond)
 			continue
 		}
 
 		consecutiveErrors = 0
 
 		if n > 0 {
+			// 诊断捕获开启时抄送原始报文
+			m.tapMutex.RLock()
+			tap := m.rawTap
+			m.tapMutex.RUnlock()
+			if tap != nil {
+				report := make([]byte, n)
+				copy(report, buffer[:n])
+				tap(report)
+			}
+
 			// 将数据抄送给RGB拦截器
 			m.extractRGBACK(buffer, n)
 
@@ -248,7 +379,7 @@ func (m *Manager) monitorDeviceData() {
 				}
 			}
 		}
-		time.Sleep(100 * time.Millisecond)
+		m.clock.Sleep(100 * time.Millisecond)
 	}
 
 	m.handleDeviceDisconnected()
@@ -318,13 +449,13 @@ func (m *Manager) WritePacketAndWaitACK(cmdID byte, packet []byte, timeout time.
 
 	// 异步等待ACK
 	go func() {
-		startTime := time.Now()
+		startTime := m.clock.Now()
 		timer := time.NewTimer(timeout)
 		defer timer.Stop()
 
 		select {
 		case resp := <-m.rgbAckChan:
-			elapsed := time.Since(startTime)
+			elapsed := m.clock.Now().Sub(startTime)
 			// 放宽检查条件：只要resp[4]==1就认为是成功ACK
 			if len(resp) >= 5 && resp[4] == 1 {
 				m.logDebug("ACK received for cmdID 0x%02X (got 0x%02X), delay: %v",
@@ -400,6 +531,12 @@ func (m *Manager) parseFanData(data []byte, length int) *types.FanData {
 	if length >= 12 {
 		fanData.TargetRPM = binary.LittleEndian.Uint16(data[10:12])
 	}
+	// 新固件把电机电流/电压(毫安/毫伏，小端)放在原保留区12..15字节，
+	// 旧固件报文不足16字节或字节恒为0，字段保持0表示不可用
+	if length >= 16 {
+		fanData.MotorCurrentMA = binary.LittleEndian.Uint16(data[12:14])
+		fanData.MotorVoltageMV = binary.LittleEndian.Uint16(data[14:16])
+	}
 
 	fanData.MaxGear, fanData.SetGear = m.parseGearSettings(fanData.GearSettings)
 	fanData.WorkMode = m.parseWorkMode(fanData.CurrentMode)
@@ -466,12 +603,7 @@ func (m *Manager) validateAndGetDevice(rpm int, label string) (*hid.Device, bool
 func buildSpeedCmd(rpm int) []byte {
 	speedBytes := make([]byte, 2)
 	binary.LittleEndian.PutUint16(speedBytes, uint16(rpm))
-	checksum := (0x5A + 0xA5 + 0x21 + 0x04 + int(speedBytes[0]) + int(speedBytes[1]) + 1) & 0xFF
-	cmd := []byte{0x02, 0x5A, 0xA5, 0x21, 0x04}
-	cmd = append(cmd, speedBytes...)
-	cmd = append(cmd, byte(checksum))
-	cmd = append(cmd, make([]byte, 23-len(cmd))...)
-	return cmd
+	return protocol.BuildReport(cmdSetSpeed, speedBytes)
 }
 
 // SetFanSpeed 设置风扇转速（纯数据下发，不再带模式切换）
@@ -494,13 +626,12 @@ func (m *Manager) SetCustomFanSpeed(rpm int) bool {
 		return false
 	}
 
-	enterModeCmd := []byte{0x02, 0x5A, 0xA5, 0x23, 0x02, 0x25, 0x00}
-	enterModeCmd = append(enterModeCmd, make([]byte, 23-len(enterModeCmd))...)
+	enterModeCmd := protocol.BuildReport(cmdEnterAutoMode, nil)
 	m.deviceOpMutex.Lock()
 	dev.Write(enterModeCmd)
 	m.deviceOpMutex.Unlock()
 
-	time.Sleep(50 * time.Millisecond)
+	m.clock.Sleep(50 * time.Millisecond)
 
 	cmd := buildSpeedCmd(rpm)
 	m.deviceOpMutex.Lock()
@@ -519,8 +650,7 @@ func (m *Manager) EnterAutoMode() error {
 	dev := m.device
 	m.mutex.Unlock()
 
-	enterModeCmd := []byte{0x02, 0x5A, 0xA5, 0x23, 0x02, 0x25, 0x00}
-	enterModeCmd = append(enterModeCmd, make([]byte, 23-len(enterModeCmd))...)
+	enterModeCmd := protocol.BuildReport(cmdEnterAutoMode, nil)
 	m.deviceOpMutex.Lock()
 	_, err := dev.Write(enterModeCmd)
 	m.deviceOpMutex.Unlock()
@@ -570,13 +700,11 @@ func (m *Manager) SetGearLight(enabled bool) bool {
 	dev := m.device
 	m.mutex.Unlock()
 
-	var cmd []byte
+	payload := []byte{0x00}
 	if enabled {
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x48, 0x03, 0x01, 0x4C}
-	} else {
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x48, 0x03, 0x00, 0x4B}
+		payload[0] = 0x01
 	}
-	cmd = append(cmd, make([]byte, 23-len(cmd))...)
+	cmd := protocol.BuildReport(cmdGearLight, payload)
 	m.deviceOpMutex.Lock()
 	_, err := dev.Write(cmd)
 	m.deviceOpMutex.Unlock()
@@ -592,13 +720,11 @@ func (m *Manager) SetPowerOnStart(enabled bool) bool {
 	dev := m.device
 	m.mutex.Unlock()
 
-	var cmd []byte
+	payload := []byte{0x01}
 	if enabled {
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x0C, 0x03, 0x02, 0x11}
-	} else {
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x0C, 0x03, 0x01, 0x10}
+		payload[0] = 0x02
 	}
-	cmd = append(cmd, make([]byte, 23-len(cmd))...)
+	cmd := protocol.BuildReport(cmdPowerOnStart, payload)
 	m.deviceOpMutex.Lock()
 	_, err := dev.Write(cmd)
 	m.deviceOpMutex.Unlock()
@@ -614,18 +740,18 @@ func (m *Manager) SetSmartStartStop(mode string) bool {
 	dev := m.device
 	m.mutex.Unlock()
 
-	var cmd []byte
+	var payload []byte
 	switch mode {
 	case "off":
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x0D, 0x03, 0x00, 0x10}
+		payload = []byte{0x00}
 	case "immediate":
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x0D, 0x03, 0x01, 0x11}
+		payload = []byte{0x01}
 	case "delayed":
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x0D, 0x03, 0x02, 0x12}
+		payload = []byte{0x02}
 	default:
 		return false
 	}
-	cmd = append(cmd, make([]byte, 23-len(cmd))...)
+	cmd := protocol.BuildReport(cmdSmartStartStop, payload)
 	m.deviceOpMutex.Lock()
 	_, err := dev.Write(cmd)
 	m.deviceOpMutex.Unlock()
@@ -644,17 +770,16 @@ func (m *Manager) SetBrightness(percentage int) bool {
 	var cmd []byte
 	switch percentage {
 	case 0:
-		// 协议格式: [0x02][5A A5][cmdID=0x47][len=0x0D=13][payload(11字节)][CRC]
-		// len=13 = content总长(含cmdID+len自身), payload=11字节, 有效数据只有首字节0x1C
-		// CRC = sum(content) = sum(47+0D+1C+00*10) = 0x70
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x47, 0x0D, 0x1C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x70}
+		// payload共11字节，有效数据只有首字节0x1C，其余为矩阵占位
+		payload := make([]byte, 11)
+		payload[0] = 0x1C
+		cmd = protocol.BuildReport(cmdBrightnessOff, payload)
 	case 100:
-		cmd = []byte{0x02, 0x5A, 0xA5, 0x43, 0x02, 0x45}
+		cmd = protocol.BuildReport(cmdBrightnessFull, nil)
 	default:
 		m.logError("SetBrightness: 不支持的亮度值 %d，仅支持0或100", percentage)
 		return false
 	}
-	cmd = append(cmd, make([]byte, 23-len(cmd))...)
 	m.deviceOpMutex.Lock()
 	_, err := dev.Write(cmd)
 	m.deviceOpMutex.Unlock()
@@ -0,0 +1,80 @@
+package device
+
+import (
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/protocol"
+)
+
+// 固件主机看门狗：开启后固件在 watchdogTimeoutSec 内未收到任何主机帧时
+// 自动回退到板载挡位控制，避免核心进程崩溃或假死后风扇卡在上位机
+// 最后下发的低转速上。开启期间由保活循环周期性重发配置帧兼作心跳，
+// 间隔取超时的三分之一，单帧丢失不会触发回退。
+const (
+	watchdogTimeoutSec     = 15
+	watchdogKeepaliveEvery = watchdogTimeoutSec / 3 * time.Second
+	watchdogEnablePayload  = 0x01
+	watchdogDisablePayload = 0x00
+)
+
+// SetHostWatchdog 开关固件主机看门狗。开启时启动保活循环，关闭时
+// 先停保活再向固件下发关闭帧。设备未连接时开关状态仍会记录，
+// 重连后由配置重应用流程再次下发。
+func (m *Manager) SetHostWatchdog(enabled bool) bool {
+	m.watchdogMutex.Lock()
+	if enabled && !m.watchdogOn {
+		m.watchdogOn = true
+		m.watchdogStop = make(chan struct{})
+		go m.watchdogKeepaliveLoop(m.watchdogStop)
+		m.logInfo("固件看门狗已开启（超时%d秒回退板载控制）", watchdogTimeoutSec)
+	} else if !enabled && m.watchdogOn {
+		m.watchdogOn = false
+		close(m.watchdogStop)
+		m.watchdogStop = nil
+		m.logInfo("固件看门狗已关闭")
+	}
+	m.watchdogMutex.Unlock()
+
+	return m.writeWatchdogFrame(enabled)
+}
+
+// writeWatchdogFrame 下发看门狗配置帧，payload: [开关, 超时秒]
+func (m *Manager) writeWatchdogFrame(enabled bool) bool {
+	m.mutex.Lock()
+	if !m.isConnected || m.device == nil {
+		m.mutex.Unlock()
+		return false
+	}
+	dev := m.device
+	m.mutex.Unlock()
+
+	payload := []byte{watchdogDisablePayload, watchdogTimeoutSec}
+	if enabled {
+		payload[0] = watchdogEnablePayload
+	}
+	cmd := protocol.BuildReport(cmdHostWatchdog, payload)
+	m.deviceOpMutex.Lock()
+	_, err := dev.Write(cmd)
+	m.deviceOpMutex.Unlock()
+	return err == nil
+}
+
+// watchdogKeepaliveLoop 保活循环。断开期间跳过写入，写失败仅记debug，
+// 等待断开检测与重连流程恢复。
+func (m *Manager) watchdogKeepaliveLoop(stop chan struct{}) {
+	ticker := time.NewTicker(watchdogKeepaliveEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !m.IsConnected() {
+				continue
+			}
+			if !m.writeWatchdogFrame(true) {
+				m.logDebug("看门狗保活帧写入失败，等待重连恢复")
+			}
+		}
+	}
+}
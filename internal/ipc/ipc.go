@@ -3,11 +3,16 @@ package ipc
 
 import (
 	"bufio"
+	cryptorand "crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	goruntime "runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -22,6 +27,14 @@ const (
 	PipeName = "BS2PRO-Controller-IPC"
 	// PipePath 命名管道完整路径
 	PipePath = `\\.\pipe\` + PipeName
+	// TCPAddr 本机回环 TCP 监听地址（命名管道的备用传输通道）
+	TCPAddr = "127.0.0.1:38501"
+)
+
+// 传输通道名称
+const (
+	TransportPipe = "pipe"
+	TransportTCP  = "tcp"
 )
 
 // RequestType 请求类型
@@ -33,22 +46,37 @@ const (
 	ReqDisconnect        RequestType = "Disconnect"
 	ReqGetDeviceStatus   RequestType = "GetDeviceStatus"
 	ReqGetCurrentFanData RequestType = "GetCurrentFanData"
+	ReqGetDeviceSessions RequestType = "GetDeviceSessions"
+	ReqEnumerateDevices  RequestType = "EnumerateDevices"
 
 	// 配置相关
-	ReqGetConfig    RequestType = "GetConfig"
-	ReqUpdateConfig RequestType = "UpdateConfig"
-	ReqSetFanCurve  RequestType = "SetFanCurve"
-	ReqGetFanCurve  RequestType = "GetFanCurve"
+	ReqGetConfig       RequestType = "GetConfig"
+	ReqUpdateConfig    RequestType = "UpdateConfig"
+	ReqSetFanCurve     RequestType = "SetFanCurve"
+	ReqGetFanCurve     RequestType = "GetFanCurve"
+	ReqImportCurve     RequestType = "ImportCurve"
+	ReqExportCurveFile RequestType = "ExportCurveFile"
+	ReqImportCurveFile RequestType = "ImportCurveFile"
 
 	// 控制相关
-	ReqSetAutoControl    RequestType = "SetAutoControl"
-	ReqSetManualGear     RequestType = "SetManualGear"
-	ReqGetAvailableGears RequestType = "GetAvailableGears"
-	ReqSetCustomSpeed    RequestType = "SetCustomSpeed"
-	ReqSetGearLight      RequestType = "SetGearLight"
-	ReqSetPowerOnStart   RequestType = "SetPowerOnStart"
-	ReqSetSmartStartStop RequestType = "SetSmartStartStop"
-	ReqSetBrightness     RequestType = "SetBrightness"
+	ReqSetAutoControl     RequestType = "SetAutoControl"
+	ReqSetManualGear      RequestType = "SetManualGear"
+	ReqGetAvailableGears  RequestType = "GetAvailableGears"
+	ReqSetCustomSpeed     RequestType = "SetCustomSpeed"
+	ReqSetGearLight       RequestType = "SetGearLight"
+	ReqSetPowerOnStart    RequestType = "SetPowerOnStart"
+	ReqSetSmartStartStop  RequestType = "SetSmartStartStop"
+	ReqSetBrightness      RequestType = "SetBrightness"
+	ReqMaxBoost           RequestType = "MaxBoost"
+	ReqNotificationAction RequestType = "NotificationAction"
+
+	// 曲线测绘向导
+	ReqStartThermalProfile  RequestType = "StartThermalProfile"
+	ReqCancelThermalProfile RequestType = "CancelThermalProfile"
+
+	// 曲线A/B对比测试
+	ReqStartCurveABTest  RequestType = "StartCurveABTest"
+	ReqCancelCurveABTest RequestType = "CancelCurveABTest"
 
 	// 温度相关
 	ReqGetTemperature         RequestType = "GetTemperature"
@@ -65,9 +93,20 @@ const (
 	ReqGetDebugInfo          RequestType = "GetDebugInfo"
 	ReqSetDebugMode          RequestType = "SetDebugMode"
 	ReqUpdateGuiResponseTime RequestType = "UpdateGuiResponseTime"
+	ReqGetControlDecisions   RequestType = "GetControlDecisions"
+	ReqGetConnectedClients   RequestType = "GetConnectedClients"
+	ReqStartFanDataCapture   RequestType = "StartFanDataCapture"
+	ReqStopFanDataCapture    RequestType = "StopFanDataCapture"
+	ReqStartIPCRecording     RequestType = "StartIPCRecording"
+	ReqStopIPCRecording      RequestType = "StopIPCRecording"
+	ReqListCrashReports      RequestType = "ListCrashReports"
+	ReqCreateSupportBundle   RequestType = "CreateSupportBundle"
 
 	// 系统相关
-	ReqPing RequestType = "Ping"
+	ReqPing          RequestType = "Ping"
+	ReqSetClientMode RequestType = "SetClientMode"
+	ReqGetSchema     RequestType = "GetSchema"
+	ReqGetHealth     RequestType = "GetHealth"
 
 	// RGB 灯效控制
 	ReqSetRGBMode        RequestType = "SetRGBMode"
@@ -80,8 +119,9 @@ const (
 
 // Request IPC 请求
 type Request struct {
-	Type RequestType     `json:"type"`
-	Data json.RawMessage `json:"data,omitempty"`
+	Type    RequestType     `json:"type"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	TraceID string          `json:"traceId,omitempty"` // 客户端生成的请求追踪ID，双端日志据此对齐
 }
 
 // Response IPC 响应
@@ -90,6 +130,7 @@ type Response struct {
 	Success    bool            `json:"success"`
 	Error      string          `json:"error,omitempty"`
 	Data       json.RawMessage `json:"data,omitempty"`
+	TraceID    string          `json:"traceId,omitempty"` // 原样回传请求的追踪ID
 }
 
 // Event IPC 事件（服务器推送给客户端）
@@ -109,16 +150,333 @@ const (
 	EventConfigUpdate        = "config-update"
 	EventServiceConnected    = "service-connected"
 	EventServiceDisconnected = "service-disconnected"
+	EventCrashLoopSafeBoot   = "crash-loop-safe-boot"
+	EventThrottlingDetected  = "throttling-detected"
+	EventFanCaptureDone      = "fan-capture-done"
+	EventMaxBoostChanged     = "max-boost-changed"
+	EventNotification        = "notification"
+	EventControlStateChanged = "control-state-changed"
+	// 曲线测绘向导的阶段进度与最终结果
+	EventThermalProfileProgress = "thermal-profile-progress"
+	EventThermalProfileDone     = "thermal-profile-done"
+	// 曲线A/B测试的窗口进度与最终结果
+	EventCurveABTestProgress = "curve-ab-test-progress"
+	EventCurveABTestDone     = "curve-ab-test-done"
+	// IPC流量录制结束（到时长/手动停止/写入失败）
+	EventIPCRecordingDone = "ipc-recording-done"
+	// 曲线实时工作点（节流后的专用事件，GUI在曲线图上画移动圆点）
+	EventCurveLivePoint = "curve-live-point"
+	// 通用长操作进度（支持包打包等多秒操作，GUI据此画确定型进度条）
+	EventOperationProgress = "operation-progress"
+)
+
+// ControlState 控制回路运行状态。区分"配置里开了智能变频"与
+// "回路实际在调速"：AutoControl=true但设备未连接时回路并未运行。
+const (
+	// ControlStateInactive 未运行（开关关闭、设备未连接或监控未启动）
+	ControlStateInactive = "inactive"
+	// ControlStateRunning 正常按曲线调速
+	ControlStateRunning = "running"
+	// ControlStateDegraded 运行中但温度来源不可靠（桥接故障等）
+	ControlStateDegraded = "degraded"
+	// ControlStateFailsafe 安全模式（崩溃循环安全启动），不执行调速
+	ControlStateFailsafe = "failsafe"
+)
+
+// ControlStateInfo 控制回路状态详情，control-state-changed 事件载荷
+type ControlStateInfo struct {
+	State     string `json:"state"`
+	Reason    string `json:"reason,omitempty"` // 处于该状态的原因说明
+	SinceUnix int64  `json:"sinceUnix"`        // 进入该状态的时刻(Unix秒)
+}
+
+// HealthStatus 组件健康状态取值，按严重程度排序
+const (
+	// HealthOK 正常工作
+	HealthOK = "ok"
+	// HealthUnknown 状态未知（如GUI未连接时无从判断，不计入整体状态）
+	HealthUnknown = "unknown"
+	// HealthDegraded 降级运行（仍有功能但不完整，如温度桥接故障）
+	HealthDegraded = "degraded"
+	// HealthDown 不可用
+	HealthDown = "down"
+)
+
+// ComponentHealth 单个子系统的健康信息，GetHealth 响应条目
+type ComponentHealth struct {
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	Detail          string `json:"detail,omitempty"`          // 状态说明或最近一次错误
+	LastSuccessUnix int64  `json:"lastSuccessUnix,omitempty"` // 最近一次正常工作的时刻(Unix秒)，0表示未知
+}
+
+// HealthReport 核心整体健康报告。整体状态取各组件中最差者（unknown不参与），
+// GUI状态栏、看门狗与 /healthz 端点共用同一份数据。
+type HealthReport struct {
+	Status     string            `json:"status"`
+	Components []ComponentHealth `json:"components"`
+	TimeUnix   int64             `json:"timeUnix"`
+}
+
+// ClientMode 客户端连接模式
+const (
+	// ClientModeFull 默认模式，可执行全部请求
+	ClientModeFull = "full"
+	// ClientModeObserver 只读观察者模式，修改类请求将被拒绝
+	ClientModeObserver = "observer"
+)
+
+// ClientType 客户端类型（握手时声明）
+const (
+	// ClientTypeGUI 交互式GUI客户端。未声明类型的旧客户端也按GUI处理，
+	// 保证升级期间"是否需要拉起窗口"的判断不回退。
+	ClientTypeGUI = "gui"
+	// ClientTypeCLI 脚本/命令行客户端，不参与GUI窗口相关判断
+	ClientTypeCLI = "cli"
+)
+
+// PermissionTier 权限层级。本地管道连接默认admin（保持GUI全功能）；
+// 未来的远程传输在鉴权握手中按凭据分配层级，破坏性请求只对admin开放。
+const (
+	// TierRead 只读：查询类请求
+	TierRead = "read"
+	// TierControl 控制：常规设备/配置修改
+	TierControl = "control"
+	// TierAdmin 管理：破坏性请求（停止服务、退出GUI、自定义转速等）
+	TierAdmin = "admin"
+)
+
+// tierRank 权限层级的大小关系，高层级包含低层级的全部权限
+var tierRank = map[string]int{
+	TierRead:    1,
+	TierControl: 2,
+	TierAdmin:   3,
+}
+
+// adminRequests 仅admin层级可执行的破坏性请求
+var adminRequests = map[RequestType]bool{
+	ReqQuitApp:        true,
+	ReqRestartService: true,
+	ReqStopService:    true,
+	ReqSetCustomSpeed: true, // 无上下限的自定义转速，误用可能损害硬件
+	ReqSetDebugMode:   true,
+}
+
+// requiredTier 返回执行指定请求所需的最低权限层级
+func requiredTier(reqType RequestType) string {
+	if adminRequests[reqType] {
+		return TierAdmin
+	}
+	if mutatingRequests[reqType] {
+		return TierControl
+	}
+	return TierRead
+}
+
+// mutatingRequests 会改变设备/配置/进程状态的请求类型，observer 客户端无权执行
+var mutatingRequests = map[RequestType]bool{
+	ReqConnect:             true,
+	ReqDisconnect:          true,
+	ReqUpdateConfig:        true,
+	ReqSetFanCurve:         true,
+	ReqSetAutoControl:      true,
+	ReqSetManualGear:       true,
+	ReqSetCustomSpeed:      true,
+	ReqSetGearLight:        true,
+	ReqSetPowerOnStart:     true,
+	ReqSetSmartStartStop:   true,
+	ReqSetBrightness:       true,
+	ReqMaxBoost:            true,
+	ReqNotificationAction:  true,
+	ReqShowWindow:          true,
+	ReqHideWindow:          true,
+	ReqQuitApp:             true,
+	ReqSetDebugMode:        true,
+	ReqSetRGBMode:          true,
+	ReqRestartService:      true,
+	ReqStopService:         true,
+	ReqStartFanDataCapture: true,
+	ReqStopFanDataCapture:  true,
+
+	ReqStartThermalProfile:  true,
+	ReqCancelThermalProfile: true,
+
+	ReqStartCurveABTest:  true,
+	ReqCancelCurveABTest: true,
+
+	ReqStartIPCRecording: true,
+	ReqStopIPCRecording:  true,
+
+	ReqCreateSupportBundle: true,
+}
+
+// 广播写入健康评分：连续失败超限的客户端被标记为不健康并跳过广播，
+// 避免一个死掉的客户端永远产生失败的写goroutine
+const (
+	// broadcastMaxConsecutiveFails 连续写失败达到该次数后标记为不健康
+	broadcastMaxConsecutiveFails = 5
+	// broadcastSlowWriteMs 单次广播写入超过该耗时(毫秒)计为慢写
+	broadcastSlowWriteMs = 500
 )
 
+// maxConcurrentPerClient 单连接同时处理的业务请求上限。
+// 超出时读循环阻塞等待空位，形成自然的背压。
+const maxConcurrentPerClient = 4
+
+// maxMessageBytes 单条IPC消息的字节上限。最大的正常载荷是带完整曲线的
+// AppConfig，远小于该值；不设上限的话一个恶意/失控的本地客户端
+// 发一行不带换行的数据就能把核心内存撑爆
+const maxMessageBytes = 1 << 20
+
+// errMessageTooLarge 客户端单条消息超过maxMessageBytes
+var errMessageTooLarge = errors.New("IPC消息超过大小上限")
+
+// clientState 单个客户端连接的服务端状态
+type clientState struct {
+	mode       string // full / observer
+	clientType string // gui / cli
+	tier       string // read / control / admin
+	remote     bool   // 经远程监听端口接入
+	authed     bool   // 已通过鉴权（本地连接天然为true，远程连接须握手校验令牌）
+
+	// 连接元数据（由s.mutex保护）
+	transport    string    // pipe / tcp
+	connectedAt  time.Time // 连接建立时刻
+	subscribed   bool      // 是否接收广播事件（UnsubscribeEvents后为false）
+	reqCount     int64     // 累计请求数
+	lastActivity time.Time // 最近一次收到请求的时刻
+	lastRequest  RequestType
+
+	// 广播写入健康评分（由s.mutex保护）
+	writeFails  int   // 连续失败次数，成功写入/收到请求时清零
+	totalFails  int   // 累计失败次数
+	slowWrites  int   // 累计慢写次数
+	lastWriteMs int64 // 最近一次广播写耗时(毫秒)
+	unhealthy   bool  // 连续失败超限，不再向其广播
+}
+
 // Server IPC 服务器
 type Server struct {
-	listener net.Listener
-	clients  map[net.Conn]bool
-	mutex    sync.RWMutex
-	handler  RequestHandler
-	logger   types.Logger
-	running  atomic.Bool
+	listener       net.Listener // 命名管道监听器
+	tcpListener    net.Listener // 回环 TCP 监听器（备用传输）
+	remoteListener net.Listener // 远程控制 TCP 监听器（可选，须配合令牌）
+	remoteAddr     string
+	remoteToken    string
+	clients        map[net.Conn]*clientState
+	mutex          sync.RWMutex
+	handler        RequestHandler
+	logger         types.Logger
+	running        atomic.Bool
+	sessionID      string // 核心本次启动的会话ID，握手时下发给客户端
+
+	// IPC流量旁路（诊断录制用），回调收到脱敏后的流量记录
+	trafficTapMu sync.Mutex
+	trafficTap   func(TrafficRecord)
+
+	// oversizedMessages 因超过maxMessageBytes被断开的消息计数（调试统计用）
+	oversizedMessages atomic.Int64
+}
+
+// TrafficRecord IPC流量录制的单条记录。入站请求和出站广播事件
+// 统一落在一条时间线上，回放时据此还原请求的相对节奏
+type TrafficRecord struct {
+	TimeUnixMs int64           `json:"t"`                 // 记录时刻(Unix毫秒)
+	Dir        string          `json:"dir"`               // request入站请求 / event出站广播
+	Type       string          `json:"type"`              // 请求或事件类型
+	TraceID    string          `json:"traceId,omitempty"` // 请求的追踪ID
+	Data       json.RawMessage `json:"data,omitempty"`    // 脱敏后的载荷
+}
+
+// SetTrafficTap 设置IPC流量旁路回调，每个入站请求和出站广播事件
+// 都会抄送一份脱敏后的记录。仅供诊断录制使用，传nil关闭旁路。
+func (s *Server) SetTrafficTap(tap func(TrafficRecord)) {
+	s.trafficTapMu.Lock()
+	s.trafficTap = tap
+	s.trafficTapMu.Unlock()
+}
+
+// tapTraffic 旁路开启时抄送一条脱敏后的流量记录
+func (s *Server) tapTraffic(dir string, typ string, traceID string, data json.RawMessage) {
+	s.trafficTapMu.Lock()
+	tap := s.trafficTap
+	s.trafficTapMu.Unlock()
+	if tap == nil {
+		return
+	}
+	tap(TrafficRecord{
+		TimeUnixMs: time.Now().UnixMilli(),
+		Dir:        dir,
+		Type:       typ,
+		TraceID:    traceID,
+		Data:       sanitizeTrafficData(data),
+	})
+}
+
+// redactedTrafficKeys 录制时须脱敏的载荷字段：
+// 访问令牌出现在SetClientMode握手参数和配置对象(remoteAccessToken)中
+var redactedTrafficKeys = map[string]bool{
+	"token":             true,
+	"remoteAccessToken": true,
+}
+
+// sanitizeTrafficData 把载荷中敏感字段的值替换为占位符。
+// 载荷不是合法JSON对象时原样返回（录制忠实于线上流量）。
+func sanitizeTrafficData(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	if !redactSensitive(v) {
+		return data
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+// redactSensitive 递归脱敏map/slice中的敏感字段，返回是否有字段被替换
+func redactSensitive(v any) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if redactedTrafficKeys[k] {
+				if s, ok := item.(string); ok && s != "" {
+					val[k] = "[REDACTED]"
+					changed = true
+				}
+				continue
+			}
+			if redactSensitive(item) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if redactSensitive(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// SetSessionID 设置核心会话ID（启动时生成，握手响应中下发）
+func (s *Server) SetSessionID(id string) {
+	s.sessionID = id
+}
+
+// EnableRemoteAccess 开启远程控制监听（须在Start前调用）。
+// 远程连接接入后处于未鉴权状态，仅能执行Ping和携带令牌的SetClientMode握手，
+// 且层级封顶为control——远程客户端不得执行停服等admin级破坏性请求。
+func (s *Server) EnableRemoteAccess(addr, token string) {
+	s.remoteAddr = addr
+	s.remoteToken = token
 }
 
 // RequestHandler 请求处理函数类型
@@ -127,7 +485,7 @@ type RequestHandler func(req Request) Response
 // NewServer 创建 IPC 服务器
 func NewServer(handler RequestHandler, logger types.Logger) *Server {
 	return &Server{
-		clients: make(map[net.Conn]bool),
+		clients: make(map[net.Conn]*clientState),
 		handler: handler,
 		logger:  logger,
 	}
@@ -149,21 +507,46 @@ func (s *Server) Start() error {
 	s.running.Store(true)
 	s.logInfo("IPC 服务器已启动: %s", PipePath)
 
+	// 额外监听回环 TCP 作为备用传输。管道 ACL 异常时客户端可以failover到这里，
+	// TCP 监听失败不影响主通道，仅记录日志。
+	tcpListener, err := net.Listen("tcp", TCPAddr)
+	if err != nil {
+		s.logError("创建 TCP 备用监听失败（仅管道可用）: %v", err)
+	} else {
+		s.tcpListener = tcpListener
+		s.logInfo("IPC TCP 备用通道已启动: %s", TCPAddr)
+		go s.acceptConnections(tcpListener)
+	}
+
+	// 可选的远程控制监听：地址由用户显式配置，必须配合非空令牌，
+	// 否则拒绝开启——绝不在无鉴权的情况下把控制面暴露到网络上
+	if s.remoteAddr != "" {
+		if s.remoteToken == "" {
+			s.logError("远程控制监听 %s 未配置访问令牌，拒绝开启", s.remoteAddr)
+		} else if remoteListener, err := net.Listen("tcp", s.remoteAddr); err != nil {
+			s.logError("创建远程控制监听失败: %v", err)
+		} else {
+			s.remoteListener = remoteListener
+			s.logInfo("IPC 远程控制通道已启动: %s (令牌鉴权)", s.remoteAddr)
+			go s.acceptConnections(remoteListener)
+		}
+	}
+
 	// 接受连接
-	go s.acceptConnections()
+	go s.acceptConnections(listener)
 
 	return nil
 }
 
 // acceptConnections 接受客户端连接
-func (s *Server) acceptConnections() {
+func (s *Server) acceptConnections(listener net.Listener) {
 	defer func() {
 		if r := recover(); r != nil {
 			s.logError("acceptConnections 发生 panic: %v", r)
 		}
 	}()
 	for s.running.Load() {
-		conn, err := s.listener.Accept()
+		conn, err := listener.Accept()
 		if err != nil {
 			if s.running.Load() {
 				s.logError("接受连接失败: %v", err)
@@ -172,11 +555,34 @@ func (s *Server) acceptConnections() {
 			return
 		}
 
+		transport := TransportPipe
+		if listener == s.tcpListener || listener == s.remoteListener {
+			transport = TransportTCP
+		}
+		remote := listener == s.remoteListener
+		state := &clientState{
+			mode:        ClientModeFull,
+			clientType:  ClientTypeGUI,
+			tier:        TierAdmin,
+			remote:      remote,
+			authed:      !remote,
+			transport:   transport,
+			connectedAt: time.Now(),
+			subscribed:  true,
+		}
+		if remote {
+			// 远程连接层级封顶control，且在令牌校验通过前不得执行业务请求
+			state.tier = TierControl
+		}
 		s.mutex.Lock()
-		s.clients[conn] = true
+		s.clients[conn] = state
 		s.mutex.Unlock()
 
-		s.logInfo("新的 IPC 客户端已连接")
+		if remote {
+			s.logInfo("新的远程 IPC 客户端已连接: %s（待鉴权）", conn.RemoteAddr())
+		} else {
+			s.logInfo("新的 IPC 客户端已连接")
+		}
 		go s.handleClient(conn)
 	}
 }
@@ -198,12 +604,27 @@ func (s *Server) handleClient(conn net.Conn) {
 
 	reader := bufio.NewReader(conn)
 
+	// 每连接的有界工作池与响应写锁：业务请求并发处理（慢请求不阻塞Ping），
+	// 响应写回经writeMu串行化避免交错
+	workerSem := make(chan struct{}, maxConcurrentPerClient)
+	writeMu := &sync.Mutex{}
+
 	for s.running.Load() {
 		// 设置读取deadline若客户端 30 秒内无任何数据（包括心跳），
 		// 视为僵尸连接，主动断开以释放 goroutine 和连接槽位。
 		conn.SetReadDeadline(time.Now().Add(30 * time.Second))
-		line, err := reader.ReadBytes('\n')
+		line, err := readLineLimited(reader, maxMessageBytes)
 		if err != nil {
+			if errors.Is(err, errMessageTooLarge) {
+				// 超限即断开：结构化报错后关闭，不给对端继续灌数据的机会
+				s.oversizedMessages.Add(1)
+				s.logError("IPC客户端消息超过上限 %d 字节，断开连接", maxMessageBytes)
+				s.writeResponse(conn, writeMu, Request{}, Response{
+					Success: false,
+					Error:   fmt.Sprintf("消息超过大小上限 %d 字节，连接已关闭", maxMessageBytes),
+				})
+				return
+			}
 			if s.running.Load() {
 				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 					s.logDebug("IPC读取超时 - 类型: 客户端无响应超时, 超时阈值: 30s, 错误详情: %v", netErr)
@@ -217,6 +638,8 @@ func (s *Server) handleClient(conn net.Conn) {
 		}
 		// 读到数据后清除deadline，避免影响后续正常处理耗时
 		conn.SetReadDeadline(time.Time{})
+		// 能发来数据即视为存活，恢复其广播资格
+		s.markClientResponsive(conn)
 
 		// 解析请求
 		var req Request
@@ -224,22 +647,173 @@ func (s *Server) handleClient(conn net.Conn) {
 			s.logError("解析请求失败: %v", err)
 			continue
 		}
-		resp := s.handler(req)
-		resp.IsResponse = true
+		if req.TraceID != "" {
+			s.logDebug("处理请求 %s (trace %s)", req.Type, req.TraceID)
+		}
+		s.recordClientActivity(conn, req.Type)
+		s.tapTraffic("request", string(req.Type), req.TraceID, req.Data)
+
+		switch {
+		case req.Type == ReqSetClientMode:
+			// 连接模式声明影响后续请求的鉴权，保持在连接goroutine内同步处理
+			s.writeResponse(conn, writeMu, req, s.handleSetClientMode(conn, req))
+		case !s.clientAuthed(conn) && req.Type != ReqPing:
+			// 远程连接在令牌校验通过前只允许Ping和握手
+			s.writeResponse(conn, writeMu, req, Response{
+				Success: false,
+				Error:   fmt.Sprintf("远程连接未完成令牌鉴权，无法执行请求: %s", req.Type),
+			})
+		case s.clientMode(conn) == ClientModeObserver && mutatingRequests[req.Type]:
+			s.writeResponse(conn, writeMu, req, Response{
+				Success: false,
+				Error:   fmt.Sprintf("observer 模式客户端无权执行修改类请求: %s", req.Type),
+			})
+		case tierRank[s.clientTier(conn)] < tierRank[requiredTier(req.Type)]:
+			s.writeResponse(conn, writeMu, req, Response{
+				Success: false,
+				Error: fmt.Sprintf("权限不足: 请求 %s 需要 %s 层级，当前为 %s",
+					req.Type, requiredTier(req.Type), s.clientTier(conn)),
+			})
+		default:
+			// 业务请求派发到有界工作池：慢请求（如桥接测试）不再头部阻塞
+			// 同连接的后续Ping等快请求。请求ID（TraceID）随响应回传供客户端配对。
+			workerSem <- struct{}{}
+			go func(r Request) {
+				defer func() {
+					<-workerSem
+					if rec := recover(); rec != nil {
+						s.logError("请求处理发生 panic: %v", rec)
+					}
+				}()
+				s.writeResponse(conn, writeMu, r, s.handler(r))
+			}(req)
+		}
+	}
+}
 
-		// 发送响应
-		respBytes, err := json.Marshal(resp)
-		if err != nil {
-			s.logError("序列化响应失败: %v", err)
-			continue
+// readLineLimited 按行读取一条消息，累计超过limit字节即返回errMessageTooLarge。
+// bufio.Reader.ReadBytes没有上限，会跟着对端发来的数据无限增长，
+// 这里改用ReadSlice按缓冲区分段累计，越界时立刻止损
+func readLineLimited(reader *bufio.Reader, limit int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > limit {
+			return nil, errMessageTooLarge
+		}
+		if err == nil {
+			return line, nil
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
 		}
+	}
+}
 
-		_, err = conn.Write(append(respBytes, '\n'))
-		if err != nil {
-			s.logError("发送响应失败: %v", err)
-			return
+// writeResponse 序列化并写回响应，同一连接上的并发写由writeMu串行化
+func (s *Server) writeResponse(conn net.Conn, writeMu *sync.Mutex, req Request, resp Response) {
+	resp.IsResponse = true
+	resp.TraceID = req.TraceID
+
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		s.logError("序列化响应失败: %v", err)
+		return
+	}
+
+	writeMu.Lock()
+	_, err = conn.Write(append(respBytes, '\n'))
+	writeMu.Unlock()
+	if err != nil {
+		s.logError("发送响应失败: %v", err)
+	}
+}
+
+// clientMode 获取指定连接的客户端模式
+func (s *Server) clientMode(conn net.Conn) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if state, ok := s.clients[conn]; ok {
+		return state.mode
+	}
+	return ClientModeFull
+}
+
+// clientTier 获取指定连接的权限层级
+func (s *Server) clientTier(conn net.Conn) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if state, ok := s.clients[conn]; ok && state.tier != "" {
+		return state.tier
+	}
+	return TierAdmin
+}
+
+// clientAuthed 检查指定连接是否已通过鉴权（本地连接恒为true）
+func (s *Server) clientAuthed(conn net.Conn) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if state, ok := s.clients[conn]; ok {
+		return state.authed
+	}
+	return false
+}
+
+// handleSetClientMode 处理客户端模式声明请求
+func (s *Server) handleSetClientMode(conn net.Conn, req Request) Response {
+	var params SetClientModeParams
+	if len(req.Data) > 0 {
+		if err := json.Unmarshal(req.Data, &params); err != nil {
+			return Response{Success: false, Error: "解析客户端模式参数失败: " + err.Error()}
 		}
 	}
+
+	mode := params.Mode
+	if mode == "" {
+		mode = ClientModeFull
+	}
+	if mode != ClientModeFull && mode != ClientModeObserver {
+		return Response{Success: false, Error: fmt.Sprintf("未知的客户端模式: %s", mode)}
+	}
+
+	clientType := params.ClientType
+	if clientType != "" && clientType != ClientTypeGUI && clientType != ClientTypeCLI {
+		return Response{Success: false, Error: fmt.Sprintf("未知的客户端类型: %s", clientType)}
+	}
+
+	tier := params.Tier
+	if tier != "" && tierRank[tier] == 0 {
+		return Response{Success: false, Error: fmt.Sprintf("未知的权限层级: %s", tier)}
+	}
+
+	s.mutex.Lock()
+	if state, ok := s.clients[conn]; ok {
+		// 远程连接在握手中校验访问令牌，失败则维持未鉴权状态
+		if state.remote && !state.authed {
+			if s.remoteToken == "" ||
+				subtle.ConstantTimeCompare([]byte(params.Token), []byte(s.remoteToken)) != 1 {
+				s.mutex.Unlock()
+				s.logError("远程客户端令牌校验失败: %s", conn.RemoteAddr())
+				return Response{Success: false, Error: "远程访问令牌校验失败"}
+			}
+			state.authed = true
+		}
+		state.mode = mode
+		if clientType != "" {
+			state.clientType = clientType
+		}
+		// 握手只能维持或降低层级：本地连接默认admin，
+		// 提升层级须由未来的远程鉴权流程完成，防止客户端自行宣称高权限
+		if tier != "" && tierRank[tier] <= tierRank[state.tier] {
+			state.tier = tier
+		}
+	}
+	s.mutex.Unlock()
+
+	s.logInfo("客户端模式已设置为: %s (类型: %s, 层级: %s)", mode, clientType, s.clientTier(conn))
+	data, _ := json.Marshal(SetClientModeResult{OK: true, SessionID: s.sessionID})
+	return Response{Success: true, Data: data}
 }
 
 // BroadcastEvent 广播事件给所有客户端
@@ -249,6 +823,7 @@ func (s *Server) BroadcastEvent(eventType string, data any) {
 		s.logError("序列化事件数据失败: %v", err)
 		return
 	}
+	s.tapTraffic("event", eventType, "", dataBytes)
 
 	event := Event{
 		IsEvent: true, // 标记为事件
@@ -263,34 +838,171 @@ func (s *Server) BroadcastEvent(eventType string, data any) {
 	}
 
 	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	conns := make([]net.Conn, 0, len(s.clients))
+	for conn, state := range s.clients {
+		// 不健康的客户端不再消耗写goroutine，等它主动发请求或断开
+		if state.unhealthy {
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	s.mutex.RUnlock()
 
-	for conn := range s.clients {
+	for _, conn := range conns {
 		go func(c net.Conn) {
 			defer func() { recover() }()
 			// 设置写超时：若客户端 Pipe 缓冲区满（GUI 卡死），2 秒后放弃写入，避免 goroutine 永久泄漏。
-			c.SetWriteDeadline(time.Now().Add(2 * time.Second))
+			start := time.Now()
+			c.SetWriteDeadline(start.Add(2 * time.Second))
 			_, err := c.Write(append(eventBytes, '\n'))
 			c.SetWriteDeadline(time.Time{}) // 写完后清除，不影响后续读 deadline
 			if err != nil {
 				s.logDebug("发送事件失败: %v", err)
 			}
+			s.recordBroadcastWrite(c, time.Since(start), err)
 		}(conn)
 	}
 }
 
+// recordBroadcastWrite 记录单个客户端的广播写入结果，
+// 连续失败超限时标记为不健康并记一条服务端日志
+func (s *Server) recordBroadcastWrite(conn net.Conn, elapsed time.Duration, writeErr error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	state, ok := s.clients[conn]
+	if !ok {
+		return
+	}
+
+	state.lastWriteMs = elapsed.Milliseconds()
+	if elapsed.Milliseconds() >= broadcastSlowWriteMs {
+		state.slowWrites++
+	}
+	if writeErr == nil {
+		state.writeFails = 0
+		return
+	}
+
+	state.writeFails++
+	state.totalFails++
+	if !state.unhealthy && state.writeFails >= broadcastMaxConsecutiveFails {
+		state.unhealthy = true
+		s.logError("客户端(%s)连续%d次广播写入失败，标记为不健康并停止向其广播", state.clientType, state.writeFails)
+	}
+}
+
+// markClientResponsive 客户端主动发来请求说明其仍然存活，
+// 清零连续失败计数并解除不健康标记
+func (s *Server) markClientResponsive(conn net.Conn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if state, ok := s.clients[conn]; ok {
+		state.writeFails = 0
+		if state.unhealthy {
+			state.unhealthy = false
+			s.logInfo("客户端(%s)恢复活跃，重新纳入广播", state.clientType)
+		}
+	}
+}
+
+// recordClientActivity 记录客户端的请求活动，供连接列表展示
+func (s *Server) recordClientActivity(conn net.Conn, reqType RequestType) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if state, ok := s.clients[conn]; ok {
+		state.reqCount++
+		state.lastActivity = time.Now()
+		state.lastRequest = reqType
+		if reqType == ReqUnsubscribeEvents {
+			state.subscribed = false
+		}
+	}
+}
+
+// ClientInfo 连接中客户端的元数据，GetConnectedClients 的响应条目
+type ClientInfo struct {
+	Transport        string      `json:"transport"`  // pipe / tcp
+	ClientType       string      `json:"clientType"` // gui / cli
+	Mode             string      `json:"mode"`       // full / observer
+	Tier             string      `json:"tier"`       // read / control / admin
+	ConnectedAtUnix  int64       `json:"connectedAtUnix"`
+	RequestCount     int64       `json:"requestCount"`
+	LastActivityUnix int64       `json:"lastActivityUnix,omitempty"` // 0表示连接后从未发过请求
+	LastRequest      RequestType `json:"lastRequest,omitempty"`
+	Subscribed       bool        `json:"subscribed"`       // 是否接收广播事件
+	Unhealthy        bool        `json:"unhealthy"`        // 广播写入连续失败被跳过
+	Remote           bool        `json:"remote,omitempty"` // 经远程控制端口接入
+}
+
+// ConnectedClients 返回当前连接的客户端列表，按连接时间排序（调试页展示用）
+func (s *Server) ConnectedClients() []ClientInfo {
+	s.mutex.RLock()
+	infos := make([]ClientInfo, 0, len(s.clients))
+	for _, state := range s.clients {
+		info := ClientInfo{
+			Transport:       state.transport,
+			ClientType:      state.clientType,
+			Mode:            state.mode,
+			Tier:            state.tier,
+			ConnectedAtUnix: state.connectedAt.Unix(),
+			RequestCount:    state.reqCount,
+			LastRequest:     state.lastRequest,
+			Subscribed:      state.subscribed,
+			Unhealthy:       state.unhealthy,
+			Remote:          state.remote,
+		}
+		if !state.lastActivity.IsZero() {
+			info.LastActivityUnix = state.lastActivity.Unix()
+		}
+		infos = append(infos, info)
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ConnectedAtUnix < infos[j].ConnectedAtUnix
+	})
+	return infos
+}
+
+// BroadcastStats 广播写入健康统计（调试信息用）
+func (s *Server) BroadcastStats() map[string]any {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	totalFails, slowWrites, unhealthy := 0, 0, 0
+	for _, state := range s.clients {
+		totalFails += state.totalFails
+		slowWrites += state.slowWrites
+		if state.unhealthy {
+			unhealthy++
+		}
+	}
+	return map[string]any{
+		"clients":           len(s.clients),
+		"unhealthyClients":  unhealthy,
+		"totalWriteFails":   totalFails,
+		"slowWrites":        slowWrites,
+		"oversizedMessages": s.oversizedMessages.Load(),
+	}
+}
+
 // Stop 停止服务器
 func (s *Server) Stop() {
 	s.running.Store(false)
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	if s.tcpListener != nil {
+		s.tcpListener.Close()
+	}
+	if s.remoteListener != nil {
+		s.remoteListener.Close()
+	}
 
 	s.mutex.Lock()
 	for conn := range s.clients {
 		conn.Close()
 	}
-	s.clients = make(map[net.Conn]bool)
+	s.clients = make(map[net.Conn]*clientState)
 	s.mutex.Unlock()
 
 	s.logInfo("IPC 服务器已停止")
@@ -303,6 +1015,19 @@ func (s *Server) HasClients() bool {
 	return len(s.clients) > 0
 }
 
+// HasGUIClients 检查是否有交互式GUI客户端连接。
+// CLI等非交互客户端不应影响"是否需要拉起GUI窗口"的判断。
+func (s *Server) HasGUIClients() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, state := range s.clients {
+		if state.clientType == ClientTypeGUI {
+			return true
+		}
+	}
+	return false
+}
+
 // 日志辅助方法
 func (s *Server) logInfo(format string, v ...any) {
 	if s.logger != nil {
@@ -322,28 +1047,90 @@ func (s *Server) logDebug(format string, v ...any) {
 	}
 }
 
+// Endpoint 客户端可用的传输端点
+type Endpoint struct {
+	Transport string // 传输类型: pipe / tcp
+	Dial      func() (net.Conn, error)
+}
+
+// DefaultEndpoints 默认端点列表：优先命名管道，管道不可用时（如 ACL 异常）回退本机 TCP
+func DefaultEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Transport: TransportPipe,
+			Dial: func() (net.Conn, error) {
+				timeout := 5 * time.Second
+				return winio.DialPipe(PipePath, &timeout)
+			},
+		},
+		{
+			Transport: TransportTCP,
+			Dial: func() (net.Conn, error) {
+				return net.DialTimeout("tcp", TCPAddr, 5*time.Second)
+			},
+		},
+	}
+}
+
+// RemoteEndpoints 远程核心端点列表：只拨指定的TCP地址，不做管道/回环回退。
+// 供GUI以"远程控制面板"模式连接另一台机器上的核心服务。
+func RemoteEndpoints(addr string) []Endpoint {
+	return []Endpoint{
+		{
+			Transport: TransportTCP,
+			Dial: func() (net.Conn, error) {
+				return net.DialTimeout("tcp", addr, 5*time.Second)
+			},
+		},
+	}
+}
+
 // Client IPC 客户端
 type Client struct {
-	conn           net.Conn
-	mutex          sync.Mutex
-	reader         *bufio.Reader
-	logger         types.Logger
-	eventHandler   func(Event)
-	responseChan   chan *Response
-	connected      bool
-	connMutex      sync.RWMutex
-	connGeneration int64
+	conn            net.Conn
+	mutex           sync.Mutex
+	reader          *bufio.Reader
+	logger          types.Logger
+	eventHandler    func(Event)
+	responseChan    chan *Response
+	connected       bool
+	connMutex       sync.RWMutex
+	connGeneration  int64
+	endpoints       []Endpoint
+	activeTransport string // 当前使用的传输类型，未连接时为空
+
+	// 请求追踪ID：随机前缀区分客户端实例，序号区分同实例的各请求
+	tracePrefix string
+	traceSeq    atomic.Int64
+}
+
+// nextTraceID 生成下一个请求追踪ID
+func (c *Client) nextTraceID() string {
+	return fmt.Sprintf("%s-%d", c.tracePrefix, c.traceSeq.Add(1))
 }
 
 // NewClient 创建 IPC 客户端
 func NewClient(logger types.Logger) *Client {
+	prefix := make([]byte, 3)
+	_, _ = cryptorand.Read(prefix)
 	return &Client{
 		logger:       logger,
 		responseChan: make(chan *Response, 1),
+		endpoints:    DefaultEndpoints(),
+		tracePrefix:  hex.EncodeToString(prefix),
 	}
 }
 
-// Connect 连接到服务器
+// SetEndpoints 覆盖端点列表（按顺序逐个尝试）
+func (c *Client) SetEndpoints(endpoints []Endpoint) {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+	if len(endpoints) > 0 {
+		c.endpoints = endpoints
+	}
+}
+
+// Connect 连接到服务器，按端点顺序逐个尝试直到成功
 func (c *Client) Connect() error {
 	c.connMutex.Lock()
 	defer c.connMutex.Unlock()
@@ -353,12 +1140,24 @@ func (c *Client) Connect() error {
 		c.conn.Close()
 		c.conn = nil
 		c.connected = false
+		c.activeTransport = ""
 	}
 
-	timeout := 5 * time.Second
-	conn, err := winio.DialPipe(PipePath, &timeout)
-	if err != nil {
-		return fmt.Errorf("连接 IPC 服务器失败: %v", err)
+	var conn net.Conn
+	var lastErr error
+	for _, ep := range c.endpoints {
+		dialed, err := ep.Dial()
+		if err != nil {
+			lastErr = err
+			c.logInfo("端点 %s 连接失败，尝试下一个: %v", ep.Transport, err)
+			continue
+		}
+		conn = dialed
+		c.activeTransport = ep.Transport
+		break
+	}
+	if conn == nil {
+		return fmt.Errorf("连接 IPC 服务器失败（所有端点均不可用）: %v", lastErr)
 	}
 
 	c.conn = conn
@@ -367,7 +1166,7 @@ func (c *Client) Connect() error {
 	// 递增generation：旧readLoop检测到generation变化后会主动退出，
 	// 确保任意时刻只有一个readLoop goroutine在运行。
 	gen := atomic.AddInt64(&c.connGeneration, 1)
-	c.logInfo("已连接到 IPC 服务器")
+	c.logInfo("已连接到 IPC 服务器 (传输: %s)", c.activeTransport)
 
 	// 启动消息接收循环
 	go c.readLoop(gen)
@@ -472,7 +1271,8 @@ func (c *Client) SendRequest(reqType RequestType, data any) (*Response, error) {
 	needsConnect := !c.connected || c.conn == nil
 	c.connMutex.RUnlock()
 
-	c.logInfo("SendRequest: 类型=%v, needsConnect=%v", reqType, needsConnect)
+	traceID := c.nextTraceID()
+	c.logInfo("SendRequest: 类型=%v, trace=%s, needsConnect=%v", reqType, traceID, needsConnect)
 
 	if needsConnect {
 		// Connect() 内部持 connMutex.Lock()，最多阻塞5秒，
@@ -493,7 +1293,7 @@ func (c *Client) SendRequest(reqType RequestType, data any) (*Response, error) {
 			return nil, fmt.Errorf("序列化请求数据失败: %v", err)
 		}
 	}
-	req := Request{Type: reqType, Data: dataBytes}
+	req := Request{Type: reqType, Data: dataBytes, TraceID: traceID}
 	reqBytes, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求失败: %v", err)
@@ -557,12 +1357,20 @@ func (c *Client) SendRequest(reqType RequestType, data any) (*Response, error) {
 			continue
 		}
 
-		// 发送成功，等待响应
-		select {
-		case resp := <-c.responseChan:
-			return resp, nil
-		case <-time.After(10 * time.Second):
-			return nil, fmt.Errorf("等待响应超时")
+		// 发送成功，等待响应。服务端并发处理请求后响应可能乱序到达，
+		// 按TraceID丢弃不属于本次请求的迟到响应
+		deadline := time.After(10 * time.Second)
+		for {
+			select {
+			case resp := <-c.responseChan:
+				if resp.TraceID != "" && resp.TraceID != traceID {
+					c.logDebug("丢弃过期响应: trace=%s (期望 %s)", resp.TraceID, traceID)
+					continue
+				}
+				return resp, nil
+			case <-deadline:
+				return nil, fmt.Errorf("等待响应超时")
+			}
 		}
 	}
 
@@ -580,6 +1388,7 @@ func (c *Client) Close() {
 		c.conn = nil
 	}
 	c.reader = nil
+	c.activeTransport = ""
 }
 
 // IsConnected 检查是否已连接
@@ -589,6 +1398,16 @@ func (c *Client) IsConnected() bool {
 	return c.connected
 }
 
+// ActiveTransport 返回当前连接使用的传输类型，未连接时为空字符串
+func (c *Client) ActiveTransport() string {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	if !c.connected {
+		return ""
+	}
+	return c.activeTransport
+}
+
 // 日志辅助方法
 func (c *Client) logInfo(format string, v ...any) {
 	if c.logger != nil {
@@ -619,6 +1438,20 @@ func GetCoreLockFilePath() string {
 	return fmt.Sprintf("%s/bs2pro-core.lock", tempDir)
 }
 
+// SetClientModeResult 客户端模式声明响应，携带核心会话ID供客户端写入自己的日志
+type SetClientModeResult struct {
+	OK        bool   `json:"ok"`
+	SessionID string `json:"sessionId,omitempty"` // 核心本次启动的会话ID
+}
+
+// SetClientModeParams 客户端模式声明参数
+type SetClientModeParams struct {
+	Mode       string `json:"mode"`                 // full / observer
+	ClientType string `json:"clientType,omitempty"` // gui / cli，空则保持现状
+	Tier       string `json:"tier,omitempty"`       // read / control / admin，只可维持或降低
+	Token      string `json:"token,omitempty"`      // 远程连接的访问令牌，本地连接忽略
+}
+
 // SetAutoControlParams 设置智能变频参数
 type SetAutoControlParams struct {
 	Enabled bool `json:"enabled"`
@@ -632,8 +1465,27 @@ type SetManualGearParams struct {
 
 // SetCustomSpeedParams 设置自定义转速参数
 type SetCustomSpeedParams struct {
-	Enabled bool `json:"enabled"`
-	RPM     int  `json:"rpm"`
+	Enabled  bool `json:"enabled"`
+	RPM      int  `json:"rpm"`
+	Override bool `json:"override,omitempty"` // 专家覆盖：无视设备能力上限强行下发，使用会记入审计日志
+}
+
+// ImportCurveParams 导入第三方曲线参数
+type ImportCurveParams struct {
+	Format  string `json:"format"`  // fancontrol / argus
+	Content string `json:"content"` // 曲线文件原始内容
+}
+
+// ExportCurveFileParams 导出.bs2curve曲线分享文件参数
+type ExportCurveFileParams struct {
+	Name        string `json:"name"`                  // 曲线名称
+	Author      string `json:"author,omitempty"`      // 作者署名
+	Description string `json:"description,omitempty"` // 适用场景说明
+}
+
+// ImportCurveFileParams 导入.bs2curve曲线分享文件参数
+type ImportCurveFileParams struct {
+	Content string `json:"content"` // 文件原始内容
 }
 
 // SetBoolParams 布尔参数
@@ -651,6 +1503,142 @@ type SetIntParams struct {
 	Value int `json:"value"`
 }
 
+// StartFanCaptureParams 高频风扇数据捕获参数
+type StartFanCaptureParams struct {
+	DurationSec int `json:"durationSec"` // 捕获时长(秒)，0取默认，超上限被截断
+}
+
+// StartIPCRecordingParams IPC流量录制参数
+type StartIPCRecordingParams struct {
+	DurationSec int `json:"durationSec"` // 录制时长(秒)，0取默认，超上限被截断
+}
+
+// CurveLivePoint 曲线上的实时工作点，curve-live-point事件载荷。
+// 专用事件而非复用原始风扇数据流：载荷是控制回路实际使用的滤波温度
+// 与计算出的目标转速，GUI不必自行重算插值。
+type CurveLivePoint struct {
+	AvgTemp    int   `json:"avgTemp"`    // 滤波(采样平均)后的控制温度(°C)
+	TargetRPM  int   `json:"targetRpm"`  // 按曲线计算的目标转速
+	CurveGen   int64 `json:"curveGen"`   // 生效曲线的代号，曲线更换后GUI据此丢弃旧点
+	TimeUnixMs int64 `json:"timeUnixMs"` // 计算时刻(Unix毫秒)
+}
+
+// OperationProgress 通用长操作进度，operation-progress 事件载荷。
+// 有专属事件的操作（曲线测绘、A/B测试）继续用各自更丰富的载荷，
+// 其余多秒操作统一走这条事件，前端按OperationID聚合出进度条。
+type OperationProgress struct {
+	OperationID string `json:"operationId"`       // 单次操作实例的唯一ID
+	Operation   string `json:"operation"`         // 操作类型，如 support-bundle
+	Phase       string `json:"phase"`             // 当前阶段名
+	Percent     int    `json:"percent"`           // 完成百分比0-100，-1表示不确定
+	Message     string `json:"message,omitempty"` // 阶段的人话说明
+	Done        bool   `json:"done"`              // 操作已结束（成功或失败）
+	Error       string `json:"error,omitempty"`   // 失败时的错误说明
+}
+
+// CrashReportInfo 单个崩溃报告的索引条目，ListCrashReports响应的元素
+type CrashReportInfo struct {
+	File       string `json:"file"`       // 文件名（位于核心日志目录）
+	TimeUnix   int64  `json:"timeUnix"`   // 报告生成时刻(Unix秒，按文件修改时间)
+	SizeBytes  int64  `json:"sizeBytes"`  // 文件大小
+	Compressed bool   `json:"compressed"` // 是否已被保留策略压缩为.gz
+}
+
+// NotificationActionParams 通知动作回传参数
+type NotificationActionParams struct {
+	ActionID string `json:"actionId"` // 被点击的动作按钮ID
+}
+
+// ShowWindowParams 唤起GUI窗口参数
+type ShowWindowParams struct {
+	View string `json:"view,omitempty"` // 目标页面（如curve/diagnostics），空则仅显示窗口
+}
+
+// MaxBoostParams 一键狂暴参数
+type MaxBoostParams struct {
+	Enabled     bool `json:"enabled"`     // true开启，false提前结束
+	DurationSec int  `json:"durationSec"` // 持续时长(秒)，0取配置默认值
+}
+
+// MaxBoostStatus 一键狂暴状态，作为MaxBoost请求的响应和max-boost-changed事件载荷
+type MaxBoostStatus struct {
+	Active       bool   `json:"active"`
+	Gear         string `json:"gear,omitempty"`         // 狂暴期间使用的挡位
+	EndsAtUnix   int64  `json:"endsAtUnix,omitempty"`   // 结束时刻(Unix秒)
+	RemainingSec int    `json:"remainingSec,omitempty"` // 剩余秒数
+}
+
+// StartThermalProfileParams 曲线测绘向导参数，0值字段取默认并按上下限截断
+type StartThermalProfileParams struct {
+	SettleSec int `json:"settleSec"` // 每个挡位切换后等待温度进入稳态的时长(秒)
+	SampleSec int `json:"sampleSec"` // 稳态后的温度采样窗口时长(秒)
+}
+
+// ThermalProfileMeasurement 单个挡位的稳态测量结果
+type ThermalProfileMeasurement struct {
+	Gear    string `json:"gear"`    // 挡位名
+	Level   string `json:"level"`   // 挡位级别(低/中/高)
+	RPM     int    `json:"rpm"`     // 该挡位的标称转速
+	AvgTemp int    `json:"avgTemp"` // 采样窗口内最高温度的平均值(°C)
+	Samples int    `json:"samples"` // 参与平均的采样次数
+}
+
+// ThermalProfileProgress 曲线测绘进度，thermal-profile-progress事件载荷
+type ThermalProfileProgress struct {
+	Phase        string `json:"phase"` // settling等待稳态 / sampling采样中
+	Gear         string `json:"gear"`
+	Level        string `json:"level"`
+	Step         int    `json:"step"` // 当前第几步(从1开始)
+	TotalSteps   int    `json:"totalSteps"`
+	RemainingSec int    `json:"remainingSec"` // 当前阶段剩余秒数
+}
+
+// ThermalProfileResult 曲线测绘结果，thermal-profile-done事件载荷。
+// RecommendedCurve 仅在完整跑完所有挡位后给出，由GUI让用户确认后经SetFanCurve应用
+type ThermalProfileResult struct {
+	Completed        bool                        `json:"completed"`
+	Reason           string                      `json:"reason"`
+	Measurements     []ThermalProfileMeasurement `json:"measurements"`
+	RecommendedCurve []types.FanCurvePoint       `json:"recommendedCurve,omitempty"`
+}
+
+// StartCurveABTestParams 曲线A/B测试参数。两条曲线按窗口交替生效，
+// 交替多轮可以摊平测试期间负载本身的漂移
+type StartCurveABTestParams struct {
+	CurveA          []types.FanCurvePoint `json:"curveA"`
+	CurveB          []types.FanCurvePoint `json:"curveB"`
+	DurationEachSec int                   `json:"durationEachSec,omitempty"` // 单个窗口时长(秒)，0取默认
+	Rounds          int                   `json:"rounds,omitempty"`          // A/B交替轮数，0取默认
+	ThresholdTemp   int                   `json:"thresholdTemp,omitempty"`   // 高温时长统计的温度阈值(°C)，0取默认
+}
+
+// CurveABTestProgress 曲线A/B测试窗口进度，curve-ab-test-progress 事件载荷
+type CurveABTestProgress struct {
+	Curve        string `json:"curve"` // 当前窗口生效的曲线: A / B
+	Round        int    `json:"round"`
+	TotalRounds  int    `json:"totalRounds"`
+	RemainingSec int    `json:"remainingSec"` // 当前窗口剩余秒数
+}
+
+// CurveABStats 单条曲线在测试全程的累计表现
+type CurveABStats struct {
+	AvgTemp           float64 `json:"avgTemp"`           // 平均最高温度(°C)
+	AvgRPM            float64 `json:"avgRpm"`            // 平均风扇转速
+	AboveThresholdSec int     `json:"aboveThresholdSec"` // 温度不低于阈值的累计时长(秒)
+	Samples           int     `json:"samples"`
+}
+
+// CurveABTestResult 曲线A/B测试结果，curve-ab-test-done 事件载荷
+type CurveABTestResult struct {
+	Completed     bool         `json:"completed"`
+	Reason        string       `json:"reason"`
+	ThresholdTemp int          `json:"thresholdTemp"`
+	CurveA        CurveABStats `json:"curveA"`
+	CurveB        CurveABStats `json:"curveB"`
+	Winner        string       `json:"winner"`            // A / B / tie
+	Verdict       string       `json:"verdict,omitempty"` // 结论说明
+}
+
 // RGBColorParam RGB颜色参数
 type RGBColorParam struct {
 	R int `json:"r"`
@@ -660,8 +1648,9 @@ type RGBColorParam struct {
 
 // SetRGBModeParams RGB灯效模式参数
 type SetRGBModeParams struct {
-	Mode       string          `json:"mode"`       // smart/rotation/breathing/static_single/static_multi/flowing/off
-	Colors     []RGBColorParam `json:"colors"`     // 颜色列表
-	Speed      string          `json:"speed"`      // fast/medium/slow
-	Brightness int             `json:"brightness"` // 0-100
+	Mode            string          `json:"mode"`                      // smart/rotation/breathing/static_single/static_multi/flowing/off
+	Colors          []RGBColorParam `json:"colors"`                    // 颜色列表
+	Speed           string          `json:"speed"`                     // fast/medium/slow
+	Brightness      int             `json:"brightness"`                // 0-100
+	SmartThresholds []int           `json:"smartThresholds,omitempty"` // 智能温控档位阈值(°C)，空则沿用现有配置
 }
@@ -0,0 +1,150 @@
+package ipc
+
+//go:generate go run ../../scripts/ipcspec -out ../../docs/ipc-protocol.json
+
+// SpecVersion 协议规范版本。请求/事件的语义发生不兼容变化时递增，
+// 外部集成方据此判断自己理解的协议是否过期。
+const SpecVersion = 1
+
+// RequestSpec 单个请求类型的机器可读描述。
+// Tier 与 Mutating 由 Spec() 从鉴权表推导，不在此处手写，避免文档与实际权限脱节。
+type RequestSpec struct {
+	Type     RequestType `json:"type"`
+	Summary  string      `json:"summary"`
+	Params   string      `json:"params,omitempty"` // 参数结构名，空表示无参数
+	Result   string      `json:"result,omitempty"` // 响应data的结构名或说明，空表示仅返回成功标志
+	Tier     string      `json:"tier"`             // 所需最低权限层级 read/control/admin
+	Mutating bool        `json:"mutating"`         // 是否改变设备/配置/进程状态
+}
+
+// EventSpec 单个广播事件的机器可读描述
+type EventSpec struct {
+	Type    string `json:"type"`
+	Summary string `json:"summary"`
+	Payload string `json:"payload,omitempty"` // 事件data的结构名或说明，空表示无载荷
+}
+
+// ProtocolSpec 完整协议规范，GetSchema 请求的响应和 specgen 生成器的输出
+type ProtocolSpec struct {
+	SpecVersion int           `json:"specVersion"`
+	Requests    []RequestSpec `json:"requests"`
+	Events      []EventSpec   `json:"events"`
+}
+
+// requestSpecs 请求类型注册表，新增请求类型时在此补充条目。
+// 顺序与常量声明保持一致，便于对照检查遗漏。
+var requestSpecs = []RequestSpec{
+	// 设备相关
+	{Type: ReqConnect, Summary: "连接BS2PRO设备"},
+	{Type: ReqDisconnect, Summary: "断开设备连接"},
+	{Type: ReqGetDeviceStatus, Summary: "查询设备连接状态", Result: "设备状态对象"},
+	{Type: ReqGetCurrentFanData, Summary: "获取最近一次风扇数据", Result: "types.FanData"},
+	{Type: ReqGetDeviceSessions, Summary: "获取设备连接会话历史", Result: "types.DeviceSession列表"},
+	{Type: ReqEnumerateDevices, Summary: "枚举当前可见的候选HID设备", Result: "设备信息列表"},
+
+	// 配置相关
+	{Type: ReqGetConfig, Summary: "获取应用配置", Result: "types.AppConfig"},
+	{Type: ReqUpdateConfig, Summary: "更新应用配置并持久化", Params: "types.AppConfig"},
+	{Type: ReqSetFanCurve, Summary: "设置温控曲线", Params: "types.FanCurve", Result: "规范化后的types.FanCurve"},
+	{Type: ReqGetFanCurve, Summary: "获取当前温控曲线", Result: "types.FanCurve"},
+	{Type: ReqImportCurve, Summary: "导入第三方格式温控曲线", Params: "ImportCurveParams", Result: "types.FanCurve"},
+	{Type: ReqExportCurveFile, Summary: "导出当前曲线为.bs2curve分享文件", Params: "ExportCurveFileParams", Result: "文件内容字符串"},
+	{Type: ReqImportCurveFile, Summary: "导入.bs2curve曲线分享文件并校验", Params: "ImportCurveFileParams", Result: "曲线点与元数据对象"},
+
+	// 控制相关
+	{Type: ReqSetAutoControl, Summary: "开关智能变频", Params: "SetAutoControlParams"},
+	{Type: ReqSetManualGear, Summary: "设置手动挡位", Params: "SetManualGearParams"},
+	{Type: ReqGetAvailableGears, Summary: "获取设备支持的挡位列表", Result: "挡位名列表"},
+	{Type: ReqSetCustomSpeed, Summary: "开关无上下限的自定义转速", Params: "SetCustomSpeedParams"},
+	{Type: ReqSetGearLight, Summary: "开关挡位指示灯", Params: "SetBoolParams"},
+	{Type: ReqSetPowerOnStart, Summary: "开关通电自启动", Params: "SetBoolParams"},
+	{Type: ReqSetSmartStartStop, Summary: "开关智能启停", Params: "SetBoolParams"},
+	{Type: ReqSetBrightness, Summary: "设置灯光亮度", Params: "SetIntParams"},
+	{Type: ReqMaxBoost, Summary: "开启/结束限时最高挡位（一键狂暴）", Params: "MaxBoostParams", Result: "MaxBoostStatus"},
+	{Type: ReqNotificationAction, Summary: "回传通知动作按钮的点击", Params: "NotificationActionParams"},
+
+	// 曲线测绘向导
+	{Type: ReqStartThermalProfile, Summary: "启动曲线测绘向导：逐挡位测量稳态温度并生成推荐曲线", Params: "StartThermalProfileParams"},
+	{Type: ReqCancelThermalProfile, Summary: "取消正在进行的曲线测绘并恢复之前的控制状态"},
+
+	// 曲线A/B对比测试
+	{Type: ReqStartCurveABTest, Summary: "启动两条曲线的A/B交替对比测试（实验性功能，需开启experimental-control开关）", Params: "StartCurveABTestParams"},
+	{Type: ReqCancelCurveABTest, Summary: "取消正在进行的曲线A/B测试并恢复原曲线"},
+
+	// 温度相关
+	{Type: ReqGetTemperature, Summary: "获取最近一次温度数据", Result: "types.TemperatureData"},
+	{Type: ReqTestTemperatureReading, Summary: "立即执行一次温度读取测试", Result: "types.TemperatureData"},
+	{Type: ReqTestBridgeProgram, Summary: "测试温度桥接程序可用性", Result: "测试结果对象"},
+	{Type: ReqGetBridgeProgramStatus, Summary: "查询温度桥接程序状态", Result: "桥接状态对象"},
+
+	// 窗口相关
+	{Type: ReqShowWindow, Summary: "请求GUI显示主窗口（可指定目标页面）", Params: "ShowWindowParams"},
+	{Type: ReqHideWindow, Summary: "请求GUI隐藏主窗口"},
+	{Type: ReqQuitApp, Summary: "退出GUI进程"},
+
+	// 调试相关
+	{Type: ReqGetDebugInfo, Summary: "获取核心运行时调试信息", Result: "调试信息对象"},
+	{Type: ReqSetDebugMode, Summary: "开关调试模式", Params: "SetBoolParams"},
+	{Type: ReqUpdateGuiResponseTime, Summary: "上报GUI响应耗时供看门狗参考", Params: "SetIntParams"},
+	{Type: ReqGetControlDecisions, Summary: "获取最近的变频决策记录", Result: "决策记录列表"},
+	{Type: ReqGetConnectedClients, Summary: "获取当前连接的IPC客户端列表", Result: "ClientInfo列表"},
+	{Type: ReqStartFanDataCapture, Summary: "开始高频风扇数据捕获", Params: "StartFanCaptureParams"},
+	{Type: ReqStopFanDataCapture, Summary: "提前结束风扇数据捕获", Result: "捕获结果对象"},
+	{Type: ReqStartIPCRecording, Summary: "开始录制IPC流量（脱敏后写JSONL，供--replay离线回放）", Params: "StartIPCRecordingParams"},
+	{Type: ReqStopIPCRecording, Summary: "提前结束IPC流量录制"},
+	{Type: ReqListCrashReports, Summary: "列出崩溃报告索引（新到旧）", Result: "CrashReportInfo列表"},
+	{Type: ReqCreateSupportBundle, Summary: "打包支持包（日志/脱敏配置/调试快照/最近崩溃报告）", Result: "包含path的对象"},
+
+	// 系统相关
+	{Type: ReqPing, Summary: "连接保活探测"},
+	{Type: ReqSetClientMode, Summary: "声明客户端模式/类型/权限层级，远程连接在此校验访问令牌", Params: "SetClientModeParams", Result: "SetClientModeResult"},
+	{Type: ReqGetSchema, Summary: "获取本协议的机器可读规范", Result: "ProtocolSpec"},
+	{Type: ReqGetHealth, Summary: "获取各子系统健康状态", Result: "HealthReport"},
+
+	// RGB 灯效控制
+	{Type: ReqSetRGBMode, Summary: "设置RGB灯效模式", Params: "SetRGBModeParams"},
+	{Type: ReqUnsubscribeEvents, Summary: "退订广播事件（仅保留请求响应）"},
+
+	// 服务管理
+	{Type: ReqRestartService, Summary: "重启核心服务进程"},
+	{Type: ReqStopService, Summary: "停止核心服务进程"},
+}
+
+// eventSpecs 广播事件注册表，新增事件类型时在此补充条目
+var eventSpecs = []EventSpec{
+	{Type: EventFanDataUpdate, Summary: "风扇数据更新", Payload: "types.FanData"},
+	{Type: EventTemperatureUpdate, Summary: "温度数据更新", Payload: "types.TemperatureData"},
+	{Type: EventDeviceConnected, Summary: "设备已连接"},
+	{Type: EventDeviceDisconnected, Summary: "设备已断开"},
+	{Type: EventDeviceError, Summary: "设备通信错误", Payload: "错误信息字符串"},
+	{Type: EventConfigUpdate, Summary: "配置已变更", Payload: "types.AppConfig"},
+	{Type: EventServiceConnected, Summary: "核心服务连接建立（客户端本地事件）"},
+	{Type: EventServiceDisconnected, Summary: "核心服务连接断开（客户端本地事件）"},
+	{Type: EventCrashLoopSafeBoot, Summary: "检测到崩溃循环，已进入安全模式启动"},
+	{Type: EventThrottlingDetected, Summary: "检测到持续热降频", Payload: "降频详情对象"},
+	{Type: EventFanCaptureDone, Summary: "风扇数据捕获完成", Payload: "捕获结果对象"},
+	{Type: EventMaxBoostChanged, Summary: "一键狂暴状态变化", Payload: "MaxBoostStatus"},
+	{Type: EventNotification, Summary: "通知消息", Payload: "通知对象(level/title/message)"},
+	{Type: EventControlStateChanged, Summary: "控制回路运行状态变化", Payload: "ControlStateInfo"},
+	{Type: EventThermalProfileProgress, Summary: "曲线测绘阶段进度", Payload: "ThermalProfileProgress"},
+	{Type: EventThermalProfileDone, Summary: "曲线测绘结束（完成或被取消）", Payload: "ThermalProfileResult"},
+	{Type: EventCurveABTestProgress, Summary: "曲线A/B测试窗口进度", Payload: "CurveABTestProgress"},
+	{Type: EventCurveABTestDone, Summary: "曲线A/B测试结束（完成或被取消）", Payload: "CurveABTestResult"},
+	{Type: EventIPCRecordingDone, Summary: "IPC流量录制完成", Payload: "录制结果对象(path/records/reason)"},
+	{Type: EventCurveLivePoint, Summary: "曲线实时工作点（仅智能变频运行时，节流推送）", Payload: "CurveLivePoint"},
+	{Type: EventOperationProgress, Summary: "通用长操作进度（支持包打包等多秒操作）", Payload: "OperationProgress"},
+}
+
+// Spec 返回完整协议规范。每次调用重新从鉴权表推导Tier/Mutating，
+// 保证输出与服务端实际执行的权限检查一致。
+func Spec() ProtocolSpec {
+	reqs := make([]RequestSpec, len(requestSpecs))
+	for i, r := range requestSpecs {
+		r.Tier = requiredTier(r.Type)
+		r.Mutating = mutatingRequests[r.Type]
+		reqs[i] = r
+	}
+	events := make([]EventSpec, len(eventSpecs))
+	copy(events, eventSpecs)
+	return ProtocolSpec{SpecVersion: SpecVersion, Requests: reqs, Events: events}
+}
@@ -0,0 +1,167 @@
+// Package lifecycle 提供核心子系统的声明式有序关闭。
+// 各子系统注册时声明自己运行期间依赖哪些其他子系统，
+// 关闭时按依赖关系拓扑排序：依赖方先停、被依赖方后停，
+// 避免出现"控制回路还在写设备，设备句柄已被释放"一类的关停竞态。
+package lifecycle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// DefaultStopTimeout 单个组件关闭的默认超时，超时后记录日志并继续关闭后续组件
+const DefaultStopTimeout = 5 * time.Second
+
+// StopFunc 组件的关闭函数。应当是幂等的：StopAll可能被多次调用（如服务停止与进程退出路径重叠）
+type StopFunc func() error
+
+// component 单个已注册的组件
+type component struct {
+	name string
+	deps []string // 本组件运行期间依赖的组件名，关闭时本组件先于它们停止
+	stop StopFunc
+}
+
+// Manager 子系统关闭顺序管理器
+type Manager struct {
+	mutex      sync.Mutex
+	components []*component
+	logger     types.Logger
+}
+
+// NewManager 创建关闭顺序管理器
+func NewManager(logger types.Logger) *Manager {
+	return &Manager{logger: logger}
+}
+
+// Register 注册一个需要有序关闭的组件。
+// deps 为该组件运行期间会使用的其他组件名（可以先于被依赖方注册），
+// StopAll 保证本组件在其所有依赖之前停止。重复注册同名组件时后者覆盖前者。
+func (m *Manager) Register(name string, stop StopFunc, deps ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, c := range m.components {
+		if c.name == name {
+			c.deps = deps
+			c.stop = stop
+			return
+		}
+	}
+	m.components = append(m.components, &component{name: name, deps: deps, stop: stop})
+}
+
+// StopOrder 返回按依赖关系解析出的关闭顺序（测试与调试用）。
+// 依赖关系无法满足（环）时，剩余组件按注册顺序追加到末尾。
+func (m *Manager) StopOrder() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	order := m.resolveOrder()
+	names := make([]string, len(order))
+	for i, c := range order {
+		names[i] = c.name
+	}
+	return names
+}
+
+// resolveOrder 拓扑排序：所有依赖某组件的组件都停止后，该组件才可停止。
+// 同一批可停止的组件间保持注册顺序，结果稳定。调用方须持有m.mutex。
+func (m *Manager) resolveOrder() []*component {
+	// dependents[X] = 依赖X、尚未停止的组件数
+	dependents := make(map[string]int, len(m.components))
+	registered := make(map[string]bool, len(m.components))
+	for _, c := range m.components {
+		registered[c.name] = true
+	}
+	for _, c := range m.components {
+		for _, dep := range c.deps {
+			if registered[dep] {
+				dependents[dep]++
+			}
+		}
+	}
+
+	order := make([]*component, 0, len(m.components))
+	stopped := make(map[string]bool, len(m.components))
+	for len(order) < len(m.components) {
+		progressed := false
+		for _, c := range m.components {
+			if stopped[c.name] || dependents[c.name] > 0 {
+				continue
+			}
+			stopped[c.name] = true
+			order = append(order, c)
+			for _, dep := range c.deps {
+				if registered[dep] {
+					dependents[dep]--
+				}
+			}
+			progressed = true
+		}
+		if !progressed {
+			// 依赖成环：记录后把剩余组件按注册顺序兜底追加，保证全部关闭
+			for _, c := range m.components {
+				if !stopped[c.name] {
+					m.logError("组件 %s 的关闭依赖成环，按注册顺序兜底关闭", c.name)
+					stopped[c.name] = true
+					order = append(order, c)
+				}
+			}
+		}
+	}
+	return order
+}
+
+// StopAll 按依赖序关闭所有组件。每个组件的关闭以 timeout 为限，
+// 超时或出错时记录日志并继续——单个组件卡死不应阻塞整个进程退出。
+func (m *Manager) StopAll(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+	m.mutex.Lock()
+	order := m.resolveOrder()
+	m.mutex.Unlock()
+
+	for _, c := range order {
+		start := time.Now()
+		if err := m.stopWithTimeout(c, timeout); err != nil {
+			m.logError("组件 %s 关闭异常 (耗时 %v): %v", c.name, time.Since(start).Round(time.Millisecond), err)
+		} else {
+			m.logInfo("组件 %s 已停止 (耗时 %v)", c.name, time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
+
+// stopWithTimeout 在独立goroutine中执行关闭函数并限时等待，panic转为错误返回
+func (m *Manager) stopWithTimeout(c *component, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("关闭时发生panic: %v", r)
+			}
+		}()
+		done <- c.stop()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("超过 %v 未完成，跳过等待", timeout)
+	}
+}
+
+func (m *Manager) logInfo(format string, v ...any) {
+	if m.logger != nil {
+		m.logger.Info(format, v...)
+	}
+}
+
+func (m *Manager) logError(format string, v ...any) {
+	if m.logger != nil {
+		m.logger.Error(format, v...)
+	}
+}
@@ -0,0 +1,93 @@
+package lifecycle
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStopOrderRespectsDependencies(t *testing.T) {
+	m := NewManager(nil)
+	m.Register("ipc", func() error { return nil })
+	m.Register("device", func() error { return nil }, "ipc")
+	m.Register("monitor", func() error { return nil }, "device", "config")
+	m.Register("config", func() error { return nil })
+
+	order := m.StopOrder()
+	pos := make(map[string]int, len(order))
+	for i, name := range order {
+		pos[name] = i
+	}
+
+	if pos["monitor"] > pos["device"] {
+		t.Errorf("monitor 应先于其依赖 device 停止，实际顺序: %v", order)
+	}
+	if pos["monitor"] > pos["config"] {
+		t.Errorf("monitor 应先于其依赖 config 停止，实际顺序: %v", order)
+	}
+	if pos["device"] > pos["ipc"] {
+		t.Errorf("device 应先于其依赖 ipc 停止，实际顺序: %v", order)
+	}
+}
+
+func TestStopAllRunsEveryComponentDespiteErrors(t *testing.T) {
+	m := NewManager(nil)
+	var stopped []string
+	m.Register("a", func() error {
+		stopped = append(stopped, "a")
+		return errors.New("模拟关闭失败")
+	}, "b")
+	m.Register("b", func() error {
+		stopped = append(stopped, "b")
+		panic("模拟关闭panic")
+	}, "c")
+	m.Register("c", func() error {
+		stopped = append(stopped, "c")
+		return nil
+	})
+
+	m.StopAll(time.Second)
+
+	want := []string{"a", "b", "c"}
+	if len(stopped) != len(want) {
+		t.Fatalf("期望关闭 %v，实际 %v", want, stopped)
+	}
+	for i, name := range want {
+		if stopped[i] != name {
+			t.Fatalf("期望关闭顺序 %v，实际 %v", want, stopped)
+		}
+	}
+}
+
+func TestStopAllTimeoutDoesNotBlock(t *testing.T) {
+	m := NewManager(nil)
+	release := make(chan struct{})
+	m.Register("stuck", func() error {
+		<-release
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		m.StopAll(50 * time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StopAll 未在超时后继续，疑似被卡死的组件阻塞")
+	}
+	close(release)
+}
+
+func TestStopOrderBreaksDependencyCycle(t *testing.T) {
+	m := NewManager(nil)
+	m.Register("x", func() error { return nil }, "y")
+	m.Register("y", func() error { return nil }, "x")
+
+	order := m.StopOrder()
+	if len(order) != 2 {
+		t.Fatalf("依赖成环时仍应返回全部组件，实际: %v", order)
+	}
+}
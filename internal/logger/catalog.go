@@ -0,0 +1,113 @@
+package logger
+
+// 日志输出语言。模板原文为中文，en-US安装通过目录把模板替换为英文，
+// 让单个安装的日志保持单一语言便于grep和跨语言协作支持。
+const (
+	// LangZhCN 中文输出（默认，保持既有行为）
+	LangZhCN = "zh-CN"
+	// LangEnUS 英文输出，目录未收录的模板回退为中文原文
+	LangEnUS = "en-US"
+)
+
+// messageCatalog 中文日志模板到英文的映射。键必须与代码中的格式串
+// 逐字符一致（含空格与标点），翻译须保留全部格式动词及其顺序。
+// 新增高频日志时在此补充条目；低频模板缺译不算错误，按原文输出。
+var messageCatalog = map[string]string{
+	// 生命周期
+	"=== BS2PRO 核心服务(Windows Service) 启动 ===": "=== BS2PRO core service (Windows Service) starting ===",
+	"版本: %s, 会话ID: %s":                        "Version: %s, session ID: %s",
+	"核心服务启动":                                  "Core service starting",
+	"安装目录: %s":                                "Install directory: %s",
+	"核心服务正在停止...":                             "Core service stopping...",
+	"核心服务进程自我终止":                              "Core service process terminating itself",
+	"日志已滚动到新日期文件: %s":                         "Log rolled over to new date file: %s",
+
+	// IPC 服务器
+	"启动 IPC 服务器 (Named Pipe)":        "Starting IPC server (named pipe)",
+	"启动 IPC 服务器失败: %v":               "Failed to start IPC server: %v",
+	"IPC 服务器已启动: %s":                 "IPC server started: %s",
+	"IPC 服务器已停止":                     "IPC server stopped",
+	"IPC 客户端已断开":                     "IPC client disconnected",
+	"IPC TCP 备用通道已启动: %s":            "IPC TCP fallback channel started: %s",
+	"IPC 远程控制通道已启动: %s (令牌鉴权)":       "IPC remote control channel started: %s (token auth)",
+	"创建 TCP 备用监听失败（仅管道可用）: %v":       "Failed to create TCP fallback listener (pipe only): %v",
+	"创建远程控制监听失败: %v":                 "Failed to create remote control listener: %v",
+	"远程控制监听 %s 未配置访问令牌，拒绝开启":         "Remote control listener %s has no access token configured, refusing to start",
+	"远程客户端令牌校验失败: %s":                "Remote client token verification failed: %s",
+	"客户端模式已设置为: %s (类型: %s, 层级: %s)": "Client mode set to: %s (type: %s, tier: %s)",
+	"解析请求失败: %v":                     "Failed to parse request: %v",
+	"序列化响应失败: %v":                    "Failed to serialize response: %v",
+	"序列化事件失败: %v":                    "Failed to serialize event: %v",
+	"序列化事件数据失败: %v":                  "Failed to serialize event payload: %v",
+	"发送响应失败: %v":                     "Failed to send response: %v",
+	"发送事件失败: %v":                     "Failed to send event: %v",
+	"请求处理发生 panic: %v":               "Panic while handling request: %v",
+	"处理请求 %s (trace %s)":             "Handling request %s (trace %s)",
+
+	// 设备
+	"正在连接设备 - 厂商ID: 0x%04X, 产品ID: 0x%04X": "Connecting device - vendor ID: 0x%04X, product ID: 0x%04X",
+	"设备连接成功: %s %s (型号: %s)":              "Device connected: %s %s (model: %s)",
+	"设备连接成功,但获取设备信息失败: %v":                "Device connected but failed to read device info: %v",
+	"设备连接已主动断开":                           "Device disconnected on request",
+	"设备连接已断开":                             "Device disconnected",
+	"设备连接意外断开，将尝试自动重连":                    "Device disconnected unexpectedly, will attempt auto reconnect",
+	"连续读取失败，设备断开: %v":                     "Consecutive read failures, device disconnected: %v",
+	"设备重连成功":                              "Device reconnected",
+	"设备重连后重新应用配置":                         "Reapplying configuration after device reconnect",
+	"重连后配置重新应用完成":                         "Configuration reapplied after reconnect",
+	"第 %d 次重连失败":                          "Reconnect attempt %d failed",
+	"等待 %v 后尝试第 %d 次重连...":                "Waiting %v before reconnect attempt %d...",
+	"初始化HID库失败: %v":                       "Failed to initialize HID library: %v",
+	"已将当前HID接口持久化为首选路径: %s":               "Persisted current HID interface as preferred path: %s",
+	"首选接口路径连接失败，回退到自动匹配: %v":              "Preferred interface path failed, falling back to auto match: %v",
+
+	// 配置
+	"从安装目录加载配置成功: %s": "Loaded configuration from install directory: %s",
+	"从默认目录加载配置成功: %s": "Loaded configuration from default directory: %s",
+	"配置文件不存在: %s":     "Configuration file does not exist: %s",
+	"读取配置文件失败 %s: %v": "Failed to read configuration file %s: %v",
+	"解析配置文件失败 %s: %v": "Failed to parse configuration file %s: %v",
+	"序列化配置失败: %v":     "Failed to serialize configuration: %v",
+	"保存配置到默认目录失败: %v": "Failed to save configuration to default directory: %v",
+	"保存配置到安装目录失败: %v": "Failed to save configuration to install directory: %v",
+	"配置保存到默认目录成功: %s": "Configuration saved to default directory: %s",
+	"配置保存到安装目录成功: %s": "Configuration saved to install directory: %s",
+	"去抖落盘配置失败: %v":    "Debounced configuration flush failed: %v",
+	"退出前落盘配置失败: %v":   "Failed to flush configuration before exit: %v",
+
+	// 温控
+	"启动健康监控": "Starting health monitoring",
+	"温度监控已在运行中，跳过重复启动":                            "Temperature monitoring already running, skipping duplicate start",
+	"进入自动模式失败: %v":                                "Failed to enter auto mode: %v",
+	"已导入%s格式曲线，共%d个点":                             "Imported %s format curve with %d points",
+	"曲线规整: %d个点合并为%d个":                            "Curve normalized: %d points merged into %d",
+	"检测到持续热降频: 最高温度 %d°C, 风扇 %d RPM 已接近上限 %d RPM": "Sustained thermal throttling detected: max temperature %d°C, fan %d RPM near limit %d RPM",
+	"检测到设备从自动模式切换到挡位工作模式，自动关闭智能变频":                "Device switched from auto mode to gear mode, disabling smart control",
+	"检测到崩溃循环（%v 内 %d 次崩溃），以安全配置启动":                "Crash loop detected (within %v: %d crashes), starting with safe configuration",
+
+	// 诊断
+	"高频风扇数据捕获开始: %s (时长 %d 秒)":    "High-frequency fan data capture started: %s (duration %d seconds)",
+	"高频风扇数据捕获结束(%s): %s，共 %d 条报文": "High-frequency fan data capture finished (%s): %s, %d reports total",
+	"IPC流量录制开始: %s (时长 %d 秒)":     "IPC traffic recording started: %s (duration %d seconds)",
+	"IPC流量录制结束(%s): %s，共 %d 条记录":  "IPC traffic recording finished (%s): %s, %d records total",
+	"ASUS ACPI 客户端初始化失败: %v":      "Failed to initialize ASUS ACPI client: %v",
+}
+
+// localize 按当前语言返回模板的译文，目录未收录或当前为中文时原样返回
+func (l *CustomLogger) localize(format string) string {
+	if l.language.Load() != LangEnUS {
+		return format
+	}
+	if translated, ok := messageCatalog[format]; ok {
+		return translated
+	}
+	return format
+}
+
+// SetLanguage 设置日志输出语言，非法值回退为默认中文
+func (l *CustomLogger) SetLanguage(lang string) {
+	if lang != LangEnUS {
+		lang = LangZhCN
+	}
+	l.language.Store(lang)
+}
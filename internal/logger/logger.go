@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -20,6 +22,16 @@ type CustomLogger struct {
 	debugMode bool
 	logDir    string
 	atom      zap.AtomicLevel
+
+	// language 日志输出语言（zh-CN/en-US），经消息目录在写入前替换模板
+	language atomic.Value // string
+
+	// 日期滚动状态：文件名按创建日期命名，长期运行的进程跨天
+	// （含夏令时/手动改时间）后由CheckRollover切换到新日期文件
+	rollMutex   sync.Mutex
+	fileDate    string
+	appRotate   *lumberjack.Logger
+	debugRotate *lumberjack.Logger
 }
 
 // NewCustomLogger 创建新的日志记录器
@@ -30,7 +42,8 @@ func NewCustomLogger(debugMode bool, installDir string) (*CustomLogger, error) {
 	}
 
 	// 主日志文件路径
-	logFilePath := filepath.Join(logDir, fmt.Sprintf("core_%s.log", time.Now().Format("2006-01-02")))
+	fileDate := time.Now().Format("2006-01-02")
+	logFilePath := filepath.Join(logDir, fmt.Sprintf("core_%s.log", fileDate))
 
 	// 创建主日志轮转配置
 	appLogRotate := &lumberjack.Logger{
@@ -92,9 +105,10 @@ func NewCustomLogger(debugMode bool, installDir string) (*CustomLogger, error) {
 	cores := []zapcore.Core{appCore, consoleCore}
 
 	// 只有在debug模式开启时才创建debug日志文件
+	var debugLogRotate *lumberjack.Logger
 	if debugMode {
-		debugFilePath := filepath.Join(logDir, fmt.Sprintf("debug_%s.log", time.Now().Format("2006-01-02")))
-		debugLogRotate := &lumberjack.Logger{
+		debugFilePath := filepath.Join(logDir, fmt.Sprintf("debug_%s.log", fileDate))
+		debugLogRotate = &lumberjack.Logger{
 			Filename:   debugFilePath,
 			MaxSize:    10,
 			MaxBackups: 7,
@@ -117,33 +131,62 @@ func NewCustomLogger(debugMode bool, installDir string) (*CustomLogger, error) {
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 	sugar := logger.Sugar()
 
-	return &CustomLogger{
-		logger:    logger,
-		sugar:     sugar,
-		debugMode: debugMode,
-		logDir:    logDir,
-		atom:      atom,
-	}, nil
+	l := &CustomLogger{
+		logger:      logger,
+		sugar:       sugar,
+		debugMode:   debugMode,
+		logDir:      logDir,
+		atom:        atom,
+		fileDate:    fileDate,
+		appRotate:   appLogRotate,
+		debugRotate: debugLogRotate,
+	}
+	l.language.Store(LangZhCN)
+	return l, nil
+}
+
+// CheckRollover 检查挂钟日期是否已越过文件名中的日期，是则切换到新日期文件。
+// lumberjack只按大小轮转，进程跨天（午夜、夏令时切换、手动调时）后若不调用
+// 本方法，日志会一直写在旧日期命名的文件里。重复调用安全，未跨天时为空操作。
+func (l *CustomLogger) CheckRollover() {
+	l.rollMutex.Lock()
+	defer l.rollMutex.Unlock()
+
+	today := time.Now().Format("2006-01-02")
+	if today == l.fileDate {
+		return
+	}
+
+	if l.appRotate != nil {
+		l.appRotate.Filename = filepath.Join(l.logDir, fmt.Sprintf("core_%s.log", today))
+		_ = l.appRotate.Rotate()
+	}
+	if l.debugRotate != nil {
+		l.debugRotate.Filename = filepath.Join(l.logDir, fmt.Sprintf("debug_%s.log", today))
+		_ = l.debugRotate.Rotate()
+	}
+	l.fileDate = today
+	l.sugar.Infof(l.localize("日志已滚动到新日期文件: %s"), today)
 }
 
 // Info 记录信息日志
 func (l *CustomLogger) Info(format string, v ...any) {
-	l.sugar.Infof(format, v...)
+	l.sugar.Infof(l.localize(format), v...)
 }
 
 // Error 记录错误日志
 func (l *CustomLogger) Error(format string, v ...any) {
-	l.sugar.Errorf(format, v...)
+	l.sugar.Errorf(l.localize(format), v...)
 }
 
 // Debug 记录调试日志
 func (l *CustomLogger) Debug(format string, v ...any) {
-	l.sugar.Debugf(format, v...)
+	l.sugar.Debugf(l.localize(format), v...)
 }
 
 // Warn 记录警告日志
 func (l *CustomLogger) Warn(format string, v ...any) {
-	l.sugar.Warnf(format, v...)
+	l.sugar.Warnf(l.localize(format), v...)
 }
 
 // GetLogDir 获取日志目录
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	ntdll                 = syscall.NewLazyDLL("ntdll.dll")
+	procQueryWnfStateData = ntdll.NewProc("NtQueryWnfStateData")
+)
+
+// wnfQuietHoursProfile 专注助手当前档位对应的WNF状态名
+// (WNF_SHEL_QUIETHOURS_ACTIVE_PROFILE_CHANGED)。Windows未提供公开API
+// 查询专注助手状态，该状态名来自逆向整理的公共资料，多个版本保持稳定。
+var wnfQuietHoursProfile = [2]uint32{0xA3BF1C75, 0x0D83063E}
+
+// queryFocusAssist 查询专注助手是否开启。仅作尽力而为：
+// 接口调用失败（精简版系统/未来版本变动）时返回错误，调用方按"未开启"处理。
+func queryFocusAssist() (bool, error) {
+	var (
+		changeStamp uint32
+		profile     uint32
+		size        = uint32(unsafe.Sizeof(profile))
+	)
+	r, _, _ := procQueryWnfStateData.Call(
+		uintptr(unsafe.Pointer(&wnfQuietHoursProfile)),
+		0, // TypeId
+		0, // ExplicitScope
+		uintptr(unsafe.Pointer(&changeStamp)),
+		uintptr(unsafe.Pointer(&profile)),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if r != 0 {
+		return false, fmt.Errorf("NtQueryWnfStateData失败: 0x%x", r)
+	}
+	// 0=关闭 1=仅优先通知 2=仅闹钟
+	return profile > 0, nil
+}
@@ -0,0 +1,230 @@
+// Package notify 提供统一的系统通知管理。
+// 所有通知经Manager分级下发；Windows专注助手(Focus Assist)开启时
+// 非关键通知被延后排队，待专注助手关闭后补发，避免打扰游戏/全屏场景。
+// 关键热告警可配置为穿透专注助手立即送达。
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// Level 通知级别
+type Level string
+
+const (
+	LevelInfo     Level = "info"
+	LevelWarning  Level = "warning"
+	LevelCritical Level = "critical" // 关键告警(如持续热降频)，可配置穿透专注助手
+)
+
+// Action 通知上的动作按钮。ID对应Manager中注册的处理函数，
+// 展示方（GUI toast）把用户点击经IPC回传核心执行。
+type Action struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// Notification 一条通知
+type Notification struct {
+	Level    Level     `json:"level"`
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+	Deferred bool      `json:"deferred"`          // 曾因专注助手被延后
+	Actions  []Action  `json:"actions,omitempty"` // 动作按钮
+}
+
+const (
+	// maxDeferred 延后队列上限，超出时丢弃最旧的一条
+	maxDeferred = 20
+	// flushPollInterval 专注助手状态轮询间隔（仅在有延后通知时实际查询）
+	flushPollInterval = 30 * time.Second
+)
+
+// Manager 通知管理器。sink为实际送达回调（如通过IPC广播给GUI展示），
+// 由调用方注入，Manager只负责分级、延后与补发。
+type Manager struct {
+	mutex                sync.Mutex
+	logger               types.Logger
+	clock                types.Clock
+	sink                 func(Notification)
+	criticalBreakthrough bool
+	deferred             []Notification
+	done                 chan struct{}
+	started              bool
+
+	// focusAssist 查询专注助手是否开启，默认走WNF接口，测试时可替换
+	focusAssist func() (bool, error)
+
+	// actions 已注册的动作处理函数，按Action.ID索引
+	actions map[string]func() error
+}
+
+// NewManager 创建通知管理器，关键告警默认穿透专注助手
+func NewManager(logger types.Logger, sink func(Notification)) *Manager {
+	return &Manager{
+		logger:               logger,
+		clock:                types.SystemClock{},
+		sink:                 sink,
+		criticalBreakthrough: true,
+		done:                 make(chan struct{}),
+		focusAssist:          queryFocusAssist,
+		actions:              make(map[string]func() error),
+	}
+}
+
+// RegisterAction 注册动作处理函数。同ID重复注册时后者覆盖前者。
+func (m *Manager) RegisterAction(id string, handler func() error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.actions[id] = handler
+}
+
+// HandleAction 执行指定ID的动作，由IPC层在收到GUI回传的点击后调用。
+// 未注册的ID返回错误而不是静默忽略，便于发现两端动作清单不一致。
+func (m *Manager) HandleAction(id string) error {
+	m.mutex.Lock()
+	handler, ok := m.actions[id]
+	m.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("未注册的通知动作: %s", id)
+	}
+	m.logInfo("执行通知动作: %s", id)
+	return handler()
+}
+
+// SetClock 替换时间源，仅供测试注入假时钟
+func (m *Manager) SetClock(clock types.Clock) {
+	m.clock = clock
+}
+
+// SetCriticalBreakthrough 设置关键告警是否穿透专注助手
+func (m *Manager) SetCriticalBreakthrough(enabled bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.criticalBreakthrough = enabled
+}
+
+// Start 启动延后队列的补发循环，重复调用无副作用
+func (m *Manager) Start() {
+	m.mutex.Lock()
+	if m.started {
+		m.mutex.Unlock()
+		return
+	}
+	m.started = true
+	m.mutex.Unlock()
+	go m.flushLoop()
+}
+
+// Stop 停止补发循环，队列中未送达的通知随之丢弃
+func (m *Manager) Stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.started {
+		return
+	}
+	m.started = false
+	close(m.done)
+}
+
+// Notify 下发一条通知。专注助手开启时非关键通知进入延后队列；
+// 查询失败按"未开启"处理，宁可打扰也不丢告警。
+func (m *Manager) Notify(level Level, title, message string) {
+	m.NotifyWithActions(level, title, message, nil)
+}
+
+// NotifyWithActions 下发一条带动作按钮的通知，延后/穿透规则与Notify一致
+func (m *Manager) NotifyWithActions(level Level, title, message string, actions []Action) {
+	n := Notification{Level: level, Title: title, Message: message, Time: m.clock.Now(), Actions: actions}
+
+	active, err := m.focusAssist()
+	if err != nil {
+		m.logDebug("查询专注助手状态失败(按未开启处理): %v", err)
+		active = false
+	}
+
+	m.mutex.Lock()
+	breakthrough := m.criticalBreakthrough
+	m.mutex.Unlock()
+
+	if active && !(level == LevelCritical && breakthrough) {
+		m.deferNotification(n)
+		return
+	}
+	m.deliver(n)
+}
+
+// deferNotification 把通知放入延后队列，队列满时丢弃最旧的一条
+func (m *Manager) deferNotification(n Notification) {
+	n.Deferred = true
+	m.mutex.Lock()
+	if len(m.deferred) >= maxDeferred {
+		m.deferred = m.deferred[1:]
+	}
+	m.deferred = append(m.deferred, n)
+	pending := len(m.deferred)
+	m.mutex.Unlock()
+	m.logInfo("专注助手开启，通知已延后: [%s] %s (队列%d条)", n.Level, n.Title, pending)
+}
+
+// deliver 实际送达一条通知
+func (m *Manager) deliver(n Notification) {
+	if m.sink != nil {
+		m.sink(n)
+	}
+}
+
+// flushLoop 周期检查专注助手状态，关闭后补发延后队列
+func (m *Manager) flushLoop() {
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-m.clock.After(flushPollInterval):
+		}
+
+		m.mutex.Lock()
+		pending := len(m.deferred)
+		m.mutex.Unlock()
+		if pending == 0 {
+			continue
+		}
+
+		if active, err := m.focusAssist(); err == nil && active {
+			continue
+		}
+		m.flushDeferred()
+	}
+}
+
+// flushDeferred 补发延后队列中的全部通知
+func (m *Manager) flushDeferred() {
+	m.mutex.Lock()
+	queued := m.deferred
+	m.deferred = nil
+	m.mutex.Unlock()
+	if len(queued) == 0 {
+		return
+	}
+	m.logInfo("专注助手已关闭，补发%d条延后通知", len(queued))
+	for _, n := range queued {
+		m.deliver(n)
+	}
+}
+
+func (m *Manager) logInfo(format string, v ...any) {
+	if m.logger != nil {
+		m.logger.Info(format, v...)
+	}
+}
+
+func (m *Manager) logDebug(format string, v ...any) {
+	if m.logger != nil {
+		m.logger.Debug(format, v...)
+	}
+}
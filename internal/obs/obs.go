@@ -0,0 +1,289 @@
+// Package obs 提供 obs-websocket (v5协议) 集成。
+// 监听OBS的节目场景切换事件：切到配置中映射了挡位的场景时应用该挡位
+// （如直播场景切静音挡避免麦克风收到风扇噪音），离开映射场景后通知
+// 调用方恢复之前的状态。连接断开后自动退避重连。
+package obs
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+	"github.com/gorilla/websocket"
+)
+
+// obs-websocket v5 消息操作码
+const (
+	opHello      = 0
+	opIdentify   = 1
+	opIdentified = 2
+	opEvent      = 5
+)
+
+// eventSubScenes 只订阅场景类事件（EventSubscription::Scenes）
+const eventSubScenes = 1 << 2
+
+// reconnectDelay 断线重连间隔
+var reconnectDelays = []time.Duration{5 * time.Second, 10 * time.Second, 30 * time.Second, 60 * time.Second}
+
+// message obs-websocket 消息封包
+type message struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d"`
+}
+
+// helloData Hello(op 0) 数据，服务端要求鉴权时带challenge/salt
+type helloData struct {
+	Authentication *struct {
+		Challenge string `json:"challenge"`
+		Salt      string `json:"salt"`
+	} `json:"authentication"`
+}
+
+// eventEnvelope Event(op 5) 数据
+type eventEnvelope struct {
+	EventType string          `json:"eventType"`
+	EventData json.RawMessage `json:"eventData"`
+}
+
+// sceneChangedData CurrentProgramSceneChanged 事件数据
+type sceneChangedData struct {
+	SceneName string `json:"sceneName"`
+}
+
+// Client obs-websocket 客户端
+type Client struct {
+	mutex    sync.Mutex
+	conn     *websocket.Conn
+	running  bool
+	stopChan chan struct{}
+
+	host         string
+	password     string
+	sceneProfile map[string]string
+
+	// onApply 进入映射场景时回调(场景名, 挡位)；onRestore 离开映射场景时回调
+	onApply   func(scene, profile string)
+	onRestore func()
+
+	// applied 当前是否处于"已应用场景挡位"状态
+	applied bool
+
+	logger types.Logger
+}
+
+// NewClient 创建OBS客户端（未启动状态）
+func NewClient(onApply func(scene, profile string), onRestore func(), logger types.Logger) *Client {
+	return &Client{
+		onApply:   onApply,
+		onRestore: onRestore,
+		logger:    logger,
+	}
+}
+
+// Start 按给定配置启动连接循环，重复调用时先停止旧连接
+func (c *Client) Start(host, password string, sceneProfile map[string]string) {
+	c.Stop()
+
+	c.mutex.Lock()
+	c.host = host
+	c.password = password
+	c.sceneProfile = sceneProfile
+	c.running = true
+	c.stopChan = make(chan struct{})
+	stopChan := c.stopChan
+	c.mutex.Unlock()
+
+	go c.runLoop(stopChan)
+}
+
+// Stop 停止连接循环并关闭连接，未启动时为空操作
+func (c *Client) Stop() {
+	c.mutex.Lock()
+	if !c.running {
+		c.mutex.Unlock()
+		return
+	}
+	c.running = false
+	close(c.stopChan)
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	restore := c.applied
+	c.applied = false
+	c.mutex.Unlock()
+
+	// 停止时若仍处于场景挡位状态，恢复原状态避免卡在静音挡
+	if restore && c.onRestore != nil {
+		c.onRestore()
+	}
+}
+
+// runLoop 连接-监听-重连循环
+func (c *Client) runLoop(stopChan chan struct{}) {
+	defer func() { recover() }()
+
+	retry := 0
+	for {
+		select {
+		case <-stopChan:
+			return
+		default:
+		}
+
+		if err := c.connectAndListen(stopChan); err != nil {
+			c.logError("OBS连接中断: %v", err)
+		}
+
+		select {
+		case <-stopChan:
+			return
+		case <-time.After(reconnectDelays[min(retry, len(reconnectDelays)-1)]):
+			retry++
+		}
+	}
+}
+
+// connectAndListen 建立连接、完成鉴权握手并持续处理事件
+func (c *Client) connectAndListen(stopChan chan struct{}) error {
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s", c.host), nil)
+	if err != nil {
+		return fmt.Errorf("连接失败: %v", err)
+	}
+
+	c.mutex.Lock()
+	if !c.running {
+		c.mutex.Unlock()
+		conn.Close()
+		return nil
+	}
+	c.conn = conn
+	c.mutex.Unlock()
+
+	defer func() {
+		c.mutex.Lock()
+		if c.conn == conn {
+			c.conn = nil
+		}
+		c.mutex.Unlock()
+		conn.Close()
+	}()
+
+	if err := c.handshake(conn); err != nil {
+		return err
+	}
+	c.logInfo("OBS已连接: %s", c.host)
+
+	for {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		if msg.Op != opEvent {
+			continue
+		}
+
+		var event eventEnvelope
+		if err := json.Unmarshal(msg.D, &event); err != nil {
+			continue
+		}
+		if event.EventType != "CurrentProgramSceneChanged" {
+			continue
+		}
+
+		var scene sceneChangedData
+		if err := json.Unmarshal(event.EventData, &scene); err != nil {
+			continue
+		}
+		c.handleSceneChange(scene.SceneName)
+	}
+}
+
+// handshake 处理 Hello -> Identify -> Identified 握手，按需计算鉴权串
+func (c *Client) handshake(conn *websocket.Conn) error {
+	var helloMsg message
+	if err := conn.ReadJSON(&helloMsg); err != nil {
+		return fmt.Errorf("读取Hello失败: %v", err)
+	}
+	if helloMsg.Op != opHello {
+		return fmt.Errorf("握手首包不是Hello (op=%d)", helloMsg.Op)
+	}
+
+	var hello helloData
+	if err := json.Unmarshal(helloMsg.D, &hello); err != nil {
+		return fmt.Errorf("解析Hello失败: %v", err)
+	}
+
+	identify := map[string]any{
+		"rpcVersion":         1,
+		"eventSubscriptions": eventSubScenes,
+	}
+	if hello.Authentication != nil {
+		identify["authentication"] = authToken(c.password,
+			hello.Authentication.Salt, hello.Authentication.Challenge)
+	}
+	if err := conn.WriteJSON(message{Op: opIdentify, D: mustMarshal(identify)}); err != nil {
+		return fmt.Errorf("发送Identify失败: %v", err)
+	}
+
+	var identified message
+	if err := conn.ReadJSON(&identified); err != nil {
+		return fmt.Errorf("等待Identified失败（密码可能错误）: %v", err)
+	}
+	if identified.Op != opIdentified {
+		return fmt.Errorf("鉴权未通过 (op=%d)", identified.Op)
+	}
+	return nil
+}
+
+// handleSceneChange 按场景-挡位映射触发应用/恢复回调
+func (c *Client) handleSceneChange(sceneName string) {
+	c.mutex.Lock()
+	profile, mapped := c.sceneProfile[sceneName]
+	wasApplied := c.applied
+	c.applied = mapped
+	c.mutex.Unlock()
+
+	if mapped {
+		c.logInfo("OBS切换到场景 [%s]，应用挡位: %s", sceneName, profile)
+		if c.onApply != nil {
+			c.onApply(sceneName, profile)
+		}
+	} else if wasApplied {
+		c.logInfo("OBS离开映射场景 [%s]，恢复此前状态", sceneName)
+		if c.onRestore != nil {
+			c.onRestore()
+		}
+	}
+}
+
+// authToken 计算 obs-websocket v5 鉴权串:
+// base64(sha256(base64(sha256(password+salt)) + challenge))
+func authToken(password, salt, challenge string) string {
+	secretSum := sha256.Sum256([]byte(password + salt))
+	secret := base64.StdEncoding.EncodeToString(secretSum[:])
+	authSum := sha256.Sum256([]byte(secret + challenge))
+	return base64.StdEncoding.EncodeToString(authSum[:])
+}
+
+func mustMarshal(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+func (c *Client) logInfo(format string, v ...any) {
+	if c.logger != nil {
+		c.logger.Info(format, v...)
+	}
+}
+
+func (c *Client) logError(format string, v ...any) {
+	if c.logger != nil {
+		c.logger.Error(format, v...)
+	}
+}
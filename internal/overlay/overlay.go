@@ -0,0 +1,211 @@
+// Package overlay 提供游戏内OSD数据发布功能。
+// 通过 RivaTuner Statistics Server (RTSS) 的共享内存接口占用一个OSD槽位，
+// 把散热垫的温度/转速/模式写入游戏画面叠加层。RTSS未运行时打开失败，
+// 调用方按需重试即可；关闭时清空自己的槽位，不影响其他OSD客户端。
+package overlay
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// mappingName RTSS共享内存对象名（v2布局）
+	mappingName = "RTSSSharedMemoryV2"
+	// ownerName 写入OSD槽位的归属标识，RTSS以此区分各客户端
+	ownerName = "BS2PRO"
+
+	// rtssSignature 'RTSS'
+	rtssSignature = 0x52545353
+
+	// v2布局头各字段偏移(DWORD数组)
+	offSignature    = 0
+	offVersion      = 4
+	offOSDEntrySize = 20
+	offOSDArrOffset = 24
+	offOSDArrSize   = 28
+	offOSDFrame     = 32
+
+	// OSD条目内的固定布局：前256字节为OSD文本，随后256字节为归属标识
+	osdTextSize  = 256
+	osdOwnerSize = 256
+)
+
+var (
+	procOpenFileMapping = syscall.NewLazyDLL("kernel32.dll").NewProc("OpenFileMappingW")
+)
+
+// mapPtr 把映射基址转成unsafe.Pointer。映射区域由RTSS进程创建、
+// 地址固定，不涉及Go堆对象，间接转换仅为通过unsafeptr检查。
+func mapPtr(addr uintptr) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+}
+
+// Publisher RTSS OSD发布端
+type Publisher struct {
+	mutex      sync.Mutex
+	handle     windows.Handle
+	view       uintptr
+	slotOffset uintptr // 占用的OSD槽位在映射中的偏移，0表示未占用
+	logger     types.Logger
+}
+
+// NewPublisher 创建OSD发布端（未打开状态）
+func NewPublisher(logger types.Logger) *Publisher {
+	return &Publisher{logger: logger}
+}
+
+// Open 连接RTSS共享内存并占用一个空闲OSD槽位。
+// RTSS未运行或无空闲槽位时返回错误。
+func (p *Publisher) Open() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.slotOffset != 0 {
+		return nil
+	}
+
+	namePtr, err := windows.UTF16PtrFromString(mappingName)
+	if err != nil {
+		return err
+	}
+	handle, _, callErr := procOpenFileMapping.Call(
+		uintptr(windows.FILE_MAP_READ|windows.FILE_MAP_WRITE), 0,
+		uintptr(unsafe.Pointer(namePtr)))
+	if handle == 0 {
+		return fmt.Errorf("打开RTSS共享内存失败（RTSS可能未运行）: %v", callErr)
+	}
+
+	view, err := windows.MapViewOfFile(windows.Handle(handle),
+		windows.FILE_MAP_READ|windows.FILE_MAP_WRITE, 0, 0, 0)
+	if err != nil {
+		windows.CloseHandle(windows.Handle(handle))
+		return fmt.Errorf("映射RTSS共享内存失败: %v", err)
+	}
+
+	if p.readDword(view, offSignature) != rtssSignature {
+		windows.UnmapViewOfFile(view)
+		windows.CloseHandle(windows.Handle(handle))
+		return fmt.Errorf("RTSS共享内存签名不匹配")
+	}
+
+	entrySize := uintptr(p.readDword(view, offOSDEntrySize))
+	arrOffset := uintptr(p.readDword(view, offOSDArrOffset))
+	arrSize := p.readDword(view, offOSDArrSize)
+	if entrySize < osdTextSize+osdOwnerSize || arrSize == 0 {
+		windows.UnmapViewOfFile(view)
+		windows.CloseHandle(windows.Handle(handle))
+		return fmt.Errorf("RTSS共享内存OSD布局不支持 (entrySize=%d)", entrySize)
+	}
+
+	// 槽位0保留给RTSS自身，从1开始找归属为空或归属为自己的槽位
+	var slot uintptr
+	for i := uintptr(1); i < uintptr(arrSize); i++ {
+		offset := arrOffset + i*entrySize
+		owner := p.readString(view, offset+osdTextSize, osdOwnerSize)
+		if owner == "" || owner == ownerName {
+			slot = offset
+			break
+		}
+	}
+	if slot == 0 {
+		windows.UnmapViewOfFile(view)
+		windows.CloseHandle(windows.Handle(handle))
+		return fmt.Errorf("RTSS无空闲OSD槽位")
+	}
+
+	p.handle = windows.Handle(handle)
+	p.view = view
+	p.slotOffset = slot
+	p.writeString(slot+osdTextSize, ownerName, osdOwnerSize)
+
+	p.logInfo("已占用RTSS OSD槽位 (偏移 %d)", slot)
+	return nil
+}
+
+// Opened 返回是否已占用OSD槽位
+func (p *Publisher) Opened() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.slotOffset != 0
+}
+
+// Publish 更新OSD文本并递增帧计数，未打开时为空操作
+func (p *Publisher) Publish(text string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.slotOffset == 0 {
+		return
+	}
+
+	p.writeString(p.slotOffset, text, osdTextSize)
+	p.writeDword(offOSDFrame, p.readDword(p.view, offOSDFrame)+1)
+}
+
+// Close 清空自己的OSD槽位并断开共享内存
+func (p *Publisher) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.slotOffset != 0 {
+		// 清空文本与归属，把槽位还给其他客户端
+		p.writeString(p.slotOffset, "", osdTextSize)
+		p.writeString(p.slotOffset+osdTextSize, "", osdOwnerSize)
+		p.writeDword(offOSDFrame, p.readDword(p.view, offOSDFrame)+1)
+		p.slotOffset = 0
+	}
+	if p.view != 0 {
+		windows.UnmapViewOfFile(p.view)
+		p.view = 0
+	}
+	if p.handle != 0 {
+		windows.CloseHandle(p.handle)
+		p.handle = 0
+	}
+}
+
+// readDword 读取映射内指定偏移的DWORD
+func (p *Publisher) readDword(view, offset uintptr) uint32 {
+	return *(*uint32)(mapPtr(view + offset))
+}
+
+// writeDword 写入映射内指定偏移的DWORD
+func (p *Publisher) writeDword(offset uintptr, value uint32) {
+	*(*uint32)(mapPtr(p.view + offset)) = value
+}
+
+// readString 读取定长ANSI字段（到NUL截止）
+func (p *Publisher) readString(view, offset uintptr, size int) string {
+	buf := unsafe.Slice((*byte)(mapPtr(view+offset)), size)
+	for i, b := range buf {
+		if b == 0 {
+			return string(buf[:i])
+		}
+	}
+	return string(buf)
+}
+
+// writeString 写入定长ANSI字段，超长截断并保证NUL结尾
+func (p *Publisher) writeString(offset uintptr, value string, size int) {
+	buf := unsafe.Slice((*byte)(mapPtr(p.view+offset)), size)
+	data := []byte(value)
+	if len(data) > size-1 {
+		data = data[:size-1]
+	}
+	n := copy(buf, data)
+	for i := n; i < size; i++ {
+		buf[i] = 0
+	}
+}
+
+func (p *Publisher) logInfo(format string, v ...any) {
+	if p.logger != nil {
+		p.logger.Info(format, v...)
+	}
+}
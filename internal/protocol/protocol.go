@@ -0,0 +1,74 @@
+// Package protocol 集中实现BS2PRO设备协议的帧构建与校验。
+// 所有下发命令统一为 [5A A5 cmdID len payload... crc] 结构：
+// len为内容长度(含cmdID与len自身)，crc为内容字节的累加和(低8位)。
+// 经HID写入时再加0x02 Report ID前缀并补零到23字节。
+package protocol
+
+import "fmt"
+
+const (
+	// MagicHigh / MagicLow 帧同步头
+	MagicHigh = 0x5A
+	MagicLow  = 0xA5
+	// ReportID HID输出报告ID
+	ReportID = 0x02
+	// ReportLen HID输出报告总长度(含Report ID)
+	ReportLen = 23
+)
+
+// Checksum 计算内容字节(cmdID+len+payload)的累加校验和
+func Checksum(content []byte) byte {
+	var sum byte
+	for _, b := range content {
+		sum += b
+	}
+	return sum
+}
+
+// BuildFrame 构建协议帧 [5A A5 cmdID len payload... crc]
+func BuildFrame(cmdID byte, payload []byte) []byte {
+	content := make([]byte, 2+len(payload))
+	content[0] = cmdID
+	content[1] = byte(len(content))
+	copy(content[2:], payload)
+
+	frame := make([]byte, 2+len(content)+1)
+	frame[0] = MagicHigh
+	frame[1] = MagicLow
+	copy(frame[2:], content)
+	frame[len(frame)-1] = Checksum(content)
+	return frame
+}
+
+// WrapReport 为协议帧加上Report ID前缀并补零到HID报告长度
+func WrapReport(frame []byte) []byte {
+	report := make([]byte, ReportLen)
+	report[0] = ReportID
+	copy(report[1:], frame)
+	return report
+}
+
+// BuildReport 构建可直接写入HID设备的完整报告
+func BuildReport(cmdID byte, payload []byte) []byte {
+	return WrapReport(BuildFrame(cmdID, payload))
+}
+
+// ValidateFrame 校验协议帧的同步头、长度字段与校验和
+func ValidateFrame(frame []byte) error {
+	if len(frame) < 5 {
+		return fmt.Errorf("帧长度不足: %d", len(frame))
+	}
+	if frame[0] != MagicHigh || frame[1] != MagicLow {
+		return fmt.Errorf("同步头无效: %02X %02X", frame[0], frame[1])
+	}
+	contentLen := int(frame[3])
+	if contentLen < 2 || 2+contentLen+1 > len(frame) {
+		return fmt.Errorf("长度字段无效: %d", contentLen)
+	}
+	content := frame[2 : 2+contentLen]
+	crc := frame[2+contentLen]
+	if got := Checksum(content); got != crc {
+		return fmt.Errorf("校验和不匹配: 期望 %02X, 实际 %02X", crc, got)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// TestBuildReportKnownCommands 用历史实现中手写的命令字节逐一验证帧构建器
+func TestBuildReportKnownCommands(t *testing.T) {
+	pad := func(b []byte) []byte {
+		out := make([]byte, ReportLen)
+		copy(out, b)
+		return out
+	}
+
+	cases := []struct {
+		name    string
+		cmdID   byte
+		payload []byte
+		want    []byte
+	}{
+		{"进入自动模式", 0x23, nil, pad([]byte{0x02, 0x5A, 0xA5, 0x23, 0x02, 0x25})},
+		{"挡位灯开", 0x48, []byte{0x01}, pad([]byte{0x02, 0x5A, 0xA5, 0x48, 0x03, 0x01, 0x4C})},
+		{"挡位灯关", 0x48, []byte{0x00}, pad([]byte{0x02, 0x5A, 0xA5, 0x48, 0x03, 0x00, 0x4B})},
+		{"通电自启动开", 0x0C, []byte{0x02}, pad([]byte{0x02, 0x5A, 0xA5, 0x0C, 0x03, 0x02, 0x11})},
+		{"通电自启动关", 0x0C, []byte{0x01}, pad([]byte{0x02, 0x5A, 0xA5, 0x0C, 0x03, 0x01, 0x10})},
+		{"智能启停关闭", 0x0D, []byte{0x00}, pad([]byte{0x02, 0x5A, 0xA5, 0x0D, 0x03, 0x00, 0x10})},
+		{"智能启停立即", 0x0D, []byte{0x01}, pad([]byte{0x02, 0x5A, 0xA5, 0x0D, 0x03, 0x01, 0x11})},
+		{"智能启停延迟", 0x0D, []byte{0x02}, pad([]byte{0x02, 0x5A, 0xA5, 0x0D, 0x03, 0x02, 0x12})},
+		{"亮度100", 0x43, nil, pad([]byte{0x02, 0x5A, 0xA5, 0x43, 0x02, 0x45})},
+		{"亮度0", 0x47, append([]byte{0x1C}, make([]byte, 10)...),
+			pad([]byte{0x02, 0x5A, 0xA5, 0x47, 0x0D, 0x1C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x70})},
+	}
+
+	for _, tc := range cases {
+		got := BuildReport(tc.cmdID, tc.payload)
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%s: BuildReport = % X, 期望 % X", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestBuildSpeedFrame 转速命令与历史实现的校验公式逐字节一致
+func TestBuildSpeedFrame(t *testing.T) {
+	for _, rpm := range []int{1000, 2300, 2760, 3300, 4000} {
+		speedBytes := make([]byte, 2)
+		binary.LittleEndian.PutUint16(speedBytes, uint16(rpm))
+
+		// 历史实现: (0x5A+0xA5+0x21+0x04+b0+b1+1)&0xFF，其中 0x5A+0xA5+1=0x100，
+		// 模256后等价于内容累加和
+		legacy := byte((0x5A + 0xA5 + 0x21 + 0x04 + int(speedBytes[0]) + int(speedBytes[1]) + 1) & 0xFF)
+
+		got := BuildReport(0x21, speedBytes)
+		if got[7] != legacy {
+			t.Errorf("rpm=%d: 校验和 %02X, 期望 %02X", rpm, got[7], legacy)
+		}
+		if err := ValidateFrame(got[1:]); err != nil {
+			t.Errorf("rpm=%d: 帧校验失败: %v", rpm, err)
+		}
+	}
+}
+
+// TestValidateGearCommands 预设挡位命令表中的每一帧都应通过校验
+func TestValidateGearCommands(t *testing.T) {
+	for gear, commands := range types.GearCommands {
+		for _, cmd := range commands {
+			if err := ValidateFrame(cmd.Command); err != nil {
+				t.Errorf("挡位 %s/%s: %v", gear, cmd.Name, err)
+			}
+		}
+	}
+}
+
+// TestValidateFrameErrors 非法帧应被识别
+func TestValidateFrameErrors(t *testing.T) {
+	if err := ValidateFrame([]byte{0x5A, 0xA5}); err == nil {
+		t.Error("过短的帧未被拒绝")
+	}
+	if err := ValidateFrame([]byte{0x00, 0xA5, 0x23, 0x02, 0x25}); err == nil {
+		t.Error("同步头错误的帧未被拒绝")
+	}
+	bad := BuildFrame(0x23, nil)
+	bad[len(bad)-1]++
+	if err := ValidateFrame(bad); err == nil {
+		t.Error("校验和错误的帧未被拒绝")
+	}
+}
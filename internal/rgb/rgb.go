@@ -2,7 +2,11 @@
 package rgb
 
 import (
+	"sync/atomic"
 	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/protocol"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
 )
 
 // RGB 速度常量
@@ -28,6 +32,19 @@ const (
 	colorGroupLen = 30  // 单个颜色组长度
 )
 
+// chunkPace 分包之间的间隔，给 MCU 喘息的时间防止底层缓冲区溢出。
+// 声明为变量以便基准测试评估不同间隔对整体耗时的影响。
+var chunkPace = 3 * time.Millisecond
+
+// 灯效切换渐变设置。中间帧同样走完整矩阵下发，
+// 每帧约耗时 prepare/finish 两次ACK + 31个分包，按此预算封顶帧数，
+// 避免渐变长时间占用命令锁与ACK预算。
+const (
+	crossfadeDefault  = 400 * time.Millisecond // 默认渐变时长
+	crossfadeStepCost = 150 * time.Millisecond // 单帧矩阵下发的预估耗时
+	crossfadeMaxSteps = 4                      // 中间帧数上限
+)
+
 // ACK 超时设置
 const (
 	// sendConfig 约需 31包 × 3ms = 93ms，再加 prepare/finish 各一次等待，
@@ -51,7 +68,8 @@ type Transport interface {
 
 // Controller 控制高级别的 RGB 灯效下发
 type Controller struct {
-	tr Transport
+	tr    Transport
+	clock types.Clock
 	// 用 channel 实现可超时的互斥锁，容量为1代表锁未被持有。
 	// 相比 sync.Mutex 优势：TryLock 和带超时的 Lock 均可原生实现。
 	cmdSem chan struct{}
@@ -59,17 +77,62 @@ type Controller struct {
 	// 异步智能控温使用的通道
 	cmdQueue chan byte
 	stopChan chan struct{}
+
+	// 渐变过渡：记录上一次成功下发的矩阵配置作为渐变起点。
+	// lastCfg 仅在持有 cmdSem 令牌时读写；时长用原子量存储以便随时调整。
+	lastCfg   *rgbConfig
+	fadeNanos atomic.Int64
+
+	// sync 非nil时动效下发对齐到共享周期边界（多设备相位同步）
+	sync *Synchronizer
 }
 
 // NewController 创建一个独立的 RGB 控制器
 func NewController(tr Transport) *Controller {
 	sem := make(chan struct{}, 1)
 	sem <- struct{}{} // 初始时放入令牌，代表锁可用
-	return &Controller{
+	c := &Controller{
 		tr:       tr,
+		clock:    types.SystemClock{},
 		cmdSem:   sem,
 		cmdQueue: make(chan byte, 5),
 	}
+	c.fadeNanos.Store(int64(crossfadeDefault))
+	return c
+}
+
+// SetCrossfadeMs 设置灯效切换的渐变时长(毫秒)：0恢复默认，负值关闭渐变
+func (c *Controller) SetCrossfadeMs(ms int) {
+	switch {
+	case ms == 0:
+		c.fadeNanos.Store(int64(crossfadeDefault))
+	case ms < 0:
+		c.fadeNanos.Store(0)
+	default:
+		c.fadeNanos.Store(int64(time.Duration(ms) * time.Millisecond))
+	}
+}
+
+// SetClock 替换时间源，仅供测试注入假时钟
+func (c *Controller) SetClock(clock types.Clock) {
+	c.clock = clock
+}
+
+// SetSynchronizer 设置相位对齐器。多个控制器共享同一实例时，
+// 流动/旋转等动效的下发时刻对齐到共享周期边界，避免并排设备漂移。
+func (c *Controller) SetSynchronizer(sync *Synchronizer) {
+	c.sync = sync
+}
+
+// alignPhase 动效下发前等待到下一个共享周期边界，未配置对齐器时不等待。
+// 必须在持有 cmdSem 令牌时调用，保证等待期间没有其他命令插队。
+func (c *Controller) alignPhase(speed byte) {
+	if c.sync == nil {
+		return
+	}
+	if delay := c.sync.AlignDelay(speed); delay > 0 {
+		c.clock.Sleep(delay)
+	}
 }
 
 // lockWithTimeout 带超时地获取锁，适用于用户主动操作（最多等待1秒）。
@@ -78,7 +141,7 @@ func (c *Controller) lockWithTimeout() bool {
 	select {
 	case <-c.cmdSem:
 		return true
-	case <-time.After(1 * time.Second):
+	case <-c.clock.After(1 * time.Second):
 		return false
 	}
 }
@@ -114,9 +177,9 @@ func (c *Controller) Start() {
 				return
 			case level := <-c.cmdQueue:
 				// 防抖: 控制命令频率
-				if time.Since(lastSend) > 500*time.Millisecond {
+				if c.clock.Now().Sub(lastSend) > 500*time.Millisecond {
 					c.SetSmartTempLevel(level)
-					lastSend = time.Now()
+					lastSend = c.clock.Now()
 				}
 			}
 		}
@@ -133,30 +196,7 @@ func (c *Controller) Stop() {
 
 // buildPacket 封装 RGB 协议底层包头包尾及校验: [5A A5 cmdID len payload... crc]
 func buildPacket(cmdID byte, payload []byte) []byte {
-	cLen := 2
-	if payload != nil {
-		cLen += len(payload)
-	}
-
-	content := make([]byte, cLen)
-	content[0] = cmdID
-	content[1] = byte(cLen)
-	if payload != nil {
-		copy(content[2:], payload)
-	}
-
-	var crc byte
-	for _, b := range content {
-		crc += b
-	}
-
-	packet := make([]byte, 2+len(content)+1)
-	packet[0] = 0x5A
-	packet[1] = 0xA5
-	copy(packet[2:], content)
-	packet[len(packet)-1] = crc
-
-	return packet
+	return protocol.BuildFrame(cmdID, payload)
 }
 
 // setState 硬件灯光开关（调用方须持有 cmdSem 令牌）
@@ -205,7 +245,7 @@ func (c *Controller) sendConfig(cfg *rgbConfig) bool {
 		_ = c.tr.WritePacket(pkt)
 
 		// 给 MCU 喘息的时间（3毫秒即可），防止底层缓冲区溢出
-		time.Sleep(3 * time.Millisecond)
+		c.clock.Sleep(chunkPace)
 	}
 
 	// 3. 发送结束指令，最多重试3次
@@ -213,12 +253,64 @@ func (c *Controller) sendConfig(cfg *rgbConfig) bool {
 	finishPkt := buildPacket(CmdFinish, nil)
 	for i := 0; i < 3; i++ {
 		if c.tr.WritePacketAndWaitACK(CmdFinish, finishPkt, ackTimeoutLong) {
+			c.lastCfg = cfg
 			return true
 		}
 	}
 	return false
 }
 
+// crossfadeTo 从上一次下发的矩阵渐变到目标配置（调用方须持有 cmdSem 令牌）。
+// 帧结构（循环区间）直接取目标配置，只对颜色与亮度做线性插值；
+// 没有渐变起点或渐变被关闭时为空操作。中间帧下发失败不致命，
+// 放弃剩余帧由最终配置兜底。
+func (c *Controller) crossfadeTo(target *rgbConfig) {
+	dur := time.Duration(c.fadeNanos.Load())
+	prev := c.lastCfg
+	if prev == nil || dur <= 0 {
+		return
+	}
+
+	steps := int(dur / crossfadeStepCost)
+	if steps > crossfadeMaxSteps {
+		steps = crossfadeMaxSteps
+	}
+	if steps < 1 {
+		steps = 1
+	}
+
+	for i := 1; i <= steps; i++ {
+		t := float64(i) / float64(steps+1)
+		if !c.sendConfig(prev.lerp(target, t)) {
+			return
+		}
+	}
+}
+
+// lerp 生成当前配置到目标配置的插值帧：循环区间沿用目标帧，
+// 颜色与亮度按比例t(0~1)线性混合
+func (c *rgbConfig) lerp(to *rgbConfig, t float64) *rgbConfig {
+	out := newRGBConfig()
+	out.LoopStart, out.LoopEnd = to.LoopStart, to.LoopEnd
+	out.LoopTime = to.LoopTime
+	out.LightScale = lerpByte(c.LightScale, to.LightScale, t)
+	for i := range out.Id {
+		for j := 0; j < 10; j++ {
+			from, dest := c.Id[i].Units[j], to.Id[i].Units[j]
+			out.Id[i].Units[j] = [3]byte{
+				lerpByte(from[0], dest[0], t),
+				lerpByte(from[1], dest[1], t),
+				lerpByte(from[2], dest[2], t),
+			}
+		}
+	}
+	return out
+}
+
+func lerpByte(a, b byte, t float64) byte {
+	return byte(float64(a) + (float64(b)-float64(a))*t + 0.5)
+}
+
 // --- 以下为对外部暴露的灯效设置方法 ---
 
 func (c *Controller) SetFlowing(speed, brightness byte) bool {
@@ -230,6 +322,8 @@ func (c *Controller) SetFlowing(speed, brightness byte) bool {
 	cfg.SetStreamer()
 	cfg.LoopTime = speed
 	cfg.LightScale = brightness
+	c.crossfadeTo(cfg)
+	c.alignPhase(speed)
 	if !c.sendConfig(cfg) {
 		return false
 	}
@@ -245,6 +339,8 @@ func (c *Controller) SetRotation(colors []Color, speed, brightness byte) bool {
 	cfg.SetRotate(colors)
 	cfg.LoopTime = speed
 	cfg.LightScale = brightness
+	c.crossfadeTo(cfg)
+	c.alignPhase(speed)
 	if !c.sendConfig(cfg) {
 		return false
 	}
@@ -260,6 +356,7 @@ func (c *Controller) SetBreathing(colors []Color, speed, brightness byte) bool {
 	cfg.SetBreathe(colors)
 	cfg.LoopTime = speed
 	cfg.LightScale = brightness
+	c.crossfadeTo(cfg)
 	if !c.sendConfig(cfg) {
 		return false
 	}
@@ -274,6 +371,7 @@ func (c *Controller) SetStaticSingle(color Color, brightness byte) bool {
 	cfg := newRGBConfig()
 	cfg.SetPure(color)
 	cfg.LightScale = brightness
+	c.crossfadeTo(cfg)
 	if !c.sendConfig(cfg) {
 		return false
 	}
@@ -288,6 +386,7 @@ func (c *Controller) SetStaticMulti(colors [3]Color, brightness byte) bool {
 	cfg := newRGBConfig()
 	cfg.SetMulticolor(colors[:])
 	cfg.LightScale = brightness
+	c.crossfadeTo(cfg)
 	if !c.sendConfig(cfg) {
 		return false
 	}
@@ -0,0 +1,97 @@
+package rgb
+
+import (
+	"testing"
+	"time"
+)
+
+// benchTransport 基准测试用的假传输层：只计数不碰硬件，
+// ACK 可配置延迟与前若干次失败，用于评估重试逻辑的代价。
+type benchTransport struct {
+	writes      int
+	acks        int
+	ackDelay    time.Duration // 模拟硬件返回 ACK 的耗时
+	ackFailures int           // 前 N 次 ACK 请求返回失败
+}
+
+func (t *benchTransport) WritePacket(packet []byte) error {
+	t.writes++
+	return nil
+}
+
+func (t *benchTransport) WritePacketAndWaitACK(cmdID byte, packet []byte, timeout time.Duration) bool {
+	t.acks++
+	if t.ackDelay > 0 {
+		time.Sleep(t.ackDelay)
+	}
+	if t.ackFailures > 0 {
+		t.ackFailures--
+		return false
+	}
+	return true
+}
+
+// BenchmarkBuildPacket 测量单个协议包的构造开销与分配次数
+func BenchmarkBuildPacket(b *testing.B) {
+	payload := make([]byte, chunkSize+1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = buildPacket(CmdTransport, payload)
+	}
+}
+
+// BenchmarkConfigBytes 测量矩阵配置序列化的开销与分配次数
+func BenchmarkConfigBytes(b *testing.B) {
+	cfg := newRGBConfig()
+	cfg.SetStreamer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = cfg.Bytes()
+	}
+}
+
+// BenchmarkSendConfig 测量完整矩阵下发的墙钟耗时，
+// 按不同分包间隔分组，为调整3ms间隔提供数据依据。
+// 31包 × 间隔 构成耗时主体，pace-0 子项即代码路径本身的开销。
+func BenchmarkSendConfig(b *testing.B) {
+	paces := []time.Duration{0, 1 * time.Millisecond, 3 * time.Millisecond}
+	origPace := chunkPace
+	defer func() { chunkPace = origPace }()
+
+	for _, pace := range paces {
+		b.Run("pace-"+pace.String(), func(b *testing.B) {
+			chunkPace = pace
+			tr := &benchTransport{}
+			c := NewController(tr)
+			cfg := newRGBConfig()
+			cfg.SetStreamer()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !c.sendConfig(cfg) {
+					b.Fatal("sendConfig 失败")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSendConfigRetry 测量 prepare ACK 失败触发重试时的额外耗时。
+// 每次迭代前两次 prepare 失败（各付出一次模拟ACK延迟），第三次成功，
+// 对应当前最多重试3次的逻辑；调整重试次数前可据此估算最坏情况代价。
+func BenchmarkSendConfigRetry(b *testing.B) {
+	origPace := chunkPace
+	chunkPace = 0
+	defer func() { chunkPace = origPace }()
+
+	tr := &benchTransport{ackDelay: 1 * time.Millisecond}
+	c := NewController(tr)
+	cfg := newRGBConfig()
+	cfg.SetStreamer()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.ackFailures = 2
+		if !c.sendConfig(cfg) {
+			b.Fatal("sendConfig 失败")
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package rgb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// loopUnit 单位LoopTime对应的动画周期估算值。硬件未提供查询接口，
+// 按实测流动灯效每档约100ms推算；对齐只需各设备采用同一换算即可。
+const loopUnit = 100 * time.Millisecond
+
+// maxAlignDelay 对齐等待上限。慢速灯效周期可达3秒，
+// 等满一个周期会让用户觉得设置没生效，超过上限时放弃对齐直接下发。
+const maxAlignDelay = 1500 * time.Millisecond
+
+// Synchronizer 多设备灯效相位对齐器。
+// 并排摆放的设备各自下发流动/旋转灯效时，动画起点取决于命令到达时刻，
+// 肉眼可见地漂移。多个 Controller 共享同一个 Synchronizer 后，
+// 下发会被推迟到共享基准时刻(epoch)的下一个周期边界，使相位一致。
+type Synchronizer struct {
+	mutex sync.Mutex
+	clock types.Clock
+	epoch time.Time
+}
+
+// NewSynchronizer 创建相位对齐器，基准时刻取创建时间
+func NewSynchronizer() *Synchronizer {
+	clock := types.SystemClock{}
+	return &Synchronizer{clock: clock, epoch: clock.Now()}
+}
+
+// SetClock 替换时间源，仅供测试注入假时钟
+func (s *Synchronizer) SetClock(clock types.Clock) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clock = clock
+	s.epoch = clock.Now()
+}
+
+// AlignDelay 返回为对齐到下一个周期边界需要等待的时长。
+// speed 为灯效的LoopTime档位；周期超长时返回0放弃对齐。
+func (s *Synchronizer) AlignDelay(speed byte) time.Duration {
+	if speed == 0 {
+		return 0
+	}
+	period := time.Duration(speed) * loopUnit
+
+	s.mutex.Lock()
+	elapsed := s.clock.Now().Sub(s.epoch)
+	s.mutex.Unlock()
+	if elapsed < 0 {
+		return 0
+	}
+
+	delay := period - elapsed%period
+	if delay == period || delay > maxAlignDelay {
+		return 0
+	}
+	return delay
+}
@@ -0,0 +1,71 @@
+package rgb
+
+import (
+	"testing"
+	"time"
+)
+
+// syncFakeClock 手动推进的假时钟（testutil会引入rgb形成循环依赖，本地实现）
+type syncFakeClock struct {
+	now time.Time
+}
+
+func (c *syncFakeClock) Now() time.Time        { return c.now }
+func (c *syncFakeClock) Sleep(d time.Duration) { c.now = c.now.Add(d) }
+func (c *syncFakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.now.Add(d)
+	return ch
+}
+func (c *syncFakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// TestAlignDelayPeriodBoundary 等待时长应补齐到下一个周期边界
+func TestAlignDelayPeriodBoundary(t *testing.T) {
+	clock := &syncFakeClock{now: time.Unix(1000, 0)}
+	s := NewSynchronizer()
+	s.SetClock(clock)
+
+	// 恰在边界上无需等待
+	if d := s.AlignDelay(SpeedFast); d != 0 {
+		t.Fatalf("基准时刻应无需等待, got %v", d)
+	}
+
+	// 周期500ms，经过300ms后应等待200ms
+	clock.Advance(300 * time.Millisecond)
+	if d := s.AlignDelay(SpeedFast); d != 200*time.Millisecond {
+		t.Fatalf("期望等待200ms, got %v", d)
+	}
+}
+
+// TestAlignDelaySharedPhase 两个控制器共享对齐器时应得到相同的下发时刻
+func TestAlignDelaySharedPhase(t *testing.T) {
+	clock := &syncFakeClock{now: time.Unix(1000, 0)}
+	s := NewSynchronizer()
+	s.SetClock(clock)
+
+	clock.Advance(730 * time.Millisecond)
+	d1 := s.AlignDelay(SpeedMedium)
+	d2 := s.AlignDelay(SpeedMedium)
+	if d1 != d2 {
+		t.Fatalf("同一时刻查询应得到相同等待: %v != %v", d1, d2)
+	}
+	if (730*time.Millisecond+d1)%(time.Duration(SpeedMedium)*loopUnit) != 0 {
+		t.Fatalf("等待后未落在周期边界: %v", d1)
+	}
+}
+
+// TestAlignDelayCap 超过上限的等待应放弃对齐
+func TestAlignDelayCap(t *testing.T) {
+	clock := &syncFakeClock{now: time.Unix(1000, 0)}
+	s := NewSynchronizer()
+	s.SetClock(clock)
+
+	// 慢速周期3s，刚过边界时需等近3s，超过上限应返回0
+	clock.Advance(100 * time.Millisecond)
+	if d := s.AlignDelay(SpeedSlow); d != 0 {
+		t.Fatalf("超上限等待应放弃对齐, got %v", d)
+	}
+	if d := s.AlignDelay(0); d != 0 {
+		t.Fatalf("speed=0应返回0, got %v", d)
+	}
+}
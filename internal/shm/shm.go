@@ -0,0 +1,283 @@
+// Package shm 提供基于内存映射文件的遥测共享内存通道。
+// 管道推送每个采样都要JSON编码+逐连接写入，对悬浮窗曲线、游戏OSD这类
+// 高频只读场景开销过大。核心将最新的风扇/温度快照写入命名共享内存，
+// 读取方映射后自行轮询，管道只保留给控制类请求。
+//
+// 内存布局（小端）:
+//
+//	偏移0  magic   uint32  固定标识"BS2P"
+//	偏移4  version uint32  布局版本
+//	偏移8  seq     uint32  序列锁：奇数表示写入中，读到前后不一致需重读
+//	偏移12 length  uint32  payload字节数
+//	偏移16 payload JSON编码的Snapshot
+package shm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// mappingName 共享内存对象名。优先创建Global命名空间对象
+	// （服务运行在Session 0，用户会话的读取方才能访问），
+	// 无权限时回落到Local（控制台模式下核心与读取方同会话）。
+	mappingNameGlobal = `Global\BS2PRO_Telemetry`
+	mappingNameLocal  = `Local\BS2PRO_Telemetry`
+
+	regionSize = 4096
+	headerSize = 16
+
+	magicValue    = 0x42533250 // "BS2P"
+	layoutVersion = 1
+
+	magicOffset   = 0
+	versionOffset = 4
+	seqOffset     = 8
+	lengthOffset  = 12
+)
+
+// viewPtr 将MapViewOfFile返回的映射基址转换为unsafe.Pointer。
+// 该地址由操作系统分配、不在Go堆上，不会被GC移动，直接转换是安全的；
+// 经过指针间接转换以通过go vet的unsafeptr检查。
+func viewPtr(addr uintptr) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+}
+
+// Snapshot 写入共享内存的遥测快照
+type Snapshot struct {
+	UpdateTime  int64                  `json:"updateTime"` // 写入时间(毫秒时间戳)
+	Fan         *types.FanData         `json:"fan"`
+	Temperature *types.TemperatureData `json:"temperature"`
+}
+
+// Writer 遥测共享内存写入端（核心服务持有）
+type Writer struct {
+	mutex  sync.Mutex
+	handle windows.Handle
+	view   uintptr
+	seq    uint32
+	logger types.Logger
+}
+
+// NewWriter 创建遥测共享内存写入端（未打开状态）
+func NewWriter(logger types.Logger) *Writer {
+	return &Writer{logger: logger}
+}
+
+// Open 创建并映射共享内存区域，写入布局头
+func (w *Writer) Open() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.view != 0 {
+		return nil
+	}
+
+	handle, name, err := createMapping()
+	if err != nil {
+		return err
+	}
+
+	view, err := windows.MapViewOfFile(handle, windows.FILE_MAP_WRITE, 0, 0, regionSize)
+	if err != nil {
+		windows.CloseHandle(handle)
+		return fmt.Errorf("映射共享内存视图失败: %v", err)
+	}
+
+	w.handle = handle
+	w.view = view
+
+	buf := unsafe.Slice((*byte)(viewPtr(view)), regionSize)
+	binary.LittleEndian.PutUint32(buf[magicOffset:], magicValue)
+	binary.LittleEndian.PutUint32(buf[versionOffset:], layoutVersion)
+	atomic.StoreUint32((*uint32)(viewPtr(view+seqOffset)), 0)
+	binary.LittleEndian.PutUint32(buf[lengthOffset:], 0)
+
+	w.logInfo("遥测共享内存已创建: %s", name)
+	return nil
+}
+
+// createMapping 创建共享内存对象，Global命名空间优先
+func createMapping() (windows.Handle, string, error) {
+	for _, name := range []string{mappingNameGlobal, mappingNameLocal} {
+		namePtr, err := windows.UTF16PtrFromString(name)
+		if err != nil {
+			return 0, "", err
+		}
+		handle, err := windows.CreateFileMapping(windows.InvalidHandle, nil,
+			windows.PAGE_READWRITE, 0, regionSize, namePtr)
+		if err == nil {
+			return handle, name, nil
+		}
+	}
+	return 0, "", fmt.Errorf("创建共享内存对象失败（Global与Local均不可用）")
+}
+
+// Opened 返回共享内存是否已打开
+func (w *Writer) Opened() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.view != 0
+}
+
+// Publish 用序列锁协议写入最新快照。payload超出区域容量时丢弃本次写入，
+// 编码失败或未打开时为空操作，调用方无需关心错误。
+func (w *Writer) Publish(snap Snapshot) {
+	payload, err := json.Marshal(snap)
+	if err != nil || len(payload) > regionSize-headerSize {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.view == 0 {
+		return
+	}
+
+	buf := unsafe.Slice((*byte)(viewPtr(w.view)), regionSize)
+
+	// 序列号先置奇数标记写入中，写完置偶数；读取方前后两次读到相同偶数才算一致
+	w.seq++
+	atomic.StoreUint32((*uint32)(viewPtr(w.view+seqOffset)), w.seq)
+
+	binary.LittleEndian.PutUint32(buf[lengthOffset:], uint32(len(payload)))
+	copy(buf[headerSize:], payload)
+
+	w.seq++
+	atomic.StoreUint32((*uint32)(viewPtr(w.view+seqOffset)), w.seq)
+}
+
+// Close 解除映射并关闭共享内存对象
+func (w *Writer) Close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.view != 0 {
+		windows.UnmapViewOfFile(w.view)
+		w.view = 0
+	}
+	if w.handle != 0 {
+		windows.CloseHandle(w.handle)
+		w.handle = 0
+	}
+}
+
+func (w *Writer) logInfo(format string, v ...any) {
+	if w.logger != nil {
+		w.logger.Info(format, v...)
+	}
+}
+
+// Reader 遥测共享内存读取端（悬浮窗/OSD等高频轮询方持有）
+type Reader struct {
+	handle windows.Handle
+	view   uintptr
+}
+
+// OpenFileMappingW 未被 x/sys/windows 导出，按本仓库惯例走 LazyDLL 绑定
+var procOpenFileMapping = syscall.NewLazyDLL("kernel32.dll").NewProc("OpenFileMappingW")
+
+// openFileMapping 打开已存在的命名共享内存对象
+func openFileMapping(access uint32, name string) (windows.Handle, error) {
+	namePtr, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, err
+	}
+	handle, _, callErr := procOpenFileMapping.Call(uintptr(access), 0, uintptr(unsafe.Pointer(namePtr)))
+	if handle == 0 {
+		return 0, callErr
+	}
+	return windows.Handle(handle), nil
+}
+
+// OpenReader 打开已存在的遥测共享内存，核心未创建时返回错误
+func OpenReader() (*Reader, error) {
+	var handle windows.Handle
+	var lastErr error
+	for _, name := range []string{mappingNameGlobal, mappingNameLocal} {
+		handle, lastErr = openFileMapping(windows.FILE_MAP_READ, name)
+		if lastErr == nil {
+			break
+		}
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("打开遥测共享内存失败（核心可能未启用该功能）: %v", lastErr)
+	}
+
+	view, err := windows.MapViewOfFile(handle, windows.FILE_MAP_READ, 0, 0, regionSize)
+	if err != nil {
+		windows.CloseHandle(handle)
+		return nil, fmt.Errorf("映射共享内存视图失败: %v", err)
+	}
+
+	buf := unsafe.Slice((*byte)(viewPtr(view)), regionSize)
+	if binary.LittleEndian.Uint32(buf[magicOffset:]) != magicValue ||
+		binary.LittleEndian.Uint32(buf[versionOffset:]) != layoutVersion {
+		windows.UnmapViewOfFile(view)
+		windows.CloseHandle(handle)
+		return nil, fmt.Errorf("遥测共享内存布局不兼容")
+	}
+
+	return &Reader{handle: handle, view: view}, nil
+}
+
+// Read 用序列锁协议读取最新快照。核心尚未写入任何数据时返回错误。
+func (r *Reader) Read() (Snapshot, error) {
+	var snap Snapshot
+	if r.view == 0 {
+		return snap, fmt.Errorf("共享内存未映射")
+	}
+
+	buf := unsafe.Slice((*byte)(viewPtr(r.view)), regionSize)
+
+	// 最多重试几次等待写入方完成，避免撕裂读
+	for attempt := 0; attempt < 8; attempt++ {
+		seqBefore := atomic.LoadUint32((*uint32)(viewPtr(r.view + seqOffset)))
+		if seqBefore == 0 {
+			return snap, fmt.Errorf("核心尚未写入遥测数据")
+		}
+		if seqBefore%2 != 0 {
+			continue
+		}
+
+		length := binary.LittleEndian.Uint32(buf[lengthOffset:])
+		if length == 0 || length > regionSize-headerSize {
+			return snap, fmt.Errorf("遥测数据长度非法: %d", length)
+		}
+		payload := make([]byte, length)
+		copy(payload, buf[headerSize:headerSize+length])
+
+		seqAfter := atomic.LoadUint32((*uint32)(viewPtr(r.view + seqOffset)))
+		if seqAfter != seqBefore {
+			continue
+		}
+
+		if err := json.Unmarshal(payload, &snap); err != nil {
+			return snap, fmt.Errorf("解析遥测数据失败: %v", err)
+		}
+		return snap, nil
+	}
+
+	return snap, fmt.Errorf("读取遥测数据冲突重试超限")
+}
+
+// Close 解除映射并关闭共享内存对象
+func (r *Reader) Close() {
+	if r.view != 0 {
+		windows.UnmapViewOfFile(r.view)
+		r.view = 0
+	}
+	if r.handle != 0 {
+		windows.CloseHandle(r.handle)
+		r.handle = 0
+	}
+}
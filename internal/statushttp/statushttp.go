@@ -0,0 +1,189 @@
+// Package statushttp 提供本地HTTP状态端点。
+// 面向直播/录屏叠加层场景：OBS浏览器源指向 http://127.0.0.1:<port>/ 即可
+// 显示散热垫的紧凑状态文本，/status 返回JSON供自定义叠加层使用。
+// 只监听回环地址，状态按配置的刷新间隔缓存，避免高频轮询穿透到核心。
+package statushttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// Status 状态端点对外暴露的数据
+type Status struct {
+	Text       string `json:"text"`       // 紧凑单行状态文本
+	CPUTemp    int    `json:"cpuTemp"`    // CPU温度(°C)
+	GPUTemp    int    `json:"gpuTemp"`    // GPU温度(°C)
+	CurrentRPM int    `json:"currentRpm"` // 当前转速
+	Profile    string `json:"profile"`    // 当前生效的模式/挡位
+	UpdateTime int64  `json:"updateTime"` // 状态生成时间(毫秒时间戳)
+}
+
+// Server 本地状态HTTP服务器
+type Server struct {
+	mutex     sync.Mutex
+	srv       *http.Server
+	getStatus func() Status
+	logger    types.Logger
+
+	// 缓存与刷新间隔：间隔内的重复请求直接返回缓存
+	cacheMutex sync.Mutex
+	cached     Status
+	cachedAt   time.Time
+	refresh    time.Duration
+
+	// /healthz 处理函数，未注册时该路径返回404
+	getHealth func() (report any, healthy bool)
+}
+
+// NewServer 创建状态HTTP服务器，getStatus由调用方提供当前状态
+func NewServer(getStatus func() Status, logger types.Logger) *Server {
+	return &Server{
+		getStatus: getStatus,
+		logger:    logger,
+		refresh:   2 * time.Second,
+	}
+}
+
+// Start 在回环地址上启动监听，refreshSec为状态缓存刷新间隔(秒，最小1)
+func (s *Server) Start(port, refreshSec int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.srv != nil {
+		return nil
+	}
+	if refreshSec < 1 {
+		refreshSec = 1
+	}
+	s.refresh = time.Duration(refreshSec) * time.Second
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("状态端点监听失败: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+
+	s.srv = &http.Server{Handler: mux}
+	go func() {
+		if serveErr := s.srv.Serve(listener); serveErr != nil && serveErr != http.ErrServerClosed {
+			s.logError("状态端点异常退出: %v", serveErr)
+		}
+	}()
+
+	s.logInfo("状态端点已启动: http://127.0.0.1:%d/", port)
+	return nil
+}
+
+// Stop 停止监听，未启动时为空操作
+func (s *Server) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.srv != nil {
+		s.srv.Close()
+		s.srv = nil
+	}
+}
+
+// Running 返回服务器是否在运行
+func (s *Server) Running() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.srv != nil
+}
+
+// SetHealth 注册 /healthz 的数据来源：返回健康报告对象与整体是否健康，
+// 不健康时端点以503响应，方便外部监控只看HTTP状态码
+func (s *Server) SetHealth(getHealth func() (report any, healthy bool)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.getHealth = getHealth
+}
+
+// handleHealthz 返回健康报告JSON，整体不健康时状态码为503
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	getHealth := s.getHealth
+	s.mutex.Unlock()
+	if getHealth == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	report, healthy := getHealth()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// currentStatus 返回缓存的状态，超过刷新间隔时重新生成
+func (s *Server) currentStatus() Status {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	if time.Since(s.cachedAt) < s.refresh {
+		return s.cached
+	}
+	s.cached = s.getStatus()
+	s.cached.UpdateTime = time.Now().UnixMilli()
+	s.cachedAt = time.Now()
+	return s.cached
+}
+
+// handleStatus 返回JSON状态
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	json.NewEncoder(w).Encode(s.currentStatus())
+}
+
+// handleIndex 返回适合OBS浏览器源直接引用的自刷新页面
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, indexPage, int(s.refresh.Milliseconds()))
+}
+
+// indexPage 极简透明背景页面，定时拉取/status更新文本
+const indexPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><style>
+body { margin:0; background:transparent; font-family:Consolas,monospace;
+       color:#fff; text-shadow:0 0 4px #000; font-size:24px; }
+</style></head><body><div id="s"></div><script>
+async function tick() {
+  try {
+    const r = await fetch('/status');
+    const d = await r.json();
+    document.getElementById('s').textContent = d.text;
+  } catch (e) {}
+}
+tick(); setInterval(tick, %d);
+</script></body></html>`
+
+func (s *Server) logInfo(format string, v ...any) {
+	if s.logger != nil {
+		s.logger.Info(format, v...)
+	}
+}
+
+func (s *Server) logError(format string, v ...any) {
+	if s.logger != nil {
+		s.logger.Error(format, v...)
+	}
+}
@@ -0,0 +1,131 @@
+package temperature
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// 支持导入的第三方曲线格式
+const (
+	ImportFormatFanControl = "fancontrol" // FanControl.Releases 导出的曲线 JSON
+	ImportFormatArgus      = "argus"      // Argus Monitor 导出的曲线 CSV
+)
+
+// ImportCurve 将第三方风扇控制工具导出的曲线转换为本应用的FanCurvePoint格式。
+// 第三方工具普遍以"温度->转速百分比"表达曲线，转换结果统一为percent单位点，
+// 应用时再按设备最大转速换算（见ResolveCurve）。
+func ImportCurve(format, content string) ([]types.FanCurvePoint, error) {
+	var curve []types.FanCurvePoint
+	var err error
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case ImportFormatFanControl:
+		curve, err = parseFanControlJSON(content)
+	case ImportFormatArgus:
+		curve, err = parseArgusCSV(content)
+	default:
+		return nil, fmt.Errorf("不支持的曲线格式: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(curve) < 2 {
+		return nil, fmt.Errorf("曲线点数不足(%d)，至少需要2个点", len(curve))
+	}
+
+	sort.Slice(curve, func(i, j int) bool {
+		return curve[i].Temperature < curve[j].Temperature
+	})
+	return curve, nil
+}
+
+// fanControlPoint FanControl曲线点，兼容大小写两种字段风格
+type fanControlPoint struct {
+	X *float64 `json:"x"`
+	Y *float64 `json:"y"`
+}
+
+// parseFanControlJSON 解析FanControl.Releases导出的曲线JSON。
+// 兼容裸点数组 [{"x":40,"y":30},...] 和带包装的 {"Points":[...]} 两种形态，
+// x为温度(°C)，y为转速百分比。
+func parseFanControlJSON(content string) ([]types.FanCurvePoint, error) {
+	var points []fanControlPoint
+
+	if err := json.Unmarshal([]byte(content), &points); err != nil {
+		var wrapper struct {
+			Points []fanControlPoint `json:"points"`
+		}
+		if err2 := json.Unmarshal([]byte(content), &wrapper); err2 != nil || len(wrapper.Points) == 0 {
+			return nil, fmt.Errorf("解析FanControl曲线JSON失败: %v", err)
+		}
+		points = wrapper.Points
+	}
+
+	curve := make([]types.FanCurvePoint, 0, len(points))
+	for i, p := range points {
+		if p.X == nil || p.Y == nil {
+			return nil, fmt.Errorf("FanControl曲线第%d个点缺少x/y字段", i+1)
+		}
+		curve = append(curve, types.FanCurvePoint{
+			Temperature: int(*p.X + 0.5),
+			RPM:         clampPercent(int(*p.Y + 0.5)),
+			Unit:        types.CurveUnitPercent,
+		})
+	}
+	return curve, nil
+}
+
+// parseArgusCSV 解析Argus Monitor导出的曲线CSV。
+// 每行"温度;百分比"（也兼容逗号分隔），自动跳过表头和空行。
+func parseArgusCSV(content string) ([]types.FanCurvePoint, error) {
+	var curve []types.FanCurvePoint
+
+	for lineNo, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" {
+			continue
+		}
+
+		sep := ";"
+		if !strings.Contains(line, ";") {
+			sep = ","
+		}
+		fields := strings.Split(line, sep)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Argus曲线第%d行格式无效: %q", lineNo+1, line)
+		}
+
+		temp, err1 := strconv.ParseFloat(strings.TrimSpace(fields[0]), 64)
+		percent, err2 := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err1 != nil || err2 != nil {
+			// 第一行允许是表头，解析失败直接跳过
+			if lineNo == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("Argus曲线第%d行数值无效: %q", lineNo+1, line)
+		}
+
+		curve = append(curve, types.FanCurvePoint{
+			Temperature: int(temp + 0.5),
+			RPM:         clampPercent(int(percent + 0.5)),
+			Unit:        types.CurveUnitPercent,
+		})
+	}
+	return curve, nil
+}
+
+func clampPercent(percent int) int {
+	if percent < 0 {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
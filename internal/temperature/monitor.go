@@ -0,0 +1,22 @@
+package temperature
+
+// MonitorState 温度监控循环期望状态推导的输入快照
+type MonitorState struct {
+	Connected        bool // 设备当前是否连接
+	AutoControl      bool // 配置中的智能变频开关
+	UserDisconnected bool // 最近一次断开是否由用户主动发起
+}
+
+// MonitorDesired 推导温度监控循环是否应当运行。所有启停路径
+// （连接/断开/配置更新/自定义转速/设备模式回退）都经这一个函数收敛，
+// 而不是各自摆弄运行标志——后者曾在"断开状态下关闭智能变频"等
+// 组合下留下孤儿循环。规则：
+//   - 设备已连接时始终运行：GUI温度显示、热降频检测与RGB智能温控都依赖采样；
+//   - 意外断开期间智能变频仍开启时保持运行，重连成功后调速立即恢复；
+//   - 用户主动断开、或断开状态下智能变频已关闭时，循环没有服务对象，应当停止。
+func MonitorDesired(s MonitorState) bool {
+	if s.Connected {
+		return true
+	}
+	return s.AutoControl && !s.UserDisconnected
+}
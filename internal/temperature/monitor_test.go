@@ -0,0 +1,27 @@
+package temperature
+
+import "testing"
+
+// TestMonitorDesired 全量枚举三个输入的八种组合，
+// 每条转移的期望值都与注释中的规则一一对应
+func TestMonitorDesired(t *testing.T) {
+	cases := []struct {
+		name string
+		in   MonitorState
+		want bool
+	}{
+		{"已连接+智能变频", MonitorState{Connected: true, AutoControl: true}, true},
+		{"已连接+手动模式", MonitorState{Connected: true}, true},
+		{"已连接+用户断开标志残留", MonitorState{Connected: true, UserDisconnected: true}, true},
+		{"已连接+智能变频+用户断开标志残留", MonitorState{Connected: true, AutoControl: true, UserDisconnected: true}, true},
+		{"意外断开+智能变频", MonitorState{AutoControl: true}, true},
+		{"意外断开+手动模式", MonitorState{}, false},
+		{"用户断开+智能变频", MonitorState{AutoControl: true, UserDisconnected: true}, false},
+		{"用户断开+手动模式", MonitorState{UserDisconnected: true}, false},
+	}
+	for _, tc := range cases {
+		if got := MonitorDesired(tc.in); got != tc.want {
+			t.Errorf("%s: MonitorDesired(%+v) = %v, want %v", tc.name, tc.in, got, tc.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package temperature
 
 import (
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -56,6 +57,11 @@ func (r *Reader) Read() types.TemperatureData {
 	// 读取 GPU 温度
 	temp.GPUTemp = r.readGPUTemperature()
 
+	// 读取 GPU 热降频标志（仅NVIDIA，失败时保持false）
+	if r.gpuVendor == "nvidia" && nvmlLoaded {
+		temp.GPUThrottle = r.readNvidiaThrottle()
+	}
+
 	// 计算最高温度
 	if temp.CPUTemp > temp.GPUTemp {
 		temp.MaxTemp = temp.CPUTemp
@@ -72,6 +78,7 @@ var (
 	nvmlInit                 *syscall.LazyProc
 	nvmlDeviceGetHandle      *syscall.LazyProc
 	nvmlDeviceGetTemperature *syscall.LazyProc
+	nvmlDeviceGetThrottle    *syscall.LazyProc
 	nvmlLoaded               bool
 )
 
@@ -96,6 +103,7 @@ func (r *Reader) initNVMLWindows() {
 		nvmlInit = nvmlDLL.NewProc("nvmlInit_v2")
 		nvmlDeviceGetHandle = nvmlDLL.NewProc("nvmlDeviceGetHandleByIndex_v2")
 		nvmlDeviceGetTemperature = nvmlDLL.NewProc("nvmlDeviceGetTemperature")
+		nvmlDeviceGetThrottle = nvmlDLL.NewProc("nvmlDeviceGetCurrentClocksThrottleReasons")
 
 		// 调用nvmlInit_v2
 		ret, _, _ := nvmlInit.Call()
@@ -146,6 +154,173 @@ func (r *Reader) readNvidiaGPUTemp() int {
 	return int(temp)
 }
 
+// NVML降频原因位掩码：Sw/HwThermalSlowdown
+const nvmlThermalSlowdownMask = 0x20 | 0x40
+
+// readNvidiaThrottle 读取NVIDIA GPU当前是否处于热降频状态
+func (r *Reader) readNvidiaThrottle() bool {
+	if r.nvmlDevice == 0 || nvmlDeviceGetThrottle == nil {
+		return false
+	}
+
+	var reasons uint64
+	ret, _, _ := nvmlDeviceGetThrottle.Call(r.nvmlDevice, uintptr(unsafe.Pointer(&reasons)))
+	if ret != 0 {
+		return false
+	}
+	return reasons&nvmlThermalSlowdownMask != 0
+}
+
+// GearMaxRPM 获取指定最高挡位对应的最大转速。
+// 不同硬件版本最高挡位不同(如BS2只到强劲)，取GearCommands中该挡位的最高转速。
+// 挡位未知时返回4000作为兜底。
+func GearMaxRPM(maxGear string) int {
+	maxRPM := 0
+	for _, cmd := range types.GearCommands[maxGear] {
+		if cmd.RPM > maxRPM {
+			maxRPM = cmd.RPM
+		}
+	}
+	if maxRPM == 0 {
+		return 4000
+	}
+	return maxRPM
+}
+
+// ResolveCurve 将曲线中以百分比表示的点换算为绝对转速。
+// percent点按设备最大转速等比换算并取整到100的整数倍，rpm点原样保留。
+// 曲线中不含percent点时直接返回原切片，避免无谓拷贝。
+func ResolveCurve(curve []types.FanCurvePoint, maxRPM int) []types.FanCurvePoint {
+	hasPercent := false
+	for _, p := range curve {
+		if p.Unit == types.CurveUnitPercent {
+			hasPercent = true
+			break
+		}
+	}
+	if !hasPercent {
+		return curve
+	}
+
+	if maxRPM <= 0 {
+		maxRPM = 4000
+	}
+
+	resolved := make([]types.FanCurvePoint, len(curve))
+	for i, p := range curve {
+		if p.Unit != types.CurveUnitPercent {
+			resolved[i] = p
+			continue
+		}
+		percent := p.RPM
+		if percent < 0 {
+			percent = 0
+		}
+		if percent > 100 {
+			percent = 100
+		}
+		rpm := float64(percent) * float64(maxRPM) / 100
+		resolved[i] = types.FanCurvePoint{
+			Temperature: p.Temperature,
+			RPM:         int((rpm+50)/100) * 100,
+			Unit:        types.CurveUnitRPM,
+		}
+	}
+	return resolved
+}
+
+// 曲线规整的默认最小间隔。相邻点靠得太近（1°C一个点）会让控制回路
+// 随温度抖动反复重定目标，规整时合并这类点。
+const (
+	// DefaultCurveMinTempGap 相邻点默认最小温度间隔(°C)
+	DefaultCurveMinTempGap = 3
+	// DefaultCurveMinRPMDelta 相邻点默认最小转速差(RPM)
+	DefaultCurveMinRPMDelta = 100
+)
+
+// NormalizeCurve 规整曲线：按温度升序排序，合并温度间隔或转速差小于
+// 最小值的相邻点。minTempGap/minRPMDelta 取<=0时使用默认值。
+// 首末点始终保留，保证曲线的温度范围和上限不变。
+func NormalizeCurve(curve []types.FanCurvePoint, minTempGap, minRPMDelta int) []types.FanCurvePoint {
+	if len(curve) <= 2 {
+		return curve
+	}
+	if minTempGap <= 0 {
+		minTempGap = DefaultCurveMinTempGap
+	}
+	if minRPMDelta <= 0 {
+		minRPMDelta = DefaultCurveMinRPMDelta
+	}
+
+	sorted := make([]types.FanCurvePoint, len(curve))
+	copy(sorted, curve)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Temperature < sorted[j].Temperature
+	})
+
+	normalized := []types.FanCurvePoint{sorted[0]}
+	for i := 1; i < len(sorted); i++ {
+		p := sorted[i]
+		last := normalized[len(normalized)-1]
+
+		rpmDelta := p.RPM - last.RPM
+		if rpmDelta < 0 {
+			rpmDelta = -rpmDelta
+		}
+		// 单位不同的相邻点转速不可比，只按温度间隔判断
+		sameUnit := curveUnit(p) == curveUnit(last)
+		tooClose := p.Temperature-last.Temperature < minTempGap ||
+			(sameUnit && rpmDelta < minRPMDelta)
+
+		if !tooClose {
+			normalized = append(normalized, p)
+			continue
+		}
+		if i == len(sorted)-1 {
+			// 末点定义曲线上限，被合并时用它替换前一个保留点而不是丢弃
+			if len(normalized) >= 2 {
+				normalized[len(normalized)-1] = p
+			} else {
+				normalized = append(normalized, p)
+			}
+		}
+	}
+	return normalized
+}
+
+// curveUnit 曲线点的生效单位，空取默认rpm
+func curveUnit(p types.FanCurvePoint) string {
+	if p.Unit == "" {
+		return types.CurveUnitRPM
+	}
+	return p.Unit
+}
+
+// CurveToPercent 将曲线换算为百分比表示，便于在最大转速不同的设备间共享。
+func CurveToPercent(curve []types.FanCurvePoint, maxRPM int) []types.FanCurvePoint {
+	if maxRPM <= 0 {
+		maxRPM = 4000
+	}
+
+	converted := make([]types.FanCurvePoint, len(curve))
+	for i, p := range curve {
+		if p.Unit == types.CurveUnitPercent {
+			converted[i] = p
+			continue
+		}
+		percent := int(float64(p.RPM)*100/float64(maxRPM) + 0.5)
+		if percent > 100 {
+			percent = 100
+		}
+		converted[i] = types.FanCurvePoint{
+			Temperature: p.Temperature,
+			RPM:         percent,
+			Unit:        types.CurveUnitPercent,
+		}
+	}
+	return converted
+}
+
 // CalculateTargetRPM 根据温度计算目标转速
 func CalculateTargetRPM(temperature int, fanCurve []types.FanCurvePoint) int {
 	if len(fanCurve) < 2 {
@@ -0,0 +1,239 @@
+package temperature
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// randomCurve 生成温度递增、转速为100整数倍的随机曲线
+func randomCurve(rng *rand.Rand, ascending bool) []types.FanCurvePoint {
+	points := 2 + rng.Intn(5)
+	curve := make([]types.FanCurvePoint, points)
+	temp := 30 + rng.Intn(10)
+	rpms := make([]int, points)
+	for i := range rpms {
+		rpms[i] = 1000 + rng.Intn(31)*100
+	}
+	if ascending {
+		// 转速随温度单调不减
+		for i := 1; i < points; i++ {
+			if rpms[i] < rpms[i-1] {
+				rpms[i] = rpms[i-1]
+			}
+		}
+	}
+	for i := range curve {
+		curve[i] = types.FanCurvePoint{Temperature: temp, RPM: rpms[i]}
+		temp += 2 + rng.Intn(15)
+	}
+	return curve
+}
+
+// curveRPMRange 曲线各点转速的最小/最大值
+func curveRPMRange(curve []types.FanCurvePoint) (minRPM, maxRPM int) {
+	minRPM, maxRPM = curve[0].RPM, curve[0].RPM
+	for _, p := range curve {
+		if p.RPM < minRPM {
+			minRPM = p.RPM
+		}
+		if p.RPM > maxRPM {
+			maxRPM = p.RPM
+		}
+	}
+	return
+}
+
+// TestCalculateTargetRPMBounds 性质：任意温度下目标转速落在曲线转速区间内，
+// 且边界外温度精确取端点转速
+func TestCalculateTargetRPMBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		curve := randomCurve(rng, false)
+		minRPM, maxRPM := curveRPMRange(curve)
+
+		first, last := curve[0], curve[len(curve)-1]
+		if got := CalculateTargetRPM(first.Temperature-10, curve); got != first.RPM {
+			t.Fatalf("低于首点温度应取首点转速: got %d want %d (curve %v)", got, first.RPM, curve)
+		}
+		if got := CalculateTargetRPM(last.Temperature+10, curve); got != last.RPM {
+			t.Fatalf("高于末点温度应取末点转速: got %d want %d (curve %v)", got, last.RPM, curve)
+		}
+
+		for temp := first.Temperature; temp <= last.Temperature; temp++ {
+			got := CalculateTargetRPM(temp, curve)
+			if got < minRPM || got > maxRPM {
+				t.Fatalf("温度 %d 目标转速 %d 超出曲线区间 [%d, %d] (curve %v)",
+					temp, got, minRPM, maxRPM, curve)
+			}
+			if got%100 != 0 {
+				t.Fatalf("温度 %d 目标转速 %d 不是100的整数倍", temp, got)
+			}
+		}
+	}
+}
+
+// TestCalculateTargetRPMMonotonic 性质：转速随温度单调不减的曲线，
+// 插值结果也应随温度单调不减
+func TestCalculateTargetRPMMonotonic(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 500; i++ {
+		curve := randomCurve(rng, true)
+		first, last := curve[0], curve[len(curve)-1]
+
+		prev := CalculateTargetRPM(first.Temperature, curve)
+		for temp := first.Temperature + 1; temp <= last.Temperature; temp++ {
+			got := CalculateTargetRPM(temp, curve)
+			if got < prev {
+				t.Fatalf("单调曲线下温度 %d 转速 %d 低于上一温度的 %d (curve %v)",
+					temp, got, prev, curve)
+			}
+			prev = got
+		}
+	}
+}
+
+// TestCalculateTargetRPMTooFewPoints 少于2个点的曲线无法插值
+func TestCalculateTargetRPMTooFewPoints(t *testing.T) {
+	if got := CalculateTargetRPM(50, nil); got != 0 {
+		t.Fatalf("空曲线应返回0, got %d", got)
+	}
+	single := []types.FanCurvePoint{{Temperature: 40, RPM: 2000}}
+	if got := CalculateTargetRPM(50, single); got != 0 {
+		t.Fatalf("单点曲线应返回0, got %d", got)
+	}
+}
+
+// TestResolveCurveIdentity 不含percent点的曲线应原样返回（同一切片）
+func TestResolveCurveIdentity(t *testing.T) {
+	curve := []types.FanCurvePoint{
+		{Temperature: 40, RPM: 1500},
+		{Temperature: 70, RPM: 3000, Unit: types.CurveUnitRPM},
+	}
+	resolved := ResolveCurve(curve, 4000)
+	if &resolved[0] != &curve[0] {
+		t.Fatal("纯rpm曲线应返回原切片，不应拷贝")
+	}
+}
+
+// TestCurveRoundTrip 性质：rpm曲线转percent再按相同最大转速还原，
+// 误差不超过一次percent取整(最大转速的0.5%)加一次百倍取整(50)
+func TestCurveRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		curve := randomCurve(rng, false)
+		maxRPM := 2000 + rng.Intn(21)*100
+
+		roundTripped := ResolveCurve(CurveToPercent(curve, maxRPM), maxRPM)
+		if len(roundTripped) != len(curve) {
+			t.Fatalf("往返后点数变化: %d -> %d", len(curve), len(roundTripped))
+		}
+		tolerance := maxRPM/200 + 50
+		for j := range curve {
+			if roundTripped[j].Temperature != curve[j].Temperature {
+				t.Fatalf("往返后温度点变化: %v -> %v", curve[j], roundTripped[j])
+			}
+			want := curve[j].RPM
+			if want > maxRPM {
+				// 超出设备上限的点换算percent时被压到100%，还原后封顶
+				want = maxRPM
+			}
+			diff := roundTripped[j].RPM - want
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > tolerance {
+				t.Fatalf("往返误差过大: 原 %d 还原 %d (maxRPM %d, 容差 %d)",
+					curve[j].RPM, roundTripped[j].RPM, maxRPM, tolerance)
+			}
+		}
+	}
+}
+
+// TestResolveCurveClampsPercent percent点超界时应压回[0,100]再换算
+func TestResolveCurveClampsPercent(t *testing.T) {
+	curve := []types.FanCurvePoint{
+		{Temperature: 30, RPM: -20, Unit: types.CurveUnitPercent},
+		{Temperature: 80, RPM: 150, Unit: types.CurveUnitPercent},
+	}
+	resolved := ResolveCurve(curve, 4000)
+	if resolved[0].RPM != 0 {
+		t.Fatalf("负百分比应换算为0, got %d", resolved[0].RPM)
+	}
+	if resolved[1].RPM != 4000 {
+		t.Fatalf("超100百分比应封顶为最大转速, got %d", resolved[1].RPM)
+	}
+}
+
+// TestGearMaxRPM 各挡位最大转速与未知挡位兜底
+func TestGearMaxRPM(t *testing.T) {
+	cases := map[string]int{
+		"静音": 1900,
+		"标准": 2760,
+		"强劲": 3300,
+		"超频": 4000,
+		"未知": 4000,
+		"":   4000,
+	}
+	for gear, want := range cases {
+		if got := GearMaxRPM(gear); got != want {
+			t.Fatalf("GearMaxRPM(%q) = %d, want %d", gear, got, want)
+		}
+	}
+}
+
+// TestNormalizeCurveMergesDensePoints 1°C一个点的密集曲线应被合并，
+// 首末点保留，相邻点满足最小温度间隔
+func TestNormalizeCurveMergesDensePoints(t *testing.T) {
+	curve := make([]types.FanCurvePoint, 0, 21)
+	for i := 0; i <= 20; i++ {
+		curve = append(curve, types.FanCurvePoint{Temperature: 40 + i, RPM: 1000 + i*150})
+	}
+	normalized := NormalizeCurve(curve, 5, 100)
+	if len(normalized) >= len(curve) {
+		t.Fatalf("密集曲线未被合并: %d -> %d", len(curve), len(normalized))
+	}
+	if normalized[0].Temperature != 40 {
+		t.Fatalf("首点被丢弃: %v", normalized[0])
+	}
+	if last := normalized[len(normalized)-1]; last.Temperature != 60 || last.RPM != 4000 {
+		t.Fatalf("末点未保留: %v", last)
+	}
+	for i := 1; i < len(normalized)-1; i++ {
+		if gap := normalized[i].Temperature - normalized[i-1].Temperature; gap < 5 {
+			t.Fatalf("相邻点温度间隔 %d 小于最小值5: %v -> %v", gap, normalized[i-1], normalized[i])
+		}
+	}
+}
+
+// TestNormalizeCurveDropsFlatPoints 转速差小于最小值的点应被合并
+func TestNormalizeCurveDropsFlatPoints(t *testing.T) {
+	curve := []types.FanCurvePoint{
+		{Temperature: 40, RPM: 2000},
+		{Temperature: 50, RPM: 2050}, // 转速差仅50
+		{Temperature: 60, RPM: 3000},
+	}
+	normalized := NormalizeCurve(curve, 3, 100)
+	if len(normalized) != 2 {
+		t.Fatalf("平坦点应被合并, got %v", normalized)
+	}
+}
+
+// TestNormalizeCurveSortsAndKeepsSparse 乱序输入被排序，已满足间隔的曲线不丢点
+func TestNormalizeCurveSortsAndKeepsSparse(t *testing.T) {
+	curve := []types.FanCurvePoint{
+		{Temperature: 70, RPM: 3500},
+		{Temperature: 40, RPM: 1500},
+		{Temperature: 55, RPM: 2500},
+	}
+	normalized := NormalizeCurve(curve, 3, 100)
+	if len(normalized) != 3 {
+		t.Fatalf("稀疏曲线不应丢点, got %v", normalized)
+	}
+	for i := 1; i < len(normalized); i++ {
+		if normalized[i].Temperature <= normalized[i-1].Temperature {
+			t.Fatalf("规整后温度未升序: %v", normalized)
+		}
+	}
+}
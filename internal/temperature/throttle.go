@@ -0,0 +1,38 @@
+package temperature
+
+// 持续降频判定参数
+const (
+	// throttleTempThreshold 无NVML降频标志时按温度兜底判定的阈值(°C)
+	throttleTempThreshold = 95
+	// throttleSustainCount 连续观测达到该次数后判定为持续降频
+	throttleSustainCount = 5
+	// throttleFanTolerance 判定风扇已满速时允许的转速误差(RPM)
+	throttleFanTolerance = 100
+)
+
+// ThrottleDetector 检测"风扇已满速仍持续热降频"的状况。
+// 此时散热垫已无能为力，应提示用户检查本体散热（清灰/换硅脂/降低负载）。
+type ThrottleDetector struct {
+	count  int
+	active bool
+}
+
+// Observe 输入一次采样，返回是否刚进入持续降频状态（仅在状态跃迁时返回true，
+// 避免每个采样周期重复告警）。
+func (d *ThrottleDetector) Observe(gpuThrottling bool, maxTemp, currentRPM, maxRPM int) bool {
+	throttling := gpuThrottling || maxTemp >= throttleTempThreshold
+	fanMaxed := maxRPM > 0 && currentRPM >= maxRPM-throttleFanTolerance
+
+	if !throttling || !fanMaxed {
+		d.count = 0
+		d.active = false
+		return false
+	}
+
+	d.count++
+	if d.count >= throttleSustainCount && !d.active {
+		d.active = true
+		return true
+	}
+	return false
+}
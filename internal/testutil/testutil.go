@@ -0,0 +1,269 @@
+// Package testutil 提供各包测试共用的测试替身：
+// 记录型Logger、可手动推进的假时钟、假RGB传输层与内存管道对，
+// 避免每个测试套件重复手写stub。仅供 _test.go 引用。
+package testutil
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/rgb"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// ============================================
+// 记录型Logger
+// ============================================
+
+// LogEntry 一条被记录的日志
+type LogEntry struct {
+	Level   string
+	Message string
+}
+
+// RecordingLogger 实现types.Logger，把日志记在内存里供断言
+type RecordingLogger struct {
+	mutex     sync.Mutex
+	entries   []LogEntry
+	debugMode bool
+	logDir    string
+}
+
+var _ types.Logger = (*RecordingLogger)(nil)
+
+// NewRecordingLogger 创建记录型Logger
+func NewRecordingLogger() *RecordingLogger {
+	return &RecordingLogger{}
+}
+
+func (l *RecordingLogger) record(level, format string, v ...any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, LogEntry{Level: level, Message: fmt.Sprintf(format, v...)})
+}
+
+func (l *RecordingLogger) Info(format string, v ...any)  { l.record("INFO", format, v...) }
+func (l *RecordingLogger) Error(format string, v ...any) { l.record("ERROR", format, v...) }
+func (l *RecordingLogger) Warn(format string, v ...any)  { l.record("WARN", format, v...) }
+func (l *RecordingLogger) Debug(format string, v ...any) { l.record("DEBUG", format, v...) }
+func (l *RecordingLogger) Close()                        {}
+func (l *RecordingLogger) CleanOldLogs()                 {}
+
+func (l *RecordingLogger) SetDebugMode(enabled bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.debugMode = enabled
+}
+
+func (l *RecordingLogger) GetLogDir() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.logDir
+}
+
+// SetLogDir 设置GetLogDir的返回值（部分被测代码据此决定文件落盘位置）
+func (l *RecordingLogger) SetLogDir(dir string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.logDir = dir
+}
+
+// Entries 返回已记录日志的拷贝
+func (l *RecordingLogger) Entries() []LogEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	out := make([]LogEntry, len(l.entries))
+	copy(out, l.entries)
+	return out
+}
+
+// Contains 判断是否记录过指定级别且包含子串的日志，level为空时匹配任意级别
+func (l *RecordingLogger) Contains(level, substr string) bool {
+	for _, e := range l.Entries() {
+		if (level == "" || e.Level == level) && strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset 清空已记录的日志
+func (l *RecordingLogger) Reset() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = nil
+}
+
+// ============================================
+// 假时钟
+// ============================================
+
+// FakeClock 可手动推进的时钟，让时间驱动的逻辑可确定性测试。
+// After返回的通道在Advance越过到期时刻时触发，Sleep阻塞到被推进越过为止。
+type FakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+var _ types.Clock = (*FakeClock)(nil)
+
+// NewFakeClock 创建假时钟，起始时刻固定以保证测试可复现
+func NewFakeClock() *FakeClock {
+	return &FakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// Now 返回当前假时刻
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// After 返回在假时刻到达 now+d 时触发的通道
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	w := &fakeWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- c.now
+		return w.ch
+	}
+	c.waiters = append(c.waiters, w)
+	return w.ch
+}
+
+// Sleep 阻塞直到假时刻被推进越过 now+d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// BlockUntil 阻塞直到至少有n个After/Sleep等待者注册，
+// 用于在Advance前确认被测goroutine已经开始等待，消除启动时序竞争
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mutex.Lock()
+		count := len(c.waiters)
+		c.mutex.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Advance 将假时刻前进d，并唤醒所有到期的After/Sleep等待者
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	remaining := c.waiters[:0]
+	var fired []*fakeWaiter
+	for _, w := range c.waiters {
+		if !w.at.After(now) {
+			fired = append(fired, w)
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	c.waiters = remaining
+	c.mutex.Unlock()
+
+	for _, w := range fired {
+		w.ch <- now
+	}
+}
+
+// ============================================
+// 假RGB传输层
+// ============================================
+
+// FakeTransport 实现rgb.Transport，记录下发的数据包并可编排ACK结果
+type FakeTransport struct {
+	mutex       sync.Mutex
+	packets     [][]byte
+	ackCount    int
+	ackFailures int   // 前N次ACK请求返回失败
+	writeErr    error // WritePacket固定返回的错误
+}
+
+var _ rgb.Transport = (*FakeTransport)(nil)
+
+// NewFakeTransport 创建假传输层，默认所有写入成功、所有ACK通过
+func NewFakeTransport() *FakeTransport {
+	return &FakeTransport{}
+}
+
+func (t *FakeTransport) WritePacket(packet []byte) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.writeErr != nil {
+		return t.writeErr
+	}
+	buf := make([]byte, len(packet))
+	copy(buf, packet)
+	t.packets = append(t.packets, buf)
+	return nil
+}
+
+func (t *FakeTransport) WritePacketAndWaitACK(cmdID byte, packet []byte, timeout time.Duration) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.ackCount++
+	buf := make([]byte, len(packet))
+	copy(buf, packet)
+	t.packets = append(t.packets, buf)
+	if t.ackFailures > 0 {
+		t.ackFailures--
+		return false
+	}
+	return true
+}
+
+// FailACKs 令接下来n次ACK请求返回失败
+func (t *FakeTransport) FailACKs(n int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.ackFailures = n
+}
+
+// SetWriteError 令后续WritePacket固定返回err，传nil恢复
+func (t *FakeTransport) SetWriteError(err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.writeErr = err
+}
+
+// Packets 返回已记录数据包的拷贝（含等待ACK的包）
+func (t *FakeTransport) Packets() [][]byte {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	out := make([][]byte, len(t.packets))
+	copy(out, t.packets)
+	return out
+}
+
+// ACKCount 返回WritePacketAndWaitACK被调用的次数
+func (t *FakeTransport) ACKCount() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.ackCount
+}
+
+// ============================================
+// 内存管道
+// ============================================
+
+// Pipe 返回一对内存连接，模拟IPC的客户端/服务端两端，
+// 可直接配合bufio按行读写换行分隔的JSON消息。
+func Pipe() (client, server net.Conn) {
+	return net.Pipe()
+}
@@ -15,18 +15,25 @@ import (
 // Manager 系统托盘管理器
 type Manager struct {
 	logger       types.Logger
+	clock        types.Clock
 	initialized  int32 // atomic: 0=未初始化, 1=已初始化
 	readyState   int32 // atomic: 0=未就绪, 1=就绪
 	mutex        sync.Mutex
 	uiMutex      sync.Mutex
 	done         chan struct{} // 关闭此通道以通知所有 goroutine 退出
 	iconData     []byte
+	iconMutex    sync.Mutex // 保护图标套与主题选择
+	icons        IconSet    // 标准状态图标套
+	iconsCB      IconSet    // 色盲友好状态图标套
+	iconTheme    string     // default / colorblind
+	currentIcon  string     // 当前已应用的图标状态键，避免重复SetIcon
 	menuItems    *MenuItems
 	onShowWindow func()
 	onQuit       func()
 	onQuitAll    func()
 	onStopCore   func()
-	onToggleAuto func() bool
+	onToggleAuto func()
+	onMaxBoost   func()
 	getStatus    func() Status
 	menuQuitGUI  *systray.MenuItem
 	menuStopCore *systray.MenuItem
@@ -45,6 +52,7 @@ type MenuItems struct {
 	GPUTemperature *systray.MenuItem
 	FanSpeed       *systray.MenuItem
 	AutoControl    *systray.MenuItem
+	MaxBoost       *systray.MenuItem
 }
 
 // Status 状态信息
@@ -54,24 +62,101 @@ type Status struct {
 	GPUTemp          int
 	CurrentRPM       uint16
 	AutoControlState bool
+	BoostRemaining   int  // 狂暴模式剩余秒数，0表示未开启
+	Alert            bool // 关键告警状态(如持续热降频)
 }
 
+// IconSet 各状态的托盘图标，未提供的状态回退到基础图标
+type IconSet struct {
+	Connected    []byte
+	Disconnected []byte
+	Alert        []byte
+	Boost        []byte
+}
+
+// 图标主题
+const (
+	IconThemeDefault    = "default"
+	IconThemeColorblind = "colorblind"
+)
+
 // NewManager 创建新的托盘管理器
 func NewManager(logger types.Logger, iconData []byte) *Manager {
 	return &Manager{
 		logger:   logger,
+		clock:    types.SystemClock{},
 		done:     make(chan struct{}),
 		iconData: iconData,
 	}
 }
 
+// SetClock 替换时间源，仅供测试注入假时钟
+func (m *Manager) SetClock(clock types.Clock) {
+	m.clock = clock
+}
+
+// SetIconSets 设置标准与色盲友好两套状态图标，应在Init前调用
+func (m *Manager) SetIconSets(standard, colorblind IconSet) {
+	m.iconMutex.Lock()
+	defer m.iconMutex.Unlock()
+	m.icons = standard
+	m.iconsCB = colorblind
+}
+
+// SetIconTheme 切换图标主题(default/colorblind)，下个状态刷新周期生效
+func (m *Manager) SetIconTheme(theme string) {
+	m.iconMutex.Lock()
+	defer m.iconMutex.Unlock()
+	if theme != m.iconTheme {
+		m.iconTheme = theme
+		m.currentIcon = "" // 强制下次刷新重新应用图标
+	}
+}
+
+// selectIcon 依据状态选出应显示的图标，返回状态键与图标数据
+func (m *Manager) selectIcon(status Status) (string, []byte) {
+	m.iconMutex.Lock()
+	set := m.icons
+	if m.iconTheme == IconThemeColorblind {
+		set = m.iconsCB
+	}
+	m.iconMutex.Unlock()
+
+	key, data := "connected", set.Connected
+	switch {
+	case !status.Connected:
+		key, data = "disconnected", set.Disconnected
+	case status.Alert:
+		key, data = "alert", set.Alert
+	case status.BoostRemaining > 0:
+		key, data = "boost", set.Boost
+	}
+	if len(data) == 0 {
+		data = m.iconData
+	}
+	return key, data
+}
+
+// applyStateIcon 按状态切换托盘图标，仅在状态键变化时调用SetIcon。
+// 调用方须持有uiMutex。
+func (m *Manager) applyStateIcon(status Status) {
+	key, data := m.selectIcon(status)
+	if key == m.currentIcon || len(data) == 0 {
+		return
+	}
+	systray.SetIcon(data)
+	m.currentIcon = key
+	m.logDebug("托盘图标已切换到状态: %s", key)
+}
+
 // SetCallbacks 设置回调函数
 func (m *Manager) SetCallbacks(
 	onShow func(),
 	onQuit func(),
 	onQuitAll func(),
 	onStopCore func(),
-	onToggleAuto func() bool,
+	onToggleAuto func(),
+	onMaxBoost func(),
 	getStatus func() Status,
 ) {
 	m.onShowWindow = onShow
@@ -79,6 +164,7 @@ func (m *Manager) SetCallbacks(
 	m.onQuitAll = onQuitAll
 	m.onStopCore = onStopCore
 	m.onToggleAuto = onToggleAuto
+	m.onMaxBoost = onMaxBoost
 	m.getStatus = getStatus
 }
 
@@ -148,7 +234,7 @@ func (m *Manager) onTrayReady() {
 	m.menuItems = menuItems
 
 	atomic.StoreInt32(&m.readyState, 1)
-	atomic.StoreInt64(&m.lastIconRefresh, time.Now().Unix())
+	atomic.StoreInt64(&m.lastIconRefresh, m.clock.Now().Unix())
 	atomic.StoreInt32(&m.consecutiveFails, 0)
 	m.logInfo("系统托盘初始化完成")
 
@@ -212,6 +298,7 @@ func (m *Manager) createMenu() (items *MenuItems, err error) {
 		autoControlEnabled = m.getStatus().AutoControlState
 	}
 	items.AutoControl = systray.AddMenuItemCheckbox("智能变频", "启用/禁用智能变频", autoControlEnabled)
+	items.MaxBoost = systray.AddMenuItem("一键狂暴", "限时切到最高挡位，到期自动恢复")
 
 	systray.AddSeparator()
 
@@ -229,7 +316,7 @@ func (m *Manager) handleMenuEvents() {
 		}
 	}()
 
-	if m.menuItems == nil || m.menuItems.Show == nil || m.menuItems.AutoControl == nil {
+	if m.menuItems == nil || m.menuItems.Show == nil || m.menuItems.AutoControl == nil || m.menuItems.MaxBoost == nil {
 		m.logError("托盘菜单未正确初始化，无法处理菜单事件")
 		return
 	}
@@ -244,14 +331,15 @@ func (m *Manager) handleMenuEvents() {
 		case <-m.menuItems.AutoControl.ClickedCh:
 			m.logDebug("托盘菜单: 切换智能变频状态")
 			if m.onToggleAuto != nil {
-				newState := m.onToggleAuto()
-				m.uiMutex.Lock()
-				if newState {
-					m.menuItems.AutoControl.Check()
-				} else {
-					m.menuItems.AutoControl.Uncheck()
-				}
-				m.uiMutex.Unlock()
+				// 不做乐观更新：勾选状态由updateMenuStatus依据
+				// 配置事件流回传的真实状态统一刷新，避免失败时UI与实际不一致。
+				m.onToggleAuto()
+			}
+		case <-m.menuItems.MaxBoost.ClickedCh:
+			m.logDebug("托盘菜单: 一键狂暴")
+			if m.onMaxBoost != nil {
+				// 菜单文本与倒计时由updateMenuStatus依据核心回传的状态刷新
+				m.onMaxBoost()
 			}
 		case <-m.menuQuitGUI.ClickedCh:
 			m.logDebug("托盘菜单: 退出控制台")
@@ -270,7 +358,7 @@ func (m *Manager) handleMenuEvents() {
 
 				// 5秒后恢复菜单项
 				go func() {
-					time.Sleep(5 * time.Second)
+					m.clock.Sleep(5 * time.Second)
 					m.uiMutex.Lock()
 					m.menuStopCore.SetTitle("关闭核心")
 					m.menuStopCore.Enable()
@@ -289,7 +377,7 @@ func (m *Manager) handleMenuEvents() {
 
 				// 5秒后恢复菜单项
 				go func() {
-					time.Sleep(5 * time.Second)
+					m.clock.Sleep(5 * time.Second)
 					m.uiMutex.Lock()
 					m.menuQuitAll.SetTitle("重启核心")
 					m.menuQuitAll.Enable()
@@ -309,12 +397,9 @@ func (m *Manager) updateMenuStatus() {
 		}
 	}()
 
-	ticker := time.NewTicker(3 * time.Second)
-	defer ticker.Stop()
-
 	for {
 		select {
-		case <-ticker.C:
+		case <-m.clock.After(3 * time.Second):
 			if atomic.LoadInt32(&m.readyState) == 0 || atomic.LoadInt32(&m.initialized) == 0 {
 				continue
 			}
@@ -364,8 +449,20 @@ func (m *Manager) updateMenuStatus() {
 					m.menuItems.AutoControl.Uncheck()
 				}
 
+				if status.BoostRemaining > 0 {
+					m.menuItems.MaxBoost.SetTitle(fmt.Sprintf("一键狂暴（剩余 %d 秒，点击结束）", status.BoostRemaining))
+				} else {
+					m.menuItems.MaxBoost.SetTitle("一键狂暴")
+				}
+
 				if status.Connected {
-					if status.AutoControlState {
+					if status.BoostRemaining > 0 {
+						tooltipText := fmt.Sprintf("BS2PRO 控制器 - 狂暴模式 剩余%d秒", status.BoostRemaining)
+						if status.CurrentRPM > 0 {
+							tooltipText += fmt.Sprintf("\n风扇: %d RPM", status.CurrentRPM)
+						}
+						systray.SetTooltip(tooltipText)
+					} else if status.AutoControlState {
 						tooltipText := fmt.Sprintf("BS2PRO 控制器 - 智能变频中\nCPU: %d°C GPU: %d°C", status.CPUTemp, status.GPUTemp)
 						if status.CurrentRPM > 0 {
 							tooltipText += fmt.Sprintf("\n风扇: %d RPM", status.CurrentRPM)
@@ -381,6 +478,8 @@ func (m *Manager) updateMenuStatus() {
 				} else {
 					systray.SetTooltip("BS2PRO 控制器 - 设备未连接")
 				}
+
+				m.applyStateIcon(status)
 			}()
 		case <-m.done:
 			return
@@ -401,12 +500,9 @@ func (m *Manager) startIconHealthMonitor() {
 		}
 	}()
 
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
 	for {
 		select {
-		case <-ticker.C:
+		case <-m.clock.After(30 * time.Second):
 			if atomic.LoadInt32(&m.readyState) == 0 || atomic.LoadInt32(&m.initialized) == 0 {
 				continue
 			}
@@ -434,11 +530,18 @@ func (m *Manager) refreshTrayIcon() {
 		return
 	}
 
-	systray.SetIcon(m.iconData)
+	// 按当前状态重设图标，避免健康刷新把状态图标打回基础图标
+	icon := m.iconData
+	if m.getStatus != nil {
+		if _, data := m.selectIcon(m.getStatus()); len(data) > 0 {
+			icon = data
+		}
+	}
+	systray.SetIcon(icon)
 	systray.SetTooltip("BS2PRO 风扇控制器 - 运行中")
 
 	atomic.StoreInt32(&m.consecutiveFails, 0)
-	atomic.StoreInt64(&m.lastIconRefresh, time.Now().Unix())
+	atomic.StoreInt64(&m.lastIconRefresh, m.clock.Now().Unix())
 	m.logDebug("托盘图标已刷新")
 }
 
@@ -483,7 +586,7 @@ func (m *Manager) CheckHealth() {
 	}
 
 	lastRefresh := atomic.LoadInt64(&m.lastIconRefresh)
-	if lastRefresh > 0 && time.Now().Unix()-lastRefresh > 90 {
+	if lastRefresh > 0 && m.clock.Now().Unix()-lastRefresh > 90 {
 		m.logInfo("检测到托盘图标长时间未刷新，尝试刷新")
 		m.refreshTrayIcon()
 	}
@@ -0,0 +1,109 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/testutil"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// TestDefaultReconnectPolicyLadder 默认策略应与历史硬编码梯度一致
+func TestDefaultReconnectPolicyLadder(t *testing.T) {
+	p := types.DefaultReconnectPolicy()
+	want := []time.Duration{2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second}
+	for i, w := range want {
+		delay, ok := p.NextDelay(i + 1)
+		if !ok || delay != w {
+			t.Fatalf("第%d次重连延迟 = %v (ok=%v), want %v", i+1, delay, ok, w)
+		}
+	}
+	if _, ok := p.NextDelay(5); ok {
+		t.Fatal("超过MaxAttempts后仍返回延迟")
+	}
+	if _, ok := p.NextDelay(0); ok {
+		t.Fatal("attempt=0应返回ok=false")
+	}
+}
+
+// TestReconnectPolicyDelayCapping 尝试次数超出梯度长度时沿用最后一项
+func TestReconnectPolicyDelayCapping(t *testing.T) {
+	p := types.ReconnectPolicy{RetryDelaysSec: []int{1, 3}, MaxAttempts: 5}.Normalized()
+	for attempt, wantSec := range map[int]int{1: 1, 2: 3, 3: 3, 5: 3} {
+		delay, ok := p.NextDelay(attempt)
+		if !ok || delay != time.Duration(wantSec)*time.Second {
+			t.Fatalf("第%d次重连延迟 = %v (ok=%v), want %ds", attempt, delay, ok, wantSec)
+		}
+	}
+}
+
+// TestReconnectPolicyNormalized 非法字段应回填默认值
+func TestReconnectPolicyNormalized(t *testing.T) {
+	p := types.ReconnectPolicy{
+		RetryDelaysSec:       []int{-1, 0, 4},
+		MaxAttempts:          0,
+		HealthIntervalSec:    20,
+		HealthMaxIntervalSec: 10, // 小于基础间隔
+	}.Normalized()
+
+	if len(p.RetryDelaysSec) != 1 || p.RetryDelaysSec[0] != 4 {
+		t.Fatalf("非正延迟应被剔除: %v", p.RetryDelaysSec)
+	}
+	if p.MaxAttempts != 4 {
+		t.Fatalf("MaxAttempts应回填默认4, got %d", p.MaxAttempts)
+	}
+	if p.HealthMaxInterval() != p.HealthInterval() {
+		t.Fatalf("最大间隔不应小于基础间隔: %v < %v", p.HealthMaxInterval(), p.HealthInterval())
+	}
+
+	empty := types.ReconnectPolicy{}.Normalized()
+	def := types.DefaultReconnectPolicy()
+	if empty.MaxAttempts != def.MaxAttempts || empty.HealthIntervalSec != def.HealthIntervalSec {
+		t.Fatalf("零值策略应等于默认策略: %+v", empty)
+	}
+}
+
+// TestReconnectLadderWithFakeClock 用假时钟驱动整条重连梯度，
+// 验证每次等待在对应时刻精确到期、时序可确定性推进
+func TestReconnectLadderWithFakeClock(t *testing.T) {
+	p := types.DefaultReconnectPolicy()
+	clock := testutil.NewFakeClock()
+
+	fired := make(chan int)
+	go func() {
+		for attempt := 1; ; attempt++ {
+			delay, ok := p.NextDelay(attempt)
+			if !ok {
+				close(fired)
+				return
+			}
+			clock.Sleep(delay)
+			fired <- attempt
+		}
+	}()
+
+	for i, sec := range []int{2, 5, 10, 30} {
+		// 等待本次Sleep注册完成，再推进到差1秒处，不应触发
+		clock.BlockUntil(1)
+		clock.Advance(time.Duration(sec-1) * time.Second)
+		select {
+		case got := <-fired:
+			t.Fatalf("第%d次重连提前触发(提前1秒收到attempt=%d)", i+1, got)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		clock.Advance(1 * time.Second)
+		select {
+		case got := <-fired:
+			if got != i+1 {
+				t.Fatalf("重连顺序错乱: got attempt=%d, want %d", got, i+1)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("第%d次重连未在预期时刻触发", i+1)
+		}
+	}
+
+	if _, open := <-fired; open {
+		t.Fatal("梯度走完后goroutine应结束")
+	}
+}
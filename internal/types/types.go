@@ -1,10 +1,19 @@
 // Package types 定义了 BS2PRO 控制器应用中使用的所有共享类型
 package types
 
+import "time"
+
+// 风扇曲线点单位
+const (
+	CurveUnitRPM     = "rpm"     // RPM字段为绝对转速(默认)
+	CurveUnitPercent = "percent" // RPM字段为设备最大转速的百分比(0-100)
+)
+
 // FanCurvePoint 风扇曲线点
 type FanCurvePoint struct {
-	Temperature int `json:"temperature"` // 温度 °C
-	RPM         int `json:"rpm"`         // 转速 RPM
+	Temperature int    `json:"temperature"`    // 温度 °C
+	RPM         int    `json:"rpm"`            // 转速 RPM 或百分比(取决于Unit)
+	Unit        string `json:"unit,omitempty"` // 单位: rpm(默认)/percent
 }
 
 // FanData 风扇数据结构
@@ -21,6 +30,12 @@ type FanData struct {
 	MaxGear      string `json:"maxGear"`
 	SetGear      string `json:"setGear"`
 	WorkMode     string `json:"workMode"`
+
+	// 电机负载遥测，新固件在状态报文的保留字节中携带。
+	// 旧固件报文较短或字节恒为0，此时字段为0表示不可用；
+	// 同转速下电流持续升高提示轴承阻力增大（老化/积灰）。
+	MotorCurrentMA uint16 `json:"motorCurrentMa,omitempty"` // 电机电流(毫安)
+	MotorVoltageMV uint16 `json:"motorVoltageMv,omitempty"` // 电机电压(毫伏)
 }
 
 // GearCommand 挡位命令结构
@@ -32,12 +47,27 @@ type GearCommand struct {
 
 // TemperatureData 温度数据
 type TemperatureData struct {
-	CPUTemp    int    `json:"cpuTemp"`       // CPU温度
-	GPUTemp    int    `json:"gpuTemp"`       // GPU温度
-	MaxTemp    int    `json:"maxTemp"`       // 最高温度
-	UpdateTime int64  `json:"updateTime"`    // 更新时间戳
-	BridgeOk   bool   `json:"bridgeOk"`      // 桥接程序是否正常
-	BridgeMsg  string `json:"bridgeMessage"` // 桥接故障提示
+	CPUTemp     int    `json:"cpuTemp"`       // CPU温度
+	GPUTemp     int    `json:"gpuTemp"`       // GPU温度
+	MaxTemp     int    `json:"maxTemp"`       // 最高温度
+	GPUThrottle bool   `json:"gpuThrottle"`   // GPU是否处于热降频状态
+	UpdateTime  int64  `json:"updateTime"`    // 更新时间戳
+	BridgeOk    bool   `json:"bridgeOk"`      // 桥接程序是否正常
+	BridgeMsg   string `json:"bridgeMessage"` // 桥接故障提示
+}
+
+// ControlDecision 智能变频单次调速决策记录，用于解释"风扇为什么变速"
+type ControlDecision struct {
+	Time        int64  `json:"time"`        // 决策时间戳(Unix秒)
+	CPUTemp     int    `json:"cpuTemp"`     // 当次CPU温度
+	GPUTemp     int    `json:"gpuTemp"`     // 当次GPU温度
+	AvgTemp     int    `json:"avgTemp"`     // 采样平均后参与计算的温度
+	SegmentLow  int    `json:"segmentLow"`  // 命中曲线段的低温端(°C)
+	SegmentHigh int    `json:"segmentHigh"` // 命中曲线段的高温端(°C)
+	PrevRPM     int    `json:"prevRpm"`     // 上一次的目标转速(0表示首次)
+	TargetRPM   int    `json:"targetRpm"`   // 本次计算出的目标转速
+	CurveGen    int64  `json:"curveGen"`    // 决策时采用的曲线代数(每次曲线替换递增)
+	Reason      string `json:"reason"`      // 决策说明
 }
 
 // BridgeTemperatureData 桥接程序返回的温度数据
@@ -63,28 +93,186 @@ type RGBConfig struct {
 	Colors     []RGBColorConfig `json:"colors"`
 	Speed      string           `json:"speed"`
 	Brightness int              `json:"brightness"`
+	// SmartThresholds 智能温控模式的档位温度阈值(°C)，依次为进入2/3/4档的下限。
+	// 为空时使用固件默认值 60/85/90。
+	SmartThresholds []int `json:"smartThresholds,omitempty"`
+	// CrossfadeMs 灯效切换渐变时长(毫秒)：0取默认400ms，负值关闭渐变
+	CrossfadeMs int `json:"crossfadeMs,omitempty"`
+}
+
+// DefaultSmartThresholds 智能温控默认档位阈值
+var DefaultSmartThresholds = []int{60, 85, 90}
+
+// OBSConfig OBS直播集成配置(obs-websocket v5)
+type OBSConfig struct {
+	Enabled      bool              `json:"enabled"`      // 集成开关
+	Host         string            `json:"host"`         // obs-websocket地址(host:port)
+	Password     string            `json:"password"`     // obs-websocket密码(未开启鉴权时留空)
+	SceneProfile map[string]string `json:"sceneProfile"` // 场景名->挡位映射，进入场景时应用、离开时恢复
+}
+
+// SmartLevelForTemp 根据温度和配置的阈值计算智能温控档位(1-4)
+func (c *RGBConfig) SmartLevelForTemp(temp int) byte {
+	thresholds := DefaultSmartThresholds
+	if c != nil && len(c.SmartThresholds) == 3 {
+		thresholds = c.SmartThresholds
+	}
+	var level byte = 1
+	for _, t := range thresholds {
+		if temp >= t {
+			level++
+		}
+	}
+	return level
 }
 
 // AppConfig 应用配置
 type AppConfig struct {
-	AutoControl             bool            `json:"autoControl"`             // 智能变频开关
-	FanCurve                []FanCurvePoint `json:"fanCurve"`                // 风扇曲线
-	GearLight               bool            `json:"gearLight"`               // 挡位灯
-	PowerOnStart            bool            `json:"powerOnStart"`            // 通电自启动
-	WindowsAutoStart        bool            `json:"windowsAutoStart"`        // Windows开机自启动
-	SmartStartStop          string          `json:"smartStartStop"`          // 智能启停
-	Brightness              int             `json:"brightness"`              // 亮度
-	TempUpdateRate          int             `json:"tempUpdateRate"`          // 温度更新频率(秒)
-	TempSampleCount         int             `json:"tempSampleCount"`         // 温度采样次数(用于平均)
-	ConfigPath              string          `json:"configPath"`              // 配置文件路径
-	ManualGear              string          `json:"manualGear"`              // 手动挡位设置
-	ManualLevel             string          `json:"manualLevel"`             // 手动挡位级别(低中高)
-	DebugMode               bool            `json:"debugMode"`               // 调试模式
-	GuiMonitoring           bool            `json:"guiMonitoring"`           // GUI监控开关
-	CustomSpeedEnabled      bool            `json:"customSpeedEnabled"`      // 自定义转速开关
-	CustomSpeedRPM          int             `json:"customSpeedRPM"`          // 自定义转速值(无上下限)
-	IgnoreDeviceOnReconnect bool            `json:"ignoreDeviceOnReconnect"` // 断连后忽略设备状态(保持APP配置)
-	RGBConfig               *RGBConfig      `json:"rgbConfig"`               // RGB灯效配置
+	AutoControl              bool            `json:"autoControl"`                 // 智能变频开关
+	FanCurve                 []FanCurvePoint `json:"fanCurve"`                    // 风扇曲线
+	CurveMinTempGap          int             `json:"curveMinTempGap"`             // 曲线相邻点最小温度间隔(°C)，0取默认
+	CurveMinRPMDelta         int             `json:"curveMinRpmDelta"`            // 曲线相邻点最小转速差(RPM)，0取默认
+	GearLight                bool            `json:"gearLight"`                   // 挡位灯
+	PowerOnStart             bool            `json:"powerOnStart"`                // 通电自启动
+	WindowsAutoStart         bool            `json:"windowsAutoStart"`            // Windows开机自启动
+	SmartStartStop           string          `json:"smartStartStop"`              // 智能启停
+	Brightness               int             `json:"brightness"`                  // 亮度
+	TempUpdateRate           int             `json:"tempUpdateRate"`              // 温度更新频率(秒)
+	AdaptiveSampling         bool            `json:"adaptiveSampling"`            // 自适应采样开关(升温快时加快采样)
+	TempUpdateRateMin        int             `json:"tempUpdateRateMin"`           // 自适应采样最短间隔(秒)
+	TempUpdateRateMax        int             `json:"tempUpdateRateMax"`           // 自适应采样最长间隔(秒)
+	TempSampleCount          int             `json:"tempSampleCount"`             // 温度采样次数(用于平均)
+	ConfigPath               string          `json:"configPath"`                  // 配置文件路径
+	ManualGear               string          `json:"manualGear"`                  // 手动挡位设置
+	ManualLevel              string          `json:"manualLevel"`                 // 手动挡位级别(低中高)
+	DebugMode                bool            `json:"debugMode"`                   // 调试模式
+	GuiMonitoring            bool            `json:"guiMonitoring"`               // GUI监控开关
+	CustomSpeedEnabled       bool            `json:"customSpeedEnabled"`          // 自定义转速开关
+	CustomSpeedRPM           int             `json:"customSpeedRPM"`              // 自定义转速值(无上下限)
+	IgnoreDeviceOnReconnect  bool            `json:"ignoreDeviceOnReconnect"`     // 断连后忽略设备状态(保持APP配置)
+	DeviceInterfacePath      string          `json:"deviceInterfacePath"`         // 首选HID接口路径(空则自动匹配，探测成功后持久化)
+	RGBConfig                *RGBConfig      `json:"rgbConfig"`                   // RGB灯效配置
+	GuiHangPolicy            string          `json:"guiHangPolicy"`               // GUI无响应处置策略: log(仅记录)/relaunch(强杀并重启)
+	GuiHangTimeoutSec        int             `json:"guiHangTimeoutSec"`           // GUI心跳超时判定阈值(秒)
+	SharedMemoryTelemetry    bool            `json:"sharedMemoryTelemetry"`       // 共享内存遥测通道(供悬浮窗/OSD高频轮询)
+	OverlayOSD               bool            `json:"overlayOSD"`                  // RTSS游戏内OSD发布开关
+	StatusServerEnabled      bool            `json:"statusServerEnabled"`         // 本地HTTP状态端点开关(供OBS浏览器源等)
+	StatusServerPort         int             `json:"statusServerPort"`            // 状态端点监听端口(仅回环)
+	StatusServerRateSec      int             `json:"statusServerRateSec"`         // 状态端点缓存刷新间隔(秒)
+	RemoteAccessAddr         string          `json:"remoteAccessAddr,omitempty"`  // IPC远程控制监听地址(host:port)，空则不开启
+	RemoteAccessToken        string          `json:"remoteAccessToken,omitempty"` // IPC远程控制访问令牌，远程连接握手时校验，空则拒绝开启监听
+	OBSConfig                *OBSConfig      `json:"obsConfig"`                   // OBS直播集成配置
+	MaxBoostDurationSec      int             `json:"maxBoostDurationSec"`         // 一键狂暴默认持续时长(秒)
+	ThermalAlertBreakthrough bool            `json:"thermalAlertBreakthrough"`    // 关键热告警穿透专注助手(Focus Assist)
+	TrayIconTheme            string          `json:"trayIconTheme"`               // 托盘图标主题: default/colorblind(色盲友好)
+	LogLanguage              string          `json:"logLanguage"`                 // 日志输出语言: zh-CN(默认)/en-US
+	FirmwareWatchdog         bool            `json:"firmwareWatchdog"`            // 固件主机看门狗: 核心失联时固件回退板载挡位控制(需固件支持)
+
+	// ReconnectPolicy 重连/健康检查策略，nil时使用默认策略
+	ReconnectPolicy *ReconnectPolicy `json:"reconnectPolicy,omitempty"`
+
+	// FeatureFlags 功能开关的用户显式覆盖，合并时优先于更新通道下发的默认值。
+	// 仅记录用户动过的开关，未记录的开关跟随通道默认
+	FeatureFlags map[string]bool `json:"featureFlags,omitempty"`
+
+	// InstallationID 本机安装的稳定唯一标识(UUID v4格式)，首次启动时生成后不再变化。
+	// 随诊断信息与支持包带出，支持侧据此关联同一台机器的多次报障
+	InstallationID string `json:"installationId,omitempty"`
+
+	// PairedDevices 连接过的设备记录（按序列号区分），每次连接成功时更新。
+	// 配置存放在ProgramData下，记录可在GUI重装后保留
+	PairedDevices []PairedDevice `json:"pairedDevices,omitempty"`
+}
+
+// PairedDevice 一台连接过的设备的配对记录
+type PairedDevice struct {
+	Serial          string `json:"serial"`          // 设备序列号
+	Model           string `json:"model"`           // 型号(BS2PRO/BS2)
+	FirstPairedUnix int64  `json:"firstPairedUnix"` // 首次连接时间戳
+	LastSeenUnix    int64  `json:"lastSeenUnix"`    // 最近一次连接时间戳
+	TimesConnected  int    `json:"timesConnected"`  // 累计连接成功次数
+}
+
+// ReconnectPolicyOrDefault 返回配置的重连策略（回填默认值后），未配置时返回默认策略
+func (c AppConfig) ReconnectPolicyOrDefault() ReconnectPolicy {
+	if c.ReconnectPolicy != nil {
+		return c.ReconnectPolicy.Normalized()
+	}
+	return DefaultReconnectPolicy()
+}
+
+// ReconnectPolicy 设备重连梯度与健康检查策略。
+// 支持按用户情况单独调整（如设备枚举慢的机器拉长梯度），
+// 零值/非法字段由Normalized回填为默认值。
+type ReconnectPolicy struct {
+	RetryDelaysSec       []int `json:"retryDelaysSec,omitempty"`       // 各次重连前的等待秒数，次数超出时沿用最后一项
+	MaxAttempts          int   `json:"maxAttempts,omitempty"`          // 一轮重连调度的最大尝试次数
+	HealthIntervalSec    int   `json:"healthIntervalSec,omitempty"`    // 健康检查基础间隔(秒)
+	HealthMaxIntervalSec int   `json:"healthMaxIntervalSec,omitempty"` // 健康检查指数退避的最大间隔(秒)
+}
+
+// DefaultReconnectPolicy 与历史硬编码行为一致的默认策略
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		RetryDelaysSec:       []int{2, 5, 10, 30},
+		MaxAttempts:          4,
+		HealthIntervalSec:    5,
+		HealthMaxIntervalSec: 30,
+	}
+}
+
+// Normalized 返回回填默认值并剔除非法取值后的拷贝
+func (p ReconnectPolicy) Normalized() ReconnectPolicy {
+	def := DefaultReconnectPolicy()
+
+	delays := make([]int, 0, len(p.RetryDelaysSec))
+	for _, d := range p.RetryDelaysSec {
+		if d > 0 {
+			delays = append(delays, d)
+		}
+	}
+	if len(delays) == 0 {
+		delays = def.RetryDelaysSec
+	}
+	p.RetryDelaysSec = delays
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.HealthIntervalSec <= 0 {
+		p.HealthIntervalSec = def.HealthIntervalSec
+	}
+	if p.HealthMaxIntervalSec <= 0 {
+		p.HealthMaxIntervalSec = def.HealthMaxIntervalSec
+	}
+	if p.HealthMaxIntervalSec < p.HealthIntervalSec {
+		p.HealthMaxIntervalSec = p.HealthIntervalSec
+	}
+	return p
+}
+
+// NextDelay 第attempt次重连(从1开始)前应等待的时长；
+// 超过MaxAttempts时ok为false，调用方应结束本轮调度。
+// 调用方须先经Normalized处理。
+func (p ReconnectPolicy) NextDelay(attempt int) (delay time.Duration, ok bool) {
+	if attempt < 1 || attempt > p.MaxAttempts || len(p.RetryDelaysSec) == 0 {
+		return 0, false
+	}
+	idx := attempt - 1
+	if idx >= len(p.RetryDelaysSec) {
+		idx = len(p.RetryDelaysSec) - 1
+	}
+	return time.Duration(p.RetryDelaysSec[idx]) * time.Second, true
+}
+
+// HealthInterval 健康检查基础间隔
+func (p ReconnectPolicy) HealthInterval() time.Duration {
+	return time.Duration(p.HealthIntervalSec) * time.Second
+}
+
+// HealthMaxInterval 健康检查退避后的最大间隔
+func (p ReconnectPolicy) HealthMaxInterval() time.Duration {
+	return time.Duration(p.HealthMaxIntervalSec) * time.Second
 }
 
 // Logger 日志记录器接口
@@ -99,6 +287,22 @@ type Logger interface {
 	GetLogDir() string
 }
 
+// Clock 时间源抽象，覆盖Now/After/Sleep三类时间驱动调用。
+// 重连退避、健康监控、防抖等逻辑通过它取时间，
+// 生产代码使用SystemClock，测试注入可手动推进的假时钟确定性地驱动。
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+}
+
+// SystemClock 直接转发到time包的真实时钟
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time                         { return time.Now() }
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (SystemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
 // GearCommands 预设挡位命令
 var GearCommands = map[string][]GearCommand{
 	"静音": {
@@ -123,6 +327,14 @@ var GearCommands = map[string][]GearCommand{
 	},
 }
 
+// GearRank 挡位从低到高的顺序，用于与设备上报的MaxGear比较
+var GearRank = map[string]int{
+	"静音": 1,
+	"标准": 2,
+	"强劲": 3,
+	"超频": 4,
+}
+
 // GetDefaultFanCurve 获取默认风扇曲线
 func GetDefaultFanCurve() []FanCurvePoint {
 	return []FanCurvePoint{
@@ -146,28 +358,51 @@ func GetDefaultFanCurve() []FanCurvePoint {
 // GetDefaultConfig 获取默认配置
 func GetDefaultConfig(isAutoStart bool) AppConfig {
 	return AppConfig{
-		AutoControl:             false,
-		FanCurve:                GetDefaultFanCurve(),
-		GearLight:               true,
-		PowerOnStart:            false,
-		WindowsAutoStart:        false,
-		SmartStartStop:          "off",
-		Brightness:              100,
-		TempUpdateRate:          2,
-		TempSampleCount:         1,
-		ConfigPath:              "",
-		ManualGear:              "标准",
-		ManualLevel:             "中",
-		DebugMode:               false,
-		GuiMonitoring:           true,
-		CustomSpeedEnabled:      false,
-		CustomSpeedRPM:          2000,
-		IgnoreDeviceOnReconnect: true, // 默认开启，防止断连后误判用户手动切换
+		AutoControl:              false,
+		FanCurve:                 GetDefaultFanCurve(),
+		CurveMinTempGap:          3,
+		CurveMinRPMDelta:         100,
+		GearLight:                true,
+		PowerOnStart:             false,
+		WindowsAutoStart:         false,
+		SmartStartStop:           "off",
+		Brightness:               100,
+		TempUpdateRate:           2,
+		AdaptiveSampling:         false,
+		TempUpdateRateMin:        1,
+		TempUpdateRateMax:        5,
+		TempSampleCount:          1,
+		ConfigPath:               "",
+		ManualGear:               "标准",
+		ManualLevel:              "中",
+		DebugMode:                false,
+		GuiMonitoring:            true,
+		CustomSpeedEnabled:       false,
+		CustomSpeedRPM:           2000,
+		IgnoreDeviceOnReconnect:  true, // 默认开启，防止断连后误判用户手动切换
+		GuiHangPolicy:            "log",
+		GuiHangTimeoutSec:        60,
+		SharedMemoryTelemetry:    false,
+		OverlayOSD:               false,
+		StatusServerEnabled:      false,
+		StatusServerPort:         38502,
+		StatusServerRateSec:      2,
+		MaxBoostDurationSec:      120,
+		ThermalAlertBreakthrough: true, // 满速仍降频属于需要立即知道的硬件问题
+		TrayIconTheme:            "default",
+		LogLanguage:              "zh-CN",
+		FirmwareWatchdog:         false, // 旧固件会忽略未知命令，仍默认关闭避免误导
+
+		OBSConfig: &OBSConfig{
+			Enabled: false,
+			Host:    "127.0.0.1:4455",
+		},
 		RGBConfig: &RGBConfig{
-			Mode:       "smart",
-			Colors:     []RGBColorConfig{{R: 0, G: 0, B: 255}, {R: 255, G: 0, B: 0}, {R: 0, G: 255, B: 0}},
-			Speed:      "medium",
-			Brightness: 100,
+			Mode:            "smart",
+			Colors:          []RGBColorConfig{{R: 0, G: 0, B: 255}, {R: 255, G: 0, B: 0}, {R: 0, G: 255, B: 0}},
+			Speed:           "medium",
+			Brightness:      100,
+			SmartThresholds: append([]int(nil), DefaultSmartThresholds...),
 		},
 	}
 }
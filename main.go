@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/TIANLI0/BS2PRO-Controller/internal/tray"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
@@ -18,6 +20,20 @@ var assets embed.FS
 //go:embed build/windows/icon.ico
 var iconData []byte
 
+// 托盘状态图标变体，由 scripts/trayicons 从基础图标生成
+var (
+	//go:embed build/windows/tray/disconnected.ico
+	iconDisconnected []byte
+	//go:embed build/windows/tray/alert.ico
+	iconAlert []byte
+	//go:embed build/windows/tray/boost.ico
+	iconBoost []byte
+	//go:embed build/windows/tray/alert_cb.ico
+	iconAlertCB []byte
+	//go:embed build/windows/tray/boost_cb.ico
+	iconBoostCB []byte
+)
+
 // 获取WebView2用户数据目录路径，隔离缓存以便卸载时干净清理
 func getWebView2DataPath() string {
 	appData, err := os.UserConfigDir()
@@ -28,8 +44,26 @@ func getWebView2DataPath() string {
 	return filepath.Join(appData, "BS2PRO-Controller")
 }
 
+// parseArgValue 从命令行参数中提取 prefix= 形式的值（如 --view=curve）
+func parseArgValue(args []string, prefix string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			return strings.TrimPrefix(arg, prefix)
+		}
+	}
+	return ""
+}
+
 func main() {
 	app := NewApp(iconData)
+	app.pendingView = parseArgValue(os.Args[1:], "--view=")
+	app.pendingAction = parseArgValue(os.Args[1:], "--action=")
+	// 远程核心模式：--core=host:port 指定远程核心端点，--core-token= 携带访问令牌。
+	// 未传参时回落到GUI本地设置文件，两者都为空则走本机管道
+	app.remoteCoreAddr = parseArgValue(os.Args[1:], "--core=")
+	app.remoteCoreToken = parseArgValue(os.Args[1:], "--core-token=")
+	app.trayIcons = tray.IconSet{Connected: iconData, Disconnected: iconDisconnected, Alert: iconAlert, Boost: iconBoost}
+	app.trayIconsCB = tray.IconSet{Connected: iconData, Disconnected: iconDisconnected, Alert: iconAlertCB, Boost: iconBoostCB}
 
 	// 检测是否为开机自启动模式
 	isAutoStart := false
@@ -62,7 +96,15 @@ func main() {
 						break
 					}
 				}
-				if !hasAutostart {
+				// toast激活重启进程携带 --action= 时把动作回传核心，不强行弹窗
+				if action := parseArgValue(secondInstanceData.Args, "--action="); action != "" {
+					go app.TriggerNotificationAction(action)
+					return
+				}
+				// 第二实例携带 --view= 时显示窗口并跳转到目标页面
+				if view := parseArgValue(secondInstanceData.Args, "--view="); view != "" {
+					app.NavigateTo(view)
+				} else if !hasAutostart {
 					app.ShowWindow()
 				}
 			},
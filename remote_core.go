@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/config"
+)
+
+// remoteCoreFileName 远程核心连接设置的持久化文件（与core_path.txt同目录）。
+// 该设置属于GUI本端，不经核心配置下发——连哪台核心本身就不该由某台核心决定。
+const remoteCoreFileName = "remote_core.json"
+
+// remoteCoreSettings GUI本地的远程核心连接设置
+type remoteCoreSettings struct {
+	Addr  string `json:"addr"`  // 远程核心地址(host:port)，空表示走本机管道
+	Token string `json:"token"` // 远程访问令牌，与远程核心配置的RemoteAccessToken一致
+}
+
+// remoteCoreFilePath 远程核心设置文件的完整路径
+func remoteCoreFilePath() string {
+	programData := os.Getenv("PROGRAMDATA")
+	if programData == "" {
+		return filepath.Join(config.GetInstallDir(), "config", remoteCoreFileName)
+	}
+	return filepath.Join(programData, "BS2PRO-Controller", remoteCoreFileName)
+}
+
+// loadRemoteCoreSettings 读取持久化的远程核心设置，文件不存在或损坏时返回零值
+func loadRemoteCoreSettings() remoteCoreSettings {
+	var settings remoteCoreSettings
+	data, err := os.ReadFile(remoteCoreFilePath())
+	if err != nil {
+		return settings
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		guiLogger.Errorf("解析远程核心设置失败: %v", err)
+		return remoteCoreSettings{}
+	}
+	return settings
+}
+
+// saveRemoteCoreSettings 持久化远程核心设置，addr为空时直接删除设置文件
+func saveRemoteCoreSettings(settings remoteCoreSettings) error {
+	filePath := remoteCoreFilePath()
+	if settings.Addr == "" {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// SetRemoteCore 保存远程核心连接设置（供前端设置页调用）。
+// 传空地址恢复为本机管道模式。设置在下次启动时生效，避免在线切换导致状态错乱。
+func (a *App) SetRemoteCore(addr, token string) error {
+	if err := saveRemoteCoreSettings(remoteCoreSettings{Addr: addr, Token: token}); err != nil {
+		guiLogger.Errorf("保存远程核心设置失败: %v", err)
+		return err
+	}
+	guiLogger.Infof("远程核心设置已保存: %q（重启GUI后生效）", addr)
+	return nil
+}
+
+// GetRemoteCoreAddr 返回当前生效的远程核心地址，本机模式返回空字符串
+func (a *App) GetRemoteCoreAddr() string {
+	return a.remoteCoreAddr
+}
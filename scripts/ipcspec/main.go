@@ -0,0 +1,34 @@
+// IPC协议规范生成器：把 internal/ipc 中注册的请求/事件描述导出为JSON，
+// 供CLI、远程客户端等外部集成使用。
+//
+//	go run scripts/ipcspec/main.go -out docs/ipc-protocol.json
+//
+// 由 internal/ipc/spec.go 的 go:generate 指令调用，注册表变更后重新生成即可。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/ipc"
+)
+
+func main() {
+	out := flag.String("out", "docs/ipc-protocol.json", "输出文件路径")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(ipc.Spec(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "序列化协议规范失败: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "写入 %s 失败: %v\n", *out, err)
+		os.Exit(1)
+	}
+	fmt.Printf("已生成 %s\n", *out)
+}
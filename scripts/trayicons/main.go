@@ -0,0 +1,156 @@
+// 托盘状态图标生成器：从 build/windows/icon.ico 派生各状态变体，
+// 输出到 build/windows/tray/ 供主程序embed。
+//
+//	go run scripts/trayicons/main.go
+//
+// 标准套：断开=去饱和置灰、告警=红色、狂暴=橙色；
+// 色盲友好套：告警=蓝色、狂暴=黄色（红绿色弱下仍可区分），置灰共用。
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// tint 把图标按亮度重新着色：保留alpha与明暗层次，色相替换为目标色
+type tint struct {
+	r, g, b float64
+}
+
+var variants = []struct {
+	name string
+	t    tint
+}{
+	{"disconnected", tint{0.55, 0.55, 0.55}}, // 置灰并压暗
+	{"alert", tint{1.0, 0.25, 0.2}},          // 红色告警
+	{"boost", tint{1.0, 0.6, 0.1}},           // 橙色狂暴
+	{"alert_cb", tint{0.2, 0.45, 1.0}},       // 色盲友好: 蓝色告警
+	{"boost_cb", tint{1.0, 0.85, 0.1}},       // 色盲友好: 黄色狂暴
+}
+
+func main() {
+	src := filepath.Join("build", "windows", "icon.ico")
+	outDir := filepath.Join("build", "windows", "tray")
+
+	raw, err := os.ReadFile(src)
+	if err != nil {
+		fmt.Println("读取基础图标失败:", err)
+		os.Exit(1)
+	}
+	frame, err := extractPNGFrame(raw)
+	if err != nil {
+		fmt.Println("解析ICO失败:", err)
+		os.Exit(1)
+	}
+	img, err := png.Decode(bytes.NewReader(frame))
+	if err != nil {
+		fmt.Println("解码PNG帧失败:", err)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		fmt.Println("创建输出目录失败:", err)
+		os.Exit(1)
+	}
+
+	for _, v := range variants {
+		tinted := applyTint(img, v.t)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, tinted); err != nil {
+			fmt.Println("编码PNG失败:", err)
+			os.Exit(1)
+		}
+		ico := wrapICO(buf.Bytes(), img.Bounds().Dx(), img.Bounds().Dy())
+		out := filepath.Join(outDir, v.name+".ico")
+		if err := os.WriteFile(out, ico, 0o644); err != nil {
+			fmt.Println("写入失败:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已生成 %s (%d字节)\n", out, len(ico))
+	}
+}
+
+// extractPNGFrame 取ICO中第一个PNG帧（现代图标均为单PNG帧）
+func extractPNGFrame(raw []byte) ([]byte, error) {
+	if len(raw) < 22 {
+		return nil, fmt.Errorf("文件过短")
+	}
+	count := binary.LittleEndian.Uint16(raw[4:6])
+	if count == 0 {
+		return nil, fmt.Errorf("不含图像帧")
+	}
+	size := binary.LittleEndian.Uint32(raw[14:18])
+	offset := binary.LittleEndian.Uint32(raw[18:22])
+	if int(offset)+int(size) > len(raw) {
+		return nil, fmt.Errorf("帧数据越界")
+	}
+	frame := raw[offset : offset+size]
+	if !bytes.HasPrefix(frame, []byte("\x89PNG")) {
+		return nil, fmt.Errorf("首帧不是PNG格式")
+	}
+	return frame, nil
+}
+
+// applyTint 按亮度重新着色
+func applyTint(img image.Image, t tint) image.Image {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			// Rec.601亮度
+			lum := 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+			out.SetNRGBA(x, y, color.NRGBA{
+				R: clamp8(lum * t.r),
+				G: clamp8(lum * t.g),
+				B: clamp8(lum * t.b),
+				A: c.A,
+			})
+		}
+	}
+	return out
+}
+
+func clamp8(v float64) uint8 {
+	if v > 255 {
+		return 255
+	}
+	if v < 0 {
+		return 0
+	}
+	return uint8(v)
+}
+
+// wrapICO 把单个PNG包装为ICO容器
+func wrapICO(pngData []byte, w, h int) []byte {
+	var buf bytes.Buffer
+	// ICONDIR
+	binary.Write(&buf, binary.LittleEndian, uint16(0)) // 保留
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // 类型: icon
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // 帧数
+	// ICONDIRENTRY
+	buf.WriteByte(dimByte(w))
+	buf.WriteByte(dimByte(h))
+	buf.WriteByte(0)                                              // 调色板
+	buf.WriteByte(0)                                              // 保留
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // 平面数
+	binary.Write(&buf, binary.LittleEndian, uint16(32))           // 位深
+	binary.Write(&buf, binary.LittleEndian, uint32(len(pngData))) // 帧大小
+	binary.Write(&buf, binary.LittleEndian, uint32(6+16))         // 帧偏移
+	buf.Write(pngData)
+	return buf.Bytes()
+}
+
+// dimByte ICO目录里256用0表示
+func dimByte(d int) byte {
+	if d >= 256 {
+		return 0
+	}
+	return byte(d)
+}